@@ -0,0 +1,278 @@
+// Package auth autentica os clientes da API e controla o acesso às rotas por escopo
+//
+// Os clientes se identificam com chaves emitidas pela própria API (cabeçalho X-API-Key ou
+// parâmetro api_key), guardadas apenas como hash, ou com tokens JWT emitidos pela plataforma
+// (Authorization: Bearer); as rotas de administração também aceitam o ADMIN_TOKEN. Cada rota
+// exige um escopo (temperature, forecast ou admin) e as sondas de saúde ficam de fora da
+// autenticação.
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scope é uma permissão concedida a um cliente
+type Scope string
+
+const (
+	// ScopeTemperature libera as consultas de temperatura e conforto térmico
+	ScopeTemperature Scope = "temperature"
+	// ScopeForecast libera as consultas de previsão
+	ScopeForecast Scope = "forecast"
+	// ScopeAdmin libera as rotas /admin, inclusive a gestão das chaves
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes são todos os escopos aceitos, na ordem em que são exibidos
+var Scopes = []Scope{ScopeTemperature, ScopeForecast, ScopeAdmin}
+
+// ParseScopes valida os escopos informados, descartando os repetidos
+func ParseScopes(values []string) ([]Scope, error) {
+	requested := make(map[Scope]bool, len(values))
+	for _, value := range values {
+		scope := Scope(strings.ToLower(strings.TrimSpace(value)))
+		if scope == "" {
+			continue
+		}
+		if !scope.valid() {
+			return nil, fmt.Errorf("unknown scope %q (use temperature, forecast or admin)", value)
+		}
+		requested[scope] = true
+	}
+	if len(requested) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	scopes := make([]Scope, 0, len(requested))
+	for _, scope := range Scopes {
+		if requested[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// valid informa se o escopo é conhecido
+func (s Scope) valid() bool {
+	for _, scope := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Métodos de autenticação registrados no Principal
+const (
+	MethodAPIKey     = "api_key"
+	MethodAdminToken = "admin_token"
+	MethodJWT        = "jwt"
+)
+
+// Principal é o cliente autenticado de uma requisição
+type Principal struct {
+	// ID identifica o cliente sem expor a credencial (ex: ID da chave)
+	ID string
+	// Name é o nome informado na emissão da credencial
+	Name   string
+	Method string
+	Scopes []Scope
+}
+
+// HasScope informa se o cliente tem o escopo
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrNoCredentials indica que a requisição não traz credenciais do tipo verificado
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials indica credenciais desconhecidas, revogadas ou malformadas
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator identifica o cliente a partir das credenciais da requisição
+type Authenticator interface {
+	// Authenticate retorna ErrNoCredentials quando a requisição não traz credenciais deste tipo
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain tenta cada autenticador em ordem; o primeiro que encontra credenciais decide
+type Chain []Authenticator
+
+// Authenticate retorna o resultado do primeiro autenticador que encontra credenciais na requisição
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// StaticToken autentica o token fixo de administração (Authorization: Bearer <ADMIN_TOKEN>)
+type StaticToken struct {
+	token     []byte
+	principal Principal
+}
+
+// NewStaticToken cria o autenticador do token com todos os escopos informados
+func NewStaticToken(token string, scopes ...Scope) *StaticToken {
+	return &StaticToken{
+		token:     []byte(token),
+		principal: Principal{ID: "admin-token", Name: "ADMIN_TOKEN", Method: MethodAdminToken, Scopes: scopes},
+	}
+}
+
+// Authenticate compara o bearer token em tempo constante
+// Outro bearer token não é recusado aqui, para que os próximos autenticadores possam verificá-lo
+func (s *StaticToken) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok || len(s.token) == 0 || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+		return nil, ErrNoCredentials
+	}
+	principal := s.principal
+	return &principal, nil
+}
+
+// bearerToken extrai o token do cabeçalho "Authorization: Bearer <token>"
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
+
+// Config configura a autenticação dos clientes
+type Config struct {
+	// RequireAPIKey exige chave de cliente com o escopo da rota nas consultas
+	RequireAPIKey bool
+	// KeysFile guarda as chaves emitidas (apenas os hashes); vazio mantém as chaves só em memória
+	KeysFile string
+	JWT      JWTConfig
+}
+
+// Enabled informa se as consultas exigem autenticação (chave de cliente ou token JWT)
+func (c Config) Enabled() bool {
+	return c.RequireAPIKey || c.JWT.Enabled()
+}
+
+// JWTConfig configura a validação dos tokens JWT
+type JWTConfig struct {
+	// Issuer é o emissor exigido na claim iss
+	Issuer string
+	// Audience é a audiência exigida na claim aud
+	Audience string
+	// JWKSURL publica as chaves dos tokens RS256/ES256 (ex: .../.well-known/jwks.json)
+	JWKSURL string
+	// HMACSecret é o segredo dos tokens HS256
+	HMACSecret string
+	// JWKSCacheTTL é o tempo de vida das chaves do JWKS em cache
+	JWKSCacheTTL time.Duration
+	// Leeway é a tolerância de relógio na validação de exp e nbf
+	Leeway time.Duration
+	// ScopesClaim é a claim com os escopos ou papéis do cliente (ex: scope, roles, realm_access.roles)
+	ScopesClaim string
+	// ScopeMap converte os valores da claim nos escopos da API; nil aceita os nomes dos escopos
+	ScopeMap map[string][]Scope
+}
+
+// Enabled informa se os tokens JWT são aceitos
+func (c JWTConfig) Enabled() bool {
+	return c.JWKSURL != "" || c.HMACSecret != ""
+}
+
+// DefaultConfig retorna a configuração padrão: consultas abertas e chaves em memória
+func DefaultConfig() Config {
+	return Config{
+		JWT: JWTConfig{
+			JWKSCacheTTL: time.Hour,
+			Leeway:       30 * time.Second,
+			ScopesClaim:  "scope",
+		},
+	}
+}
+
+// ConfigFrom lê API_KEY_AUTH, API_KEYS_FILE e JWT_*
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	cfg.KeysFile = strings.TrimSpace(getenv("API_KEYS_FILE"))
+	if raw := strings.TrimSpace(getenv("API_KEY_AUTH")); raw != "" {
+		required, err := strconv.ParseBool(raw)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid API_KEY_AUTH: %q", raw)
+		}
+		cfg.RequireAPIKey = required
+	}
+
+	jwtCfg := &cfg.JWT
+	jwtCfg.Issuer = strings.TrimSpace(getenv("JWT_ISSUER"))
+	jwtCfg.Audience = strings.TrimSpace(getenv("JWT_AUDIENCE"))
+	jwtCfg.JWKSURL = strings.TrimSpace(getenv("JWT_JWKS_URL"))
+	jwtCfg.HMACSecret = getenv("JWT_HMAC_SECRET")
+	if raw := strings.TrimSpace(getenv("JWT_SCOPES_CLAIM")); raw != "" {
+		jwtCfg.ScopesClaim = raw
+	}
+	for name, target := range map[string]*time.Duration{
+		"JWT_JWKS_CACHE_TTL": &jwtCfg.JWKSCacheTTL,
+		"JWT_LEEWAY":         &jwtCfg.Leeway,
+	} {
+		raw := strings.TrimSpace(getenv(name))
+		if raw == "" {
+			continue
+		}
+		value, err := time.ParseDuration(raw)
+		if err != nil || value < 0 {
+			return cfg, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		*target = value
+	}
+	if raw := strings.TrimSpace(getenv("JWT_SCOPE_MAP")); raw != "" {
+		scopeMap, err := parseScopeMap(raw)
+		if err != nil {
+			return cfg, err
+		}
+		jwtCfg.ScopeMap = scopeMap
+	}
+
+	if jwtCfg.JWKSURL != "" {
+		if u, err := url.Parse(jwtCfg.JWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("invalid JWT_JWKS_URL: %q", jwtCfg.JWKSURL)
+		}
+	}
+	if jwtCfg.Enabled() && (jwtCfg.Issuer == "" || jwtCfg.Audience == "") {
+		return cfg, errors.New("JWT authentication requires JWT_ISSUER and JWT_AUDIENCE")
+	}
+	return cfg, nil
+}
+
+// parseScopeMap lê os pares valor=escopo separados por vírgula (ex: weather.read=temperature,ops=admin)
+// Um valor pode aparecer mais de uma vez para conceder vários escopos
+func parseScopeMap(raw string) (map[string][]Scope, error) {
+	scopeMap := make(map[string][]Scope)
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		value, name, ok := strings.Cut(pair, "=")
+		value, scope := strings.TrimSpace(value), Scope(strings.TrimSpace(name))
+		if !ok || value == "" || !scope.valid() {
+			return nil, fmt.Errorf("invalid JWT_SCOPE_MAP: %q (use value=scope with temperature, forecast or admin)", pair)
+		}
+		scopeMap[value] = append(scopeMap[value], scope)
+	}
+	return scopeMap, nil
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []Scope
+		err      string
+	}{
+		{name: "Sorted and deduplicated", values: []string{"admin", " Temperature ", "admin"}, expected: []Scope{ScopeTemperature, ScopeAdmin}},
+		{name: "Forecast", values: []string{"forecast"}, expected: []Scope{ScopeForecast}},
+		{name: "Unknown scope", values: []string{"temperature", "write"}, err: `unknown scope "write"`},
+		{name: "Empty", values: []string{" "}, err: "at least one scope is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, err := ParseScopes(tt.values)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, scopes)
+		})
+	}
+}
+
+func TestChain_Authenticate(t *testing.T) {
+	store, err := NewKeyStore("")
+	require.NoError(t, err)
+	key, _, err := store.Create("mobile", []Scope{ScopeTemperature})
+	require.NoError(t, err)
+	chain := Chain{NewStaticToken("admin-secret", ScopeAdmin), store}
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		expected string
+		err      error
+	}{
+		{name: "Admin token", header: "Authorization", value: "Bearer admin-secret", expected: MethodAdminToken},
+		{name: "API key", header: HeaderAPIKey, value: key, expected: MethodAPIKey},
+		{name: "Unknown API key", header: HeaderAPIKey, value: KeyPrefix + "unknown", err: ErrInvalidCredentials},
+		{name: "Other bearer token", header: "Authorization", value: "Bearer other", err: ErrNoCredentials},
+		{name: "No credentials", err: ErrNoCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/temperature/01310100", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			principal, err := chain.Authenticate(req)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, principal.Method)
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	store, err := NewKeyStore("")
+	require.NoError(t, err)
+	temperatureKey, _, err := store.Create("mobile", []Scope{ScopeTemperature})
+	require.NoError(t, err)
+	forecastKey, _, err := store.Create("dashboard", []Scope{ScopeForecast})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/temperature/:cep", Require(store, ScopeTemperature), func(c *gin.Context) {
+		principal, ok := PrincipalFrom(c)
+		require.True(t, ok)
+		c.String(http.StatusOK, principal.Name)
+	})
+
+	tests := []struct {
+		name         string
+		path         string
+		key          string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Header", path: "/temperature/01310100", key: temperatureKey, expectedCode: http.StatusOK, expectedBody: "mobile"},
+		{name: "Query", path: "/temperature/01310100?api_key=" + temperatureKey, expectedCode: http.StatusOK, expectedBody: "mobile"},
+		{name: "Missing scope", path: "/temperature/01310100", key: forecastKey, expectedCode: http.StatusForbidden, expectedBody: "forbidden"},
+		{name: "Unknown key", path: "/temperature/01310100", key: "wca_unknown", expectedCode: http.StatusUnauthorized, expectedBody: "unauthorized"},
+		{name: "No key", path: "/temperature/01310100", expectedCode: http.StatusUnauthorized, expectedBody: "unauthorized"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+			if tt.expectedCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestConfigFrom(t *testing.T) {
+	cfg, err := ConfigFrom(func(string) string { return "" })
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+
+	env := map[string]string{"API_KEY_AUTH": "true", "API_KEYS_FILE": "/var/lib/weather/api-keys.json"}
+	cfg, err = ConfigFrom(func(key string) string { return env[key] })
+	require.NoError(t, err)
+	assert.True(t, cfg.RequireAPIKey)
+	assert.Equal(t, "/var/lib/weather/api-keys.json", cfg.KeysFile)
+
+	env["API_KEY_AUTH"] = "sometimes"
+	_, err = ConfigFrom(func(key string) string { return env[key] })
+	assert.EqualError(t, err, `invalid API_KEY_AUTH: "sometimes"`)
+}
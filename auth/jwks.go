@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksMinRefresh limita as buscas do JWKS provocadas por kid desconhecido ou falha anterior
+const jwksMinRefresh = time.Minute
+
+// jwksMaxSize limita o tamanho da resposta do JWKS
+const jwksMaxSize = 1 << 20
+
+// HTTPClientInterface define o contrato do client HTTP usado para buscar o JWKS
+type HTTPClientInterface interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// JWKS busca e mantém em cache as chaves públicas (RSA e EC P-256) que assinam os tokens
+// O conjunto é buscado de novo quando o cache vence ou quando chega um kid desconhecido
+// (rotação de chaves), no máximo uma vez por minuto; com o servidor indisponível, as chaves já
+// conhecidas continuam valendo
+// A busca é feita fora do lock e compartilhada pelas requisições que chegam durante ela; as chaves
+// em cache são servidas sem esperar a atualização
+type JWKS struct {
+	client HTTPClientInterface
+	url    string
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	inflight    *jwksFetch
+}
+
+// jwksFetch é uma busca do JWKS em andamento; done é fechado quando ela termina
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKS cria o cache das chaves publicadas na URL
+func NewJWKS(client HTTPClientInterface, url string, ttl time.Duration) *JWKS {
+	return NewJWKSWithClock(client, url, ttl, time.Now)
+}
+
+// NewJWKSWithClock cria o cache com o relógio informado (usado nos testes)
+func NewJWKSWithClock(client HTTPClientInterface, url string, ttl time.Duration, now func() time.Time) *JWKS {
+	return &JWKS{client: client, url: url, ttl: ttl, now: now}
+}
+
+// Key retorna a chave pública do kid
+func (j *JWKS) Key(ctx context.Context, kid string) (any, error) {
+	j.mu.Lock()
+	now := j.now()
+	key, known := j.lookup(kid)
+	fresh := !j.fetchedAt.IsZero() && now.Sub(j.fetchedAt) < j.ttl
+	if known && fresh {
+		j.mu.Unlock()
+		return key, nil
+	}
+	fetch := j.startRefresh(ctx, now, false)
+	j.mu.Unlock()
+
+	// Chave conhecida com o cache vencido: a atualização segue em segundo plano
+	if known {
+		return key, nil
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := fetch.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	key, known = j.lookup(kid)
+	j.mu.Unlock()
+	if !known {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// Check é a verificação de prontidão: falha enquanto nenhuma chave foi carregada
+func (j *JWKS) Check(ctx context.Context) error {
+	j.mu.Lock()
+	if len(j.keys) > 0 {
+		j.mu.Unlock()
+		return nil
+	}
+	fetch := j.startRefresh(ctx, j.now(), true)
+	j.mu.Unlock()
+	return fetch.wait(ctx)
+}
+
+// startRefresh inicia a busca do JWKS, ou retorna a que está em andamento; sem force, retorna nil
+// se a última tentativa foi há menos de um minuto (chamado com o lock)
+// A busca não é cancelada junto com a requisição que a iniciou, pois outras podem estar esperando;
+// o timeout do client HTTP a limita
+func (j *JWKS) startRefresh(ctx context.Context, now time.Time, force bool) *jwksFetch {
+	if j.inflight != nil {
+		return j.inflight
+	}
+	if !force && now.Sub(j.attemptedAt) < jwksMinRefresh {
+		return nil
+	}
+	j.attemptedAt = now
+	fetch := &jwksFetch{done: make(chan struct{})}
+	j.inflight = fetch
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		keys, err := j.fetch(ctx)
+		j.mu.Lock()
+		if err == nil {
+			j.keys = keys
+			j.fetchedAt = now
+		} else if len(j.keys) > 0 {
+			slog.WarnContext(ctx, "Erro ao atualizar JWKS; usando as chaves em cache", "url", j.url, "error", err)
+		}
+		j.inflight = nil
+		j.mu.Unlock()
+
+		fetch.err = err
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+// wait espera o fim da busca (ou o cancelamento da requisição) e retorna o erro dela
+func (f *jwksFetch) wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lookup procura a chave pelo kid; tokens sem kid são aceitos quando o JWKS tem uma única chave
+// (chamado com o lock)
+func (j *JWKS) lookup(kid string) (any, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// jwk é uma chave do JWKS (RFC 7517); apenas os campos de RSA e EC são lidos
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch busca o JWKS e retorna as chaves de assinatura utilizáveis (chamado sem o lock)
+func (j *JWKS) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating jwks request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching jwks: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error decoding jwks: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Chaves de tipos não suportados (ex: OKP) não impedem o uso das demais
+			slog.DebugContext(ctx, "Chave do JWKS ignorada", "kid", k.Kid, "kty", k.Kty, "error", err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey converte a JWK na chave pública correspondente
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid EC point")
+		}
+		// Confere se o ponto pertence à curva antes de usá-lo
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, errors.New("invalid EC point")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTAuthenticator autentica tokens JWT (Authorization: Bearer <token>) emitidos pela plataforma
+// Tokens HS256 são verificados com o segredo compartilhado e tokens RS256/ES256 com as chaves do
+// JWKS; emissor, audiência e expiração são obrigatórios
+type JWTAuthenticator struct {
+	cfg    JWTConfig
+	jwks   *JWKS
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator cria o autenticador; jwks pode ser nil quando só HS256 é aceito
+func NewJWTAuthenticator(cfg JWTConfig, jwks *JWKS) *JWTAuthenticator {
+	var methods []string
+	if cfg.HMACSecret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if jwks != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+	return &JWTAuthenticator{
+		cfg:  cfg,
+		jwks: jwks,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(methods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+	}
+}
+
+// Authenticate valida o bearer token e converte as claims no cliente autenticado
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	raw, ok := bearerToken(r)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (any, error) {
+		return a.key(r.Context(), token)
+	})
+	if err == nil {
+		if subject, _ := claims.GetSubject(); subject == "" {
+			err = errors.New("token has no sub claim")
+		}
+	}
+	if err != nil {
+		slog.DebugContext(r.Context(), "Token JWT recusado", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	return &Principal{ID: subject, Name: clientName(claims), Method: MethodJWT, Scopes: a.scopes(claims)}, nil
+}
+
+// key escolhe a chave de verificação pelo algoritmo do token
+// O algoritmo já foi restrito por WithValidMethods; a chave do JWKS precisa ser do mesmo tipo
+func (a *JWTAuthenticator) key(ctx context.Context, token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return []byte(a.cfg.HMACSecret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		kid, _ := token.Header["kid"].(string)
+		return a.jwks.Key(ctx, kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// scopes converte os valores da claim de escopos (JWT_SCOPES_CLAIM) nos escopos da API
+// Sem JWT_SCOPE_MAP, valem os valores iguais aos nomes dos escopos (temperature, forecast, admin)
+func (a *JWTAuthenticator) scopes(claims jwt.MapClaims) []Scope {
+	granted := make(map[Scope]bool)
+	for _, value := range claimValues(claims, a.cfg.ScopesClaim) {
+		if a.cfg.ScopeMap == nil {
+			if scope := Scope(value); scope.valid() {
+				granted[scope] = true
+			}
+			continue
+		}
+		for _, scope := range a.cfg.ScopeMap[value] {
+			granted[scope] = true
+		}
+	}
+
+	var scopes []Scope
+	for _, scope := range Scopes {
+		if granted[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// claimValues lê uma claim de texto separado por espaços (ex: scope) ou lista (ex: roles)
+// Nomes com ponto acessam claims aninhadas (ex: realm_access.roles do Keycloak)
+func claimValues(claims jwt.MapClaims, name string) []string {
+	var value any = map[string]any(claims)
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// clientName identifica o cliente nos logs: azp ou client_id (client credentials) ou o sub
+func clientName(claims jwt.MapClaims) string {
+	for _, name := range []string{"azp", "client_id", "sub"} {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,380 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer     = "https://auth.internal.example.com"
+	testAudience   = "weather-cep-api"
+	testHMACSecret = "hmac-secret-with-at-least-32-bytes!!"
+)
+
+// jwksServer publica um JWKS local com as chaves cadastradas e conta as requisições
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []map[string]string
+	requests atomic.Int64
+}
+
+// newJWKSServer cria o servidor do JWKS
+func newJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// setKeys substitui as chaves publicadas (rotação)
+func (s *jwksServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+// b64 codifica um inteiro grande no formato das JWKs
+func b64(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// rsaJWK converte a chave pública RSA em JWK
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256", "n": b64(key.N), "e": b64(big.NewInt(int64(key.E)))}
+}
+
+// ecJWK converte a chave pública EC P-256 em JWK
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	x, y := make([]byte, 32), make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(x), "y": base64.RawURLEncoding.EncodeToString(y),
+	}
+}
+
+// validClaims são as claims de um token aceito, com a expiração em uma hora
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "service-account-1",
+		"azp":   "dashboard",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "openid temperature",
+	}
+}
+
+// sign assina as claims com o método, o kid e a chave informados
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// bearer cria uma requisição com o token no cabeçalho Authorization
+func bearer(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/temperature/01310100", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// testJWTConfig é a configuração com o emissor e a audiência dos testes
+func testJWTConfig(jwksURL, secret string) JWTConfig {
+	cfg := DefaultConfig().JWT
+	cfg.Issuer, cfg.Audience, cfg.JWKSURL, cfg.HMACSecret = testIssuer, testAudience, jwksURL, secret
+	return cfg
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t)
+	server.setKeys(rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey))
+	cfg := testJWTConfig(server.URL, testHMACSecret)
+	authenticator := NewJWTAuthenticator(cfg, NewJWKS(server.Client(), cfg.JWKSURL, cfg.JWKSCacheTTL))
+
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{name: "RS256", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())},
+		{name: "ES256", token: sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims())},
+		{name: "HS256", token: sign(t, jwt.SigningMethodHS256, "", []byte(testHMACSecret), validClaims())},
+		{name: "Audience list", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { c["aud"] = []string{"other", testAudience} }))},
+		{name: "Expired", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), err: "token is expired"},
+		{name: "Missing expiry", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { delete(c, "exp") })), err: "exp claim is required"},
+		{name: "Wrong issuer", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" })), err: "token has invalid issuer"},
+		{name: "Wrong audience", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { c["aud"] = "other-api" })), err: "token has invalid audience"},
+		{name: "Missing subject", token: sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, with(func(c jwt.MapClaims) { delete(c, "sub") })), err: "token has no sub claim"},
+		{name: "Wrong key", token: sign(t, jwt.SigningMethodRS256, "rsa-1", otherRSAKey, validClaims()), err: "signature is invalid"},
+		{name: "Wrong HMAC secret", token: sign(t, jwt.SigningMethodHS256, "", []byte("another-secret"), validClaims()), err: "signature is invalid"},
+		{name: "Key type mismatch", token: sign(t, jwt.SigningMethodRS256, "ec-1", rsaKey, validClaims()), err: "key is of invalid type"},
+		{name: "Algorithm not allowed", token: sign(t, jwt.SigningMethodHS512, "", []byte(testHMACSecret), validClaims()), err: "signing method HS512 is invalid"},
+		{name: "Unsigned", token: sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims()), err: "signing method none is invalid"},
+		{name: "Malformed", token: "not-a-jwt", err: "token is malformed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(bearer(tt.token))
+			if tt.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &Principal{ID: "service-account-1", Name: "dashboard", Method: MethodJWT, Scopes: []Scope{ScopeTemperature}}, principal)
+		})
+	}
+
+	_, err = authenticator.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestJWTAuthenticator_OnlyHMAC(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	authenticator := NewJWTAuthenticator(testJWTConfig("", testHMACSecret), nil)
+
+	_, err = authenticator.Authenticate(bearer(sign(t, jwt.SigningMethodHS256, "", []byte(testHMACSecret), validClaims())))
+	require.NoError(t, err)
+	// Sem JWKS, tokens RS256 são recusados antes de qualquer busca de chave
+	_, err = authenticator.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())))
+	assert.ErrorContains(t, err, "signing method RS256 is invalid")
+}
+
+func TestJWTAuthenticator_Scopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    string
+		scopeMap string
+		claims   jwt.MapClaims
+		expected []Scope
+	}{
+		{
+			name:     "Space separated scope claim",
+			claims:   jwt.MapClaims{"scope": "openid admin temperature unknown"},
+			expected: []Scope{ScopeTemperature, ScopeAdmin},
+		},
+		{
+			name:     "Mapped roles list",
+			claim:    "roles",
+			scopeMap: "weather.read=temperature,weather.read=forecast,platform-admin=admin",
+			claims:   jwt.MapClaims{"roles": []any{"weather.read", "temperature"}},
+			expected: []Scope{ScopeTemperature, ScopeForecast},
+		},
+		{
+			name:     "Nested claim",
+			claim:    "realm_access.roles",
+			scopeMap: "platform-admin=admin",
+			claims:   jwt.MapClaims{"realm_access": map[string]any{"roles": []any{"platform-admin"}}},
+			expected: []Scope{ScopeAdmin},
+		},
+		{
+			name:   "Missing claim",
+			claim:  "roles",
+			claims: jwt.MapClaims{"scope": "temperature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{
+				"JWT_ISSUER": testIssuer, "JWT_AUDIENCE": testAudience, "JWT_HMAC_SECRET": testHMACSecret,
+				"JWT_SCOPES_CLAIM": tt.claim, "JWT_SCOPE_MAP": tt.scopeMap,
+			}
+			cfg, err := ConfigFrom(func(key string) string { return env[key] })
+			require.NoError(t, err)
+
+			claims := validClaims()
+			delete(claims, "scope")
+			for name, value := range tt.claims {
+				claims[name] = value
+			}
+			principal, err := NewJWTAuthenticator(cfg.JWT, nil).Authenticate(bearer(sign(t, jwt.SigningMethodHS256, "", []byte(testHMACSecret), claims)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, principal.Scopes)
+		})
+	}
+}
+
+func TestJWKS_Key(t *testing.T) {
+	first, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	second, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t)
+	server.setKeys(ecJWK("key-1", &first.PublicKey), map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": "AAAA"})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	jwks := NewJWKSWithClock(server.Client(), server.URL, time.Hour, func() time.Time { return now })
+	ctx := context.Background()
+
+	require.NoError(t, jwks.Check(ctx))
+	key, err := jwks.Key(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, first.PublicKey.Equal(key))
+	assert.Equal(t, int64(1), server.requests.Load(), "keys are cached")
+
+	// Kid desconhecido (rotação) busca o JWKS de novo, no máximo uma vez por minuto
+	server.setKeys(ecJWK("key-1", &first.PublicKey), ecJWK("key-2", &second.PublicKey))
+	_, err = jwks.Key(ctx, "key-2")
+	assert.ErrorContains(t, err, `unknown key id "key-2"`)
+	now = now.Add(jwksMinRefresh)
+	key, err = jwks.Key(ctx, "key-2")
+	require.NoError(t, err)
+	assert.True(t, second.PublicKey.Equal(key))
+	assert.Equal(t, int64(2), server.requests.Load())
+	_, err = jwks.Key(ctx, "unknown")
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), server.requests.Load())
+
+	// Com o cache vencido e o servidor fora do ar, as chaves conhecidas continuam valendo
+	server.Close()
+	now = now.Add(2 * time.Hour)
+	key, err = jwks.Key(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, first.PublicKey.Equal(key))
+}
+
+func TestJWKS_Key_RefreshOutsideLock(t *testing.T) {
+	first, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	second, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t)
+	server.setKeys(ecJWK("key-1", &first.PublicKey))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	jwks := NewJWKSWithClock(server.Client(), server.URL, time.Hour, func() time.Time { return now })
+	ctx := context.Background()
+	require.NoError(t, jwks.Check(ctx))
+
+	// O servidor passa a segurar as respostas até release ser fechado
+	release := make(chan struct{})
+	var requests atomic.Int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(slow.Close)
+	jwks.url = slow.URL
+	server.setKeys(ecJWK("key-1", &first.PublicKey), ecJWK("key-2", &second.PublicKey))
+	now = now.Add(2 * time.Hour)
+
+	// Os kids desconhecidos esperam uma única busca, compartilhada
+	results := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := jwks.Key(ctx, "key-2")
+			results <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Durante a busca a chave em cache é servida sem esperar
+	key, err := jwks.Key(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, first.PublicKey.Equal(key))
+
+	// Quem desiste da requisição não espera a busca
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = jwks.Key(canceled, "key-2")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, <-results)
+	}
+	assert.Equal(t, int64(1), requests.Load())
+	key, err = jwks.Key(ctx, "key-2")
+	require.NoError(t, err)
+	assert.True(t, second.PublicKey.Equal(key))
+}
+
+func TestJWKS_Check_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewJWKS(server.Client(), server.URL, time.Hour).Check(context.Background())
+	assert.EqualError(t, err, "error fetching jwks: status 503")
+
+	empty := newJWKSServer(t)
+	err = NewJWKS(empty.Client(), empty.URL, time.Hour).Check(context.Background())
+	assert.EqualError(t, err, "jwks has no usable signing keys")
+}
+
+func TestConfigFrom_JWT(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		err  string
+	}{
+		{name: "Issuer and audience required", env: map[string]string{"JWT_JWKS_URL": "https://auth.example.com/jwks.json"}, err: "JWT authentication requires JWT_ISSUER and JWT_AUDIENCE"},
+		{name: "Invalid JWKS URL", env: map[string]string{"JWT_JWKS_URL": "auth.example.com/jwks.json"}, err: "invalid JWT_JWKS_URL"},
+		{name: "Invalid scope map", env: map[string]string{"JWT_SCOPE_MAP": "weather.read=read"}, err: "invalid JWT_SCOPE_MAP"},
+		{name: "Invalid leeway", env: map[string]string{"JWT_LEEWAY": "-1s"}, err: "invalid JWT_LEEWAY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConfigFrom(func(key string) string { return tt.env[key] })
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	env := map[string]string{"JWT_ISSUER": testIssuer, "JWT_AUDIENCE": testAudience, "JWT_JWKS_URL": "https://auth.example.com/jwks.json", "JWT_JWKS_CACHE_TTL": "10m"}
+	cfg, err := ConfigFrom(func(key string) string { return env[key] })
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.False(t, cfg.RequireAPIKey)
+	assert.Equal(t, 10*time.Minute, cfg.JWT.JWKSCacheTTL)
+	assert.Equal(t, "scope", cfg.JWT.ScopesClaim)
+}
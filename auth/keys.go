@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// KeyPrefix identifica as chaves emitidas pela API (ex: em varreduras de segredos vazados)
+	KeyPrefix = "wca_"
+	// HeaderAPIKey é o cabeçalho com a chave do cliente
+	HeaderAPIKey = "X-API-Key"
+	// QueryAPIKey é o parâmetro de query alternativo ao cabeçalho
+	QueryAPIKey = "api_key"
+)
+
+// ErrKeyNotFound indica uma chave inexistente ou já revogada
+var ErrKeyNotFound = errors.New("api key not found")
+
+// Key é uma chave de cliente emitida; o valor da chave não é guardado, apenas o SHA-256
+type Key struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []Scope   `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// keysFile é o formato do arquivo de chaves (API_KEYS_FILE)
+type keysFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// KeyStore guarda as chaves de clientes e autentica as requisições que as apresentam
+type KeyStore struct {
+	mu     sync.RWMutex
+	path   string
+	byID   map[string]*Key
+	byHash map[string]*Key
+	now    func() time.Time
+}
+
+// NewKeyStore carrega as chaves do arquivo (inexistente começa vazio; "" mantém as chaves só em memória)
+func NewKeyStore(path string) (*KeyStore, error) {
+	s := &KeyStore{
+		path:   path,
+		byID:   make(map[string]*Key),
+		byHash: make(map[string]*Key),
+		now:    time.Now,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading api keys file: %w", err)
+	}
+	var file keysFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error decoding api keys file %s: %w", path, err)
+	}
+	for i := range file.Keys {
+		key := file.Keys[i]
+		if key.ID == "" || key.Hash == "" {
+			return nil, fmt.Errorf("error decoding api keys file %s: key without id or hash", path)
+		}
+		s.byID[key.ID] = &key
+		s.byHash[key.Hash] = &key
+	}
+	return s, nil
+}
+
+// Create emite uma chave com os escopos informados
+// O valor da chave só é devolvido aqui; depois disso apenas o hash é conhecido
+func (s *KeyStore) Create(name string, scopes []Scope) (string, Key, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", Key{}, errors.New("name is required")
+	}
+	if len(scopes) == 0 {
+		return "", Key{}, errors.New("at least one scope is required")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", Key{}, fmt.Errorf("error generating api key: %w", err)
+	}
+	plaintext := KeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	hash := hashKey(plaintext)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// O ID é o início do hash; em caso (improvável) de colisão usa mais caracteres
+	id := hash[:8]
+	for n := 12; s.byID[id] != nil; n += 4 {
+		id = hash[:n]
+	}
+	key := &Key{
+		ID:        id,
+		Name:      name,
+		Hash:      hash,
+		Scopes:    append([]Scope(nil), scopes...),
+		CreatedAt: s.now().UTC().Truncate(time.Second),
+	}
+	s.byID[key.ID] = key
+	s.byHash[key.Hash] = key
+	if err := s.save(); err != nil {
+		delete(s.byID, key.ID)
+		delete(s.byHash, key.Hash)
+		return "", Key{}, err
+	}
+	return plaintext, *key, nil
+}
+
+// Revoke remove a chave; as requisições seguintes com ela são recusadas
+func (s *KeyStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.byID[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	delete(s.byID, key.ID)
+	delete(s.byHash, key.Hash)
+	if err := s.save(); err != nil {
+		s.byID[key.ID] = key
+		s.byHash[key.Hash] = key
+		return err
+	}
+	return nil
+}
+
+// List retorna as chaves emitidas, das mais antigas para as mais recentes
+func (s *KeyStore) List() []Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list()
+}
+
+// Len retorna a quantidade de chaves emitidas
+func (s *KeyStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byID)
+}
+
+// Authenticate identifica o cliente pela chave do cabeçalho X-API-Key ou do parâmetro api_key
+func (s *KeyStore) Authenticate(r *http.Request) (*Principal, error) {
+	plaintext := strings.TrimSpace(r.Header.Get(HeaderAPIKey))
+	if plaintext == "" {
+		plaintext = strings.TrimSpace(r.URL.Query().Get(QueryAPIKey))
+	}
+	if plaintext == "" {
+		return nil, ErrNoCredentials
+	}
+
+	s.mu.RLock()
+	key, ok := s.byHash[hashKey(plaintext)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{ID: key.ID, Name: key.Name, Method: MethodAPIKey, Scopes: key.Scopes}, nil
+}
+
+// list ordena as chaves pela data de emissão (chamado com o lock)
+func (s *KeyStore) list() []Key {
+	keys := make([]Key, 0, len(s.byID))
+	for _, key := range s.byID {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// save grava as chaves no arquivo, substituindo-o de uma vez (chamado com o lock)
+func (s *KeyStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(keysFile{Keys: s.list()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding api keys: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error saving api keys: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error saving api keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error saving api keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error saving api keys: %w", err)
+	}
+	return nil
+}
+
+// hashKey calcula o hash guardado no lugar da chave
+// As chaves têm 256 bits aleatórios, portanto um hash rápido sem salt é suficiente
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
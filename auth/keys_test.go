@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestWithKey cria uma requisição com a chave no cabeçalho X-API-Key
+func requestWithKey(key string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/temperature/01310100", nil)
+	req.Header.Set(HeaderAPIKey, key)
+	return req
+}
+
+func TestKeyStore_CreateAndRevoke(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	store, err := NewKeyStore(path)
+	require.NoError(t, err)
+
+	plaintext, key, err := store.Create("partner", []Scope{ScopeTemperature, ScopeForecast})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plaintext, KeyPrefix))
+	assert.Len(t, key.ID, 8)
+	assert.Equal(t, []Scope{ScopeTemperature, ScopeForecast}, key.Scopes)
+
+	// O arquivo guarda apenas o hash da chave
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), plaintext)
+	assert.Contains(t, string(data), key.Hash)
+
+	principal, err := store.Authenticate(requestWithKey(plaintext))
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{ID: key.ID, Name: "partner", Method: MethodAPIKey, Scopes: key.Scopes}, principal)
+
+	// As chaves sobrevivem ao reinício
+	reloaded, err := NewKeyStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, store.List(), reloaded.List())
+	_, err = reloaded.Authenticate(requestWithKey(plaintext))
+	require.NoError(t, err)
+
+	require.NoError(t, reloaded.Revoke(key.ID))
+	_, err = reloaded.Authenticate(requestWithKey(plaintext))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorIs(t, reloaded.Revoke(key.ID), ErrKeyNotFound)
+
+	reloaded, err = NewKeyStore(path)
+	require.NoError(t, err)
+	assert.Zero(t, reloaded.Len())
+}
+
+func TestKeyStore_Create_Invalid(t *testing.T) {
+	store, err := NewKeyStore("")
+	require.NoError(t, err)
+
+	_, _, err = store.Create(" ", []Scope{ScopeTemperature})
+	assert.EqualError(t, err, "name is required")
+	_, _, err = store.Create("partner", nil)
+	assert.EqualError(t, err, "at least one scope is required")
+	assert.Zero(t, store.Len())
+}
+
+func TestNewKeyStore_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewKeyStore(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Zero(t, store.Len())
+
+	path := filepath.Join(dir, "api-keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"name":"no id"}]}`), 0o600))
+	_, err = NewKeyStore(path)
+	assert.ErrorContains(t, err, "key without id or hash")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":`), 0o600))
+	_, err = NewKeyStore(path)
+	assert.ErrorContains(t, err, "error decoding api keys file")
+}
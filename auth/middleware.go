@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey guarda o cliente autenticado no contexto do Gin
+const principalKey = "auth.principal"
+
+// Require autentica a requisição e exige o escopo informado
+// Sem credenciais válidas responde 401; com credenciais sem o escopo, 403
+func Require(authenticator Authenticator, scope Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authenticator.Authenticate(c.Request)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="weather-cep-api"`)
+			c.String(http.StatusUnauthorized, "unauthorized")
+			c.Abort()
+			return
+		}
+		if !principal.HasScope(scope) {
+			c.String(http.StatusForbidden, "forbidden")
+			c.Abort()
+			return
+		}
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// PrincipalFrom retorna o cliente autenticado por Require
+func PrincipalFrom(c *gin.Context) (*Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,215 @@
+// Package breaker implementa o circuit breaker das chamadas aos provedores externos
+//
+// Depois de uma sequência de falhas (erro de rede ou resposta 5xx) o circuito abre e as
+// chamadas falham imediatamente, sem esperar o timeout do provedor. Passado o intervalo de
+// espera, uma única chamada de teste é liberada: se funcionar o circuito fecha, senão volta a abrir.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-cep-api/services"
+)
+
+// ErrOpen é retornado (encapsulado) quando o circuito está aberto
+var ErrOpen = errors.New("circuit breaker open")
+
+// State é o estado do circuito
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+// String retorna o nome do estado (usado nos logs e na prontidão)
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Config descreve o circuit breaker de cada provedor
+type Config struct {
+	// Threshold é o número de falhas seguidas que abre o circuito (0 desativa)
+	Threshold int
+	// Cooldown é quanto tempo o circuito fica aberto antes da chamada de teste
+	Cooldown time.Duration
+}
+
+// Enabled indica se o circuit breaker está ativo
+func (c Config) Enabled() bool {
+	return c.Threshold > 0
+}
+
+// DefaultConfig retorna a configuração padrão
+func DefaultConfig() Config {
+	return Config{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+}
+
+// ConfigFrom lê CIRCUIT_BREAKER_THRESHOLD e CIRCUIT_BREAKER_COOLDOWN
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	if raw := strings.TrimSpace(getenv("CIRCUIT_BREAKER_THRESHOLD")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return cfg, fmt.Errorf("invalid CIRCUIT_BREAKER_THRESHOLD: %q", raw)
+		}
+		cfg.Threshold = value
+	}
+	if raw := strings.TrimSpace(getenv("CIRCUIT_BREAKER_COOLDOWN")); raw != "" {
+		value, err := time.ParseDuration(raw)
+		if err != nil || value <= 0 {
+			return cfg, fmt.Errorf("invalid CIRCUIT_BREAKER_COOLDOWN: %q", raw)
+		}
+		cfg.Cooldown = value
+	}
+	return cfg, nil
+}
+
+// Client é o decorator de HTTPClientInterface que aplica o circuit breaker às chamadas de um provedor
+type Client struct {
+	provider string
+	inner    services.HTTPClientInterface
+	cfg      Config
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewClient envolve o client HTTP de um provedor com o circuit breaker
+func NewClient(provider string, inner services.HTTPClientInterface, cfg Config) *Client {
+	return NewClientWithClock(provider, inner, cfg, time.Now)
+}
+
+// NewClientWithClock cria o circuit breaker com relógio customizado (usado nos testes)
+func NewClientWithClock(provider string, inner services.HTTPClientInterface, cfg Config, now func() time.Time) *Client {
+	return &Client{
+		provider: provider,
+		inner:    inner,
+		cfg:      cfg,
+		now:      now,
+	}
+}
+
+// Provider retorna o nome do provedor protegido
+func (c *Client) Provider() string {
+	return c.provider
+}
+
+// State retorna o estado atual do circuito (aberto passa a meio-aberto depois do intervalo de espera)
+func (c *Client) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == StateOpen && c.now().Sub(c.openedAt) >= c.cfg.Cooldown {
+		return StateHalfOpen
+	}
+	return c.state
+}
+
+// Do executa a requisição se o circuito permitir e registra o resultado
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if !c.cfg.Enabled() {
+		return c.inner.Do(req)
+	}
+	if !c.allow() {
+		return nil, fmt.Errorf("%w: %s", ErrOpen, c.provider)
+	}
+
+	resp, err := c.inner.Do(req)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Requisição cancelada pelo cliente: não diz nada sobre a saúde do provedor
+		c.release()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		c.recordFailure()
+	default:
+		c.recordSuccess()
+	}
+	return resp, err
+}
+
+// allow decide se a chamada pode seguir; no estado meio-aberto só uma chamada de teste por vez
+func (c *Client) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case StateClosed:
+		return true
+	case StateOpen:
+		if c.now().Sub(c.openedAt) < c.cfg.Cooldown {
+			return false
+		}
+		c.state = StateHalfOpen
+	}
+	if c.probing {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+// release libera a chamada de teste sem alterar o estado
+func (c *Client) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probing = false
+}
+
+// recordFailure conta a falha e abre o circuito ao atingir o limite (ou se a chamada de teste falhar)
+func (c *Client) recordFailure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	c.probing = false
+	if c.state == StateHalfOpen || c.failures >= c.cfg.Threshold {
+		if c.state != StateOpen {
+			slog.Warn("Circuit breaker aberto", "provider", c.provider, "failures", c.failures, "cooldown", c.cfg.Cooldown)
+		}
+		c.state = StateOpen
+		c.openedAt = c.now()
+	}
+}
+
+// recordSuccess zera as falhas e fecha o circuito
+func (c *Client) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != StateClosed {
+		slog.Info("Circuit breaker fechado", "provider", c.provider)
+	}
+	c.state = StateClosed
+	c.failures = 0
+	c.probing = false
+}
+
+// Check é a verificação de prontidão: falha enquanto o circuito estiver aberto
+func (c *Client) Check(_ context.Context) error {
+	if state := c.State(); state == StateOpen {
+		return fmt.Errorf("circuit breaker for %s is %s", c.provider, state)
+	}
+	return nil
+}
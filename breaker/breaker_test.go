@@ -0,0 +1,156 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHTTPClient é um mock do HTTPClientInterface
+type MockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+const viaCEPURL = "https://viacep.com.br/ws/01310100/json/"
+
+// newTestClient cria o circuit breaker com limite de 2 falhas e relógio controlado
+func newTestClient(inner *MockHTTPClient) (*Client, func(time.Duration)) {
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	client := NewClientWithClock("viacep", inner, Config{Threshold: 2, Cooldown: 30 * time.Second}, func() time.Time { return now })
+	return client, func(d time.Duration) { now = now.Add(d) }
+}
+
+func doRequest(t *testing.T, client *Client) (*http.Response, error) {
+	t.Helper()
+	return client.Do(httptest.NewRequest("GET", viaCEPURL, nil))
+}
+
+func TestClient_Do_OpensAfterThreshold(t *testing.T) {
+	inner := new(MockHTTPClient)
+	inner.On("Do", viaCEPURL).Return(&http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil).Twice()
+	client, advance := newTestClient(inner)
+
+	for i := 0; i < 2; i++ {
+		resp, err := doRequest(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	}
+	assert.Equal(t, StateOpen, client.State())
+	assert.Error(t, client.Check(context.Background()))
+
+	// Circuito aberto: falha imediata, sem chamar o provedor
+	_, err := doRequest(t, client)
+	assert.ErrorIs(t, err, ErrOpen)
+	inner.AssertNumberOfCalls(t, "Do", 2)
+
+	// Passado o intervalo, a chamada de teste é liberada e fecha o circuito
+	advance(30 * time.Second)
+	assert.Equal(t, StateHalfOpen, client.State())
+	assert.NoError(t, client.Check(context.Background()))
+	inner.On("Do", viaCEPURL).Return(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil).Once()
+	_, err = doRequest(t, client)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, client.State())
+}
+
+func TestClient_Do_FailedProbeReopens(t *testing.T) {
+	inner := new(MockHTTPClient)
+	inner.On("Do", viaCEPURL).Return(nil, errors.New("dial tcp: i/o timeout"))
+	client, advance := newTestClient(inner)
+
+	_, _ = doRequest(t, client)
+	_, _ = doRequest(t, client)
+	require.Equal(t, StateOpen, client.State())
+
+	advance(31 * time.Second)
+	_, err := doRequest(t, client)
+	assert.NotErrorIs(t, err, ErrOpen)
+	assert.Equal(t, StateOpen, client.State())
+
+	_, err = doRequest(t, client)
+	assert.ErrorIs(t, err, ErrOpen)
+	inner.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestClient_Do_IgnoresClientErrorsAndCancellation(t *testing.T) {
+	inner := new(MockHTTPClient)
+	inner.On("Do", viaCEPURL).Return(&http.Response{StatusCode: http.StatusBadRequest, Body: http.NoBody}, nil).Times(3)
+	client, _ := newTestClient(inner)
+
+	// 4xx é uma resposta válida do provedor
+	for i := 0; i < 3; i++ {
+		_, err := doRequest(t, client)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, StateClosed, client.State())
+
+	// Requisições canceladas pelo cliente não contam como falha
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inner.On("Do", viaCEPURL).Return(nil, context.Canceled)
+	for i := 0; i < 3; i++ {
+		_, err := client.Do(httptest.NewRequest("GET", viaCEPURL, nil).WithContext(ctx))
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, StateClosed, client.State())
+}
+
+func TestClient_Do_Disabled(t *testing.T) {
+	inner := new(MockHTTPClient)
+	inner.On("Do", viaCEPURL).Return(nil, errors.New("connection refused"))
+	client := NewClient("viacep", inner, Config{Threshold: 0})
+
+	for i := 0; i < 10; i++ {
+		_, err := doRequest(t, client)
+		assert.NotErrorIs(t, err, ErrOpen)
+	}
+	inner.AssertNumberOfCalls(t, "Do", 10)
+}
+
+func TestConfigFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   string
+		cooldown    string
+		expected    Config
+		expectError bool
+	}{
+		{name: "Defaults", expected: DefaultConfig()},
+		{name: "Custom", threshold: "3", cooldown: "1m", expected: Config{Threshold: 3, Cooldown: time.Minute}},
+		{name: "Disabled", threshold: "0", expected: Config{Threshold: 0, Cooldown: 30 * time.Second}},
+		{name: "Invalid threshold", threshold: "-1", expectError: true},
+		{name: "Invalid cooldown", cooldown: "0s", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{
+				"CIRCUIT_BREAKER_THRESHOLD": tt.threshold,
+				"CIRCUIT_BREAKER_COOLDOWN":  tt.cooldown,
+			}
+
+			cfg, err := ConfigFrom(func(key string) string { return env[key] })
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
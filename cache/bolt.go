@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket é o bucket onde as entradas são gravadas
+var boltBucket = []byte("cache")
+
+// boltHeaderSize é o tamanho do cabeçalho de cada valor (expiração em Unix nanossegundos)
+const boltHeaderSize = 8
+
+// Bolt é o backend em disco (bbolt): sobrevive a reinícios, mas não é compartilhado entre réplicas
+// O arquivo fica bloqueado pelo processo, então cada serviço precisa de um caminho próprio
+type Bolt struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// NewBolt abre (ou cria) o arquivo de cache e descarta as entradas já expiradas
+func NewBolt(path string) (*Bolt, error) {
+	return newBolt(path, time.Now)
+}
+
+// newBolt abre o arquivo de cache com relógio customizado
+func newBolt(path string, now func() time.Time) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening cache file %s: %w", path, err)
+	}
+
+	b := &Bolt{db: db, now: now}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltBucket)
+		if err != nil {
+			return err
+		}
+		return b.purgeExpired(bucket)
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing cache file %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Get retorna o valor se a entrada ainda não expirou
+func (b *Bolt) Get(key string) ([]byte, bool, error) {
+	var value []byte
+	expired := false
+	err := b.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltBucket).Get([]byte(key))
+		if raw == nil {
+			return nil
+		}
+		if len(raw) < boltHeaderSize {
+			expired = true
+			return nil
+		}
+		if b.isExpired(raw) {
+			expired = true
+			return nil
+		}
+		// O slice devolvido pelo bbolt só é válido durante a transação
+		value = append([]byte(nil), raw[boltHeaderSize:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading cache: %w", err)
+	}
+	if expired {
+		return nil, false, b.Delete(key)
+	}
+	return value, value != nil, nil
+}
+
+// Set grava o valor precedido do instante de expiração
+func (b *Bolt) Set(key string, value []byte, ttl time.Duration) error {
+	raw := make([]byte, boltHeaderSize+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(raw, uint64(b.now().Add(ttl).UnixNano()))
+	}
+	copy(raw[boltHeaderSize:], value)
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), raw)
+	})
+	if err != nil {
+		return fmt.Errorf("error writing cache: %w", err)
+	}
+	return nil
+}
+
+// Delete remove a chave
+func (b *Bolt) Delete(key string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting cache entry: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix remove as chaves com o prefixo
+func (b *Bolt) DeletePrefix(prefix string) (int, error) {
+	removed := 0
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		// Coleta antes de remover: apagar durante a iteração do cursor pode pular chaves
+		var keys [][]byte
+		cursor := bucket.Cursor()
+		for key, _ := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, _ = cursor.Next() {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		removed = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error purging cache: %w", err)
+	}
+	return removed, nil
+}
+
+// Count retorna quantas chaves não expiradas têm o prefixo
+func (b *Bolt) Count(prefix string) (int, error) {
+	count := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(boltBucket).Cursor()
+		for key, raw := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, raw = cursor.Next() {
+			if len(raw) >= boltHeaderSize && !b.isExpired(raw) {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading cache: %w", err)
+	}
+	return count, nil
+}
+
+// Ping verifica se o arquivo de cache continua aberto e legível
+func (b *Bolt) Ping(_ context.Context) error {
+	if err := b.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltBucket) == nil {
+			return fmt.Errorf("bucket %s not found", boltBucket)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("error reading cache file: %w", err)
+	}
+	return nil
+}
+
+// Close fecha o arquivo de cache
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
+
+// isExpired verifica o cabeçalho de expiração de um valor gravado
+func (b *Bolt) isExpired(raw []byte) bool {
+	expiresAt := int64(binary.BigEndian.Uint64(raw[:boltHeaderSize]))
+	return expiresAt != 0 && b.now().UnixNano() > expiresAt
+}
+
+// purgeExpired remove as entradas expiradas (executado na abertura do arquivo)
+func (b *Bolt) purgeExpired(bucket *bolt.Bucket) error {
+	var expired [][]byte
+	err := bucket.ForEach(func(key, raw []byte) error {
+		if len(raw) < boltHeaderSize || b.isExpired(raw) {
+			expired = append(expired, append([]byte(nil), key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
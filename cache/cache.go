@@ -0,0 +1,189 @@
+// Package cache define a abstração de cache usada pelos serviços de CEP e clima
+//
+// Os backends (memória, disco via bbolt e Redis) armazenam bytes com TTL; Store adiciona
+// a serialização dos modelos e um namespace de chaves para que vários serviços
+// compartilhem o mesmo backend (ex: um único Redis entre réplicas).
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Cache define o contrato dos backends de cache
+type Cache interface {
+	// Get retorna o valor da chave; found é false quando a chave não existe ou expirou
+	Get(key string) (value []byte, found bool, err error)
+	// Set grava o valor com o TTL informado (ttl <= 0 não expira)
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete remove a chave (não é erro se ela não existir)
+	Delete(key string) error
+	// DeletePrefix remove todas as chaves com o prefixo e retorna quantas foram removidas
+	DeletePrefix(prefix string) (int, error)
+	// Count retorna quantas chaves não expiradas têm o prefixo
+	Count(prefix string) (int, error)
+	// Close libera os recursos do backend
+	Close() error
+}
+
+// Pinger é implementado pelos backends que podem ficar indisponíveis (ex: Redis fora do ar)
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping verifica se o backend está acessível; backends sem Pinger (memória) estão sempre disponíveis
+func Ping(ctx context.Context, backend Cache) error {
+	if pinger, ok := backend.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// Store é um cache tipado: serializa T em JSON e prefixa as chaves com o namespace
+type Store[T any] struct {
+	backend   Cache
+	namespace string
+	ttl       atomic.Int64
+
+	hits    atomic.Int64
+	misses  atomic.Int64
+	writes  atomic.Int64
+	deletes atomic.Int64
+	errors  atomic.Int64
+}
+
+// Stats resume o uso de um cache tipado desde a inicialização
+type Stats struct {
+	Namespace string  `json:"namespace"`
+	TTL       string  `json:"ttl"`
+	Entries   int     `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	HitRatio  float64 `json:"hit_ratio"`
+	Writes    int64   `json:"writes"`
+	Deletes   int64   `json:"deletes"`
+	Errors    int64   `json:"errors"`
+}
+
+// NewStore cria um cache tipado sobre o backend informado
+// O namespace deve mudar junto com o formato de T (ex: "weather:v1:") para não ler entradas antigas
+func NewStore[T any](backend Cache, namespace string, ttl time.Duration) *Store[T] {
+	store := &Store[T]{
+		backend:   backend,
+		namespace: namespace,
+	}
+	store.ttl.Store(int64(ttl))
+	return store
+}
+
+// TTL retorna o tempo de vida das entradas gravadas por este cache
+func (s *Store[T]) TTL() time.Duration {
+	return time.Duration(s.ttl.Load())
+}
+
+// SetTTL altera o tempo de vida das próximas gravações (as entradas existentes mantêm a expiração original)
+func (s *Store[T]) SetTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("ttl must be positive")
+	}
+	s.ttl.Store(int64(ttl))
+	return nil
+}
+
+// Get busca e desserializa a entrada da chave
+func (s *Store[T]) Get(key string) (*T, bool, error) {
+	value, found, err := s.Peek(key)
+	switch {
+	case err != nil:
+		s.errors.Add(1)
+	case found:
+		s.hits.Add(1)
+	default:
+		s.misses.Add(1)
+	}
+	return value, found, err
+}
+
+// Peek busca a entrada sem contabilizá-la nas estatísticas (usado pela administração do cache)
+func (s *Store[T]) Peek(key string) (*T, bool, error) {
+	data, found, err := s.backend.Get(s.namespace + key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		// Entrada corrompida ou de outro formato: descarta para que a próxima consulta a regrave
+		_ = s.backend.Delete(s.namespace + key)
+		return nil, false, fmt.Errorf("error decoding cache entry %q: %w", key, err)
+	}
+	return &value, true, nil
+}
+
+// Set serializa e grava a entrada da chave
+func (s *Store[T]) Set(key string, value *T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.errors.Add(1)
+		return fmt.Errorf("error encoding cache entry %q: %w", key, err)
+	}
+	if err := s.backend.Set(s.namespace+key, data, s.TTL()); err != nil {
+		s.errors.Add(1)
+		return err
+	}
+	s.writes.Add(1)
+	return nil
+}
+
+// Delete remove a entrada da chave
+func (s *Store[T]) Delete(key string) error {
+	if err := s.backend.Delete(s.namespace + key); err != nil {
+		s.errors.Add(1)
+		return err
+	}
+	s.deletes.Add(1)
+	return nil
+}
+
+// Purge remove todas as entradas do namespace e retorna quantas foram removidas
+func (s *Store[T]) Purge() (int, error) {
+	removed, err := s.backend.DeletePrefix(s.namespace)
+	if err != nil {
+		s.errors.Add(1)
+		return removed, err
+	}
+	s.deletes.Add(int64(removed))
+	return removed, nil
+}
+
+// Ping verifica se o backend do cache está acessível
+func (s *Store[T]) Ping(ctx context.Context) error {
+	return Ping(ctx, s.backend)
+}
+
+// Stats retorna as estatísticas de uso e o número de entradas do namespace
+func (s *Store[T]) Stats() (Stats, error) {
+	stats := Stats{
+		Namespace: s.namespace,
+		TTL:       s.TTL().String(),
+		Hits:      s.hits.Load(),
+		Misses:    s.misses.Load(),
+		Writes:    s.writes.Load(),
+		Deletes:   s.deletes.Load(),
+		Errors:    s.errors.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+
+	entries, err := s.backend.Count(s.namespace)
+	if err != nil {
+		return stats, err
+	}
+	stats.Entries = entries
+	return stats, nil
+}
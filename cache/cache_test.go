@@ -0,0 +1,336 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"weather-cep-api/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backendFactory cria um backend e devolve uma função que avança o relógio dele
+type backendFactory func(t *testing.T) (Cache, func(time.Duration))
+
+// backends lista os backends testados com o mesmo contrato
+var backends = map[string]backendFactory{
+	"memory": func(t *testing.T) (Cache, func(time.Duration)) {
+		now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+		backend := NewMemoryWithClock(func() time.Time { return now })
+		return backend, func(d time.Duration) { now = now.Add(d) }
+	},
+	"bolt": func(t *testing.T) (Cache, func(time.Duration)) {
+		now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+		backend, err := newBolt(filepath.Join(t.TempDir(), "cache.db"), func() time.Time { return now })
+		require.NoError(t, err)
+		return backend, func(d time.Duration) { now = now.Add(d) }
+	},
+	"redis": func(t *testing.T) (Cache, func(time.Duration)) {
+		server := miniredis.RunT(t)
+		backend, err := NewRedis("redis://" + server.Addr() + "/0")
+		require.NoError(t, err)
+		return backend, server.FastForward
+	},
+}
+
+func TestBackends_Contract(t *testing.T) {
+	for name, factory := range backends {
+		t.Run(name, func(t *testing.T) {
+			backend, advance := factory(t)
+			defer backend.Close()
+
+			_, found, err := backend.Get("missing")
+			require.NoError(t, err)
+			assert.False(t, found)
+
+			require.NoError(t, backend.Set("key", []byte("value"), time.Minute))
+			value, found, err := backend.Get("key")
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, []byte("value"), value)
+
+			// Regravar substitui o valor e renova o TTL
+			advance(40 * time.Second)
+			require.NoError(t, backend.Set("key", []byte("other"), time.Minute))
+			advance(40 * time.Second)
+			value, found, err = backend.Get("key")
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, []byte("other"), value)
+
+			// Expira após o TTL
+			advance(time.Minute)
+			_, found, err = backend.Get("key")
+			require.NoError(t, err)
+			assert.False(t, found)
+
+			// TTL zero não expira
+			require.NoError(t, backend.Set("forever", []byte("x"), 0))
+			advance(24 * time.Hour)
+			_, found, err = backend.Get("forever")
+			require.NoError(t, err)
+			assert.True(t, found)
+
+			require.NoError(t, backend.Delete("forever"))
+			require.NoError(t, backend.Delete("forever"))
+			_, found, err = backend.Get("forever")
+			require.NoError(t, err)
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestStore_Serialization(t *testing.T) {
+	for name, factory := range backends {
+		t.Run(name, func(t *testing.T) {
+			backend, _ := factory(t)
+			defer backend.Close()
+
+			locations := NewStore[models.LocationInfo](backend, "cep:v1:", time.Hour)
+			temperatures := NewStore[models.TemperatureResponse](backend, "temperature:v1:", time.Hour)
+
+			location := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100", IBGE: "3550308", UFMismatch: true}
+			temperature := &models.TemperatureResponse{TempC: 25.5, TempF: 77.9, TempK: 298.5}
+
+			// Mesma chave em namespaces diferentes não colide
+			require.NoError(t, locations.Set("01310100", location))
+			require.NoError(t, temperatures.Set("01310100", temperature))
+
+			gotLocation, found, err := locations.Get("01310100")
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, location, gotLocation)
+
+			gotTemperature, found, err := temperatures.Get("01310100")
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, temperature, gotTemperature)
+
+			require.NoError(t, locations.Delete("01310100"))
+			_, found, err = locations.Get("01310100")
+			require.NoError(t, err)
+			assert.False(t, found)
+			_, found, _ = temperatures.Get("01310100")
+			assert.True(t, found)
+		})
+	}
+}
+
+func TestMemory_SweepsExpiredEntries(t *testing.T) {
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	backend := NewMemoryWithClock(func() time.Time { return now })
+	for _, key := range []string{"coords|-23.5613,-46.6565", "coords|-22.9068,-43.1729", "coords|-8.0539,-34.8811"} {
+		require.NoError(t, backend.Set(key, []byte("{}"), time.Minute))
+	}
+	require.NoError(t, backend.Set("permanent", []byte("{}"), 0))
+
+	// Antes do intervalo de limpeza as entradas vencidas continuam guardadas
+	now = now.Add(59 * time.Second)
+	require.NoError(t, backend.Set("fresh", []byte("{}"), time.Hour))
+	assert.Len(t, backend.entries, 5)
+
+	// A limpeza periódica descarta as vencidas sem que elas sejam lidas
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, backend.Set("other", []byte("{}"), time.Hour))
+	assert.Len(t, backend.entries, 3)
+	assert.Equal(t, 3, backend.recent.Len())
+	for _, key := range []string{"permanent", "fresh", "other"} {
+		assert.Contains(t, backend.entries, key)
+	}
+}
+
+func TestMemory_MaxEntries(t *testing.T) {
+	backend := NewMemoryWithLimit(2)
+	require.NoError(t, backend.Set("a", []byte("1"), time.Hour))
+	require.NoError(t, backend.Set("b", []byte("2"), time.Hour))
+
+	// A leitura renova "a"; a nova entrada descarta "b", a menos usada recentemente
+	_, found, _ := backend.Get("a")
+	require.True(t, found)
+	require.NoError(t, backend.Set("c", []byte("3"), time.Hour))
+	_, found, _ = backend.Get("b")
+	assert.False(t, found)
+	for _, key := range []string{"a", "c"} {
+		_, found, _ = backend.Get(key)
+		assert.True(t, found, key)
+	}
+
+	// Regravar uma chave existente não descarta outras
+	require.NoError(t, backend.Set("c", []byte("4"), time.Hour))
+	count, err := backend.Count("")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestStore_Get_CorruptedEntry(t *testing.T) {
+	backend := NewMemory()
+	store := NewStore[models.LocationInfo](backend, "cep:v1:", time.Hour)
+	require.NoError(t, backend.Set("cep:v1:01310100", []byte("not json"), time.Hour))
+
+	_, found, err := store.Get("01310100")
+	assert.Error(t, err)
+	assert.False(t, found)
+
+	// A entrada inválida é descartada
+	_, found, _ = backend.Get("cep:v1:01310100")
+	assert.False(t, found)
+}
+
+func TestBolt_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	backend, err := NewBolt(path)
+	require.NoError(t, err)
+	require.NoError(t, backend.Set("kept", []byte("value"), time.Hour))
+	require.NoError(t, backend.Set("expired", []byte("value"), time.Millisecond))
+	require.NoError(t, backend.Close())
+
+	time.Sleep(5 * time.Millisecond)
+	backend, err = NewBolt(path)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	value, found, err := backend.Get("kept")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("value"), value)
+
+	_, found, err = backend.Get("expired")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRedis_SharedBetweenInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	// Duas réplicas apontando para o mesmo Redis enxergam as mesmas entradas
+	first := NewRedisWithClient(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	second := NewRedisWithClient(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	defer first.Close()
+	defer second.Close()
+
+	require.NoError(t, first.Set("weather:v1:são paulo|SP", []byte(`{"temp_C":25}`), time.Minute))
+	value, found, err := second.Get("weather:v1:são paulo|SP")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.JSONEq(t, `{"temp_C":25}`, string(value))
+	assert.True(t, server.Exists("weather:v1:são paulo|SP"))
+}
+
+func TestRedis_Errors(t *testing.T) {
+	_, err := NewRedis("not-a-url")
+	assert.Error(t, err)
+
+	server := miniredis.RunT(t)
+	backend, err := NewRedis("redis://" + server.Addr())
+	require.NoError(t, err)
+	defer backend.Close()
+
+	// Redis indisponível vira erro (os serviços registram e seguem sem cache)
+	server.Close()
+	_, _, err = backend.Get("key")
+	assert.Error(t, err)
+	assert.Error(t, backend.Set("key", []byte("value"), time.Minute))
+}
+
+func TestBackends_PrefixOperations(t *testing.T) {
+	for name, factory := range backends {
+		t.Run(name, func(t *testing.T) {
+			backend, advance := factory(t)
+			defer backend.Close()
+
+			require.NoError(t, backend.Set("cep:v1:01310100", []byte("a"), time.Hour))
+			require.NoError(t, backend.Set("cep:v1:20040020", []byte("b"), time.Hour))
+			require.NoError(t, backend.Set("cep:v1:70040010", []byte("c"), time.Minute))
+			require.NoError(t, backend.Set("weather:v1:recife|PE", []byte("d"), time.Hour))
+
+			count, err := backend.Count("cep:v1:")
+			require.NoError(t, err)
+			assert.Equal(t, 3, count)
+
+			// Entradas expiradas não são contadas
+			advance(2 * time.Minute)
+			count, err = backend.Count("cep:v1:")
+			require.NoError(t, err)
+			assert.Equal(t, 2, count)
+
+			removed, err := backend.DeletePrefix("cep:v1:")
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, removed, 2)
+
+			count, err = backend.Count("cep:v1:")
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+
+			// Outros namespaces não são afetados
+			_, found, err := backend.Get("weather:v1:recife|PE")
+			require.NoError(t, err)
+			assert.True(t, found)
+		})
+	}
+}
+
+func TestStore_StatsAndTTL(t *testing.T) {
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	backend := NewMemoryWithClock(func() time.Time { return now })
+	store := NewStore[models.LocationInfo](backend, "cep:v1:", time.Hour)
+
+	_, _, _ = store.Get("01310100")
+	require.NoError(t, store.Set("01310100", &models.LocationInfo{City: "São Paulo", State: "SP"}))
+	_, _, _ = store.Get("01310100")
+	_, _, _ = store.Get("01310100")
+	_, _, _ = store.Peek("01310100")
+
+	stats, err := store.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, Stats{
+		Namespace: "cep:v1:",
+		TTL:       "1h0m0s",
+		Entries:   1,
+		Hits:      2,
+		Misses:    1,
+		HitRatio:  2.0 / 3.0,
+		Writes:    1,
+	}, stats)
+
+	// O novo TTL vale para as próximas gravações
+	assert.Error(t, store.SetTTL(0))
+	require.NoError(t, store.SetTTL(time.Minute))
+	assert.Equal(t, time.Minute, store.TTL())
+	require.NoError(t, store.Set("20040020", &models.LocationInfo{City: "Rio de Janeiro", State: "RJ"}))
+
+	now = now.Add(2 * time.Minute)
+	_, found, _ := store.Peek("20040020")
+	assert.False(t, found)
+	_, found, _ = store.Peek("01310100")
+	assert.True(t, found)
+
+	// A entrada expirada já foi descartada na leitura; resta apenas uma para o purge
+	removed, err := store.Purge()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	stats, err = store.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Entries)
+	assert.Equal(t, int64(1), stats.Deletes)
+}
+
+func TestPing(t *testing.T) {
+	for name, factory := range backends {
+		t.Run(name, func(t *testing.T) {
+			backend, _ := factory(t)
+			assert.NoError(t, Ping(context.Background(), backend))
+			assert.NoError(t, NewStore[models.LocationInfo](backend, "cep:v1:", time.Hour).Ping(context.Background()))
+
+			// Depois de fechado, os backends com recurso externo passam a falhar
+			require.NoError(t, backend.Close())
+			if _, ok := backend.(Pinger); ok {
+				assert.Error(t, Ping(context.Background(), backend))
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backend identifica a implementação de cache
+type Backend string
+
+const (
+	BackendNone   Backend = "none"
+	BackendMemory Backend = "memory"
+	BackendBolt   Backend = "bolt"
+	BackendRedis  Backend = "redis"
+)
+
+// DefaultMaxEntries é o limite padrão de entradas do backend em memória
+const DefaultMaxEntries = 100000
+
+// Config descreve o cache de um serviço
+type Config struct {
+	Backend  Backend
+	TTL      time.Duration
+	Path     string
+	RedisURL string
+	// MaxEntries limita as entradas do backend em memória (0 = sem limite); as menos usadas
+	// recentemente são descartadas primeiro
+	MaxEntries int
+}
+
+// Enabled indica se o serviço deve usar cache
+func (c Config) Enabled() bool {
+	return c.Backend != BackendNone && c.TTL > 0
+}
+
+// ConfigFrom lê a configuração de cache de um serviço a partir das variáveis com o prefixo informado:
+// <PREFIX>_CACHE_BACKEND (memory, bolt, redis ou none), <PREFIX>_CACHE_TTL (ex: "5m"; "0" desativa),
+// <PREFIX>_CACHE_PATH (arquivo do bolt), <PREFIX>_CACHE_REDIS_URL (padrão: REDIS_URL) e
+// <PREFIX>_CACHE_MAX_ENTRIES (backend memory; "0" sem limite)
+func ConfigFrom(prefix string, defaultTTL time.Duration, getenv func(string) string) (Config, error) {
+	prefix = strings.ToUpper(prefix)
+	cfg := Config{
+		Backend:    BackendMemory,
+		TTL:        defaultTTL,
+		Path:       strings.ToLower(prefix) + "-cache.db",
+		RedisURL:   getenv("REDIS_URL"),
+		MaxEntries: DefaultMaxEntries,
+	}
+
+	if raw := strings.TrimSpace(getenv(prefix + "_CACHE_BACKEND")); raw != "" {
+		cfg.Backend = Backend(strings.ToLower(raw))
+	}
+	if raw := strings.TrimSpace(getenv(prefix + "_CACHE_TTL")); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl < 0 {
+			return cfg, fmt.Errorf("invalid %s_CACHE_TTL: %q", prefix, raw)
+		}
+		cfg.TTL = ttl
+	}
+	if raw := getenv(prefix + "_CACHE_PATH"); raw != "" {
+		cfg.Path = raw
+	}
+	if raw := getenv(prefix + "_CACHE_REDIS_URL"); raw != "" {
+		cfg.RedisURL = raw
+	}
+	if raw := strings.TrimSpace(getenv(prefix + "_CACHE_MAX_ENTRIES")); raw != "" {
+		maxEntries, err := strconv.Atoi(raw)
+		if err != nil || maxEntries < 0 {
+			return cfg, fmt.Errorf("invalid %s_CACHE_MAX_ENTRIES: %q", prefix, raw)
+		}
+		cfg.MaxEntries = maxEntries
+	}
+
+	switch cfg.Backend {
+	case BackendNone, BackendMemory, BackendBolt:
+	case BackendRedis:
+		if cfg.RedisURL == "" {
+			return cfg, fmt.Errorf("%s_CACHE_BACKEND=redis requires %s_CACHE_REDIS_URL or REDIS_URL", prefix, prefix)
+		}
+	default:
+		return cfg, fmt.Errorf("invalid %s_CACHE_BACKEND: %q", prefix, cfg.Backend)
+	}
+	return cfg, nil
+}
+
+// Open cria o backend descrito pela configuração (nil quando o cache está desativado)
+func Open(cfg Config) (Cache, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	switch cfg.Backend {
+	case BackendMemory:
+		return NewMemoryWithLimit(cfg.MaxEntries), nil
+	case BackendBolt:
+		return NewBolt(cfg.Path)
+	case BackendRedis:
+		return NewRedis(cfg.RedisURL)
+	}
+	return nil, fmt.Errorf("invalid cache backend: %q", cfg.Backend)
+}
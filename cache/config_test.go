@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+	}{
+		{
+			name:     "Defaults",
+			env:      map[string]string{},
+			expected: Config{Backend: BackendMemory, TTL: time.Hour, Path: "cep-cache.db", MaxEntries: DefaultMaxEntries},
+		},
+		{
+			name:     "Bolt with custom path",
+			env:      map[string]string{"CEP_CACHE_BACKEND": "BOLT", "CEP_CACHE_PATH": "/data/cep.db", "CEP_CACHE_TTL": "48h"},
+			expected: Config{Backend: BackendBolt, TTL: 48 * time.Hour, Path: "/data/cep.db", MaxEntries: DefaultMaxEntries},
+		},
+		{
+			name:     "Redis from shared URL",
+			env:      map[string]string{"CEP_CACHE_BACKEND": "redis", "REDIS_URL": "redis://cache:6379/1"},
+			expected: Config{Backend: BackendRedis, TTL: time.Hour, Path: "cep-cache.db", RedisURL: "redis://cache:6379/1", MaxEntries: DefaultMaxEntries},
+		},
+		{
+			name:     "Redis URL per service",
+			env:      map[string]string{"CEP_CACHE_BACKEND": "redis", "REDIS_URL": "redis://cache:6379/1", "CEP_CACHE_REDIS_URL": "redis://other:6379/2"},
+			expected: Config{Backend: BackendRedis, TTL: time.Hour, Path: "cep-cache.db", RedisURL: "redis://other:6379/2", MaxEntries: DefaultMaxEntries},
+		},
+		{
+			name:     "Memory without entry limit",
+			env:      map[string]string{"CEP_CACHE_MAX_ENTRIES": "0"},
+			expected: Config{Backend: BackendMemory, TTL: time.Hour, Path: "cep-cache.db"},
+		},
+		{
+			name:     "Disabled by TTL",
+			env:      map[string]string{"CEP_CACHE_TTL": "0"},
+			expected: Config{Backend: BackendMemory, TTL: 0, Path: "cep-cache.db", MaxEntries: DefaultMaxEntries},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ConfigFrom("cep", time.Hour, func(key string) string { return tt.env[key] })
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestConfigFrom_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "Unknown backend", env: map[string]string{"WEATHER_CACHE_BACKEND": "memcached"}},
+		{name: "Invalid TTL", env: map[string]string{"WEATHER_CACHE_TTL": "soon"}},
+		{name: "Negative TTL", env: map[string]string{"WEATHER_CACHE_TTL": "-1m"}},
+		{name: "Redis without URL", env: map[string]string{"WEATHER_CACHE_BACKEND": "redis"}},
+		{name: "Negative max entries", env: map[string]string{"WEATHER_CACHE_MAX_ENTRIES": "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConfigFrom("WEATHER", time.Minute, func(key string) string { return tt.env[key] })
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	backend, err := Open(Config{Backend: BackendNone, TTL: time.Minute})
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+
+	backend, err = Open(Config{Backend: BackendMemory, TTL: 0})
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+
+	backend, err = Open(Config{Backend: BackendMemory, TTL: time.Minute})
+	require.NoError(t, err)
+	assert.IsType(t, &Memory{}, backend)
+
+	backend, err = Open(Config{Backend: BackendBolt, TTL: time.Minute, Path: filepath.Join(t.TempDir(), "cache.db")})
+	require.NoError(t, err)
+	assert.IsType(t, &Bolt{}, backend)
+	require.NoError(t, backend.Close())
+
+	server := miniredis.RunT(t)
+	backend, err = Open(Config{Backend: BackendRedis, TTL: time.Minute, RedisURL: "redis://" + server.Addr()})
+	require.NoError(t, err)
+	assert.IsType(t, &Redis{}, backend)
+	require.NoError(t, backend.Close())
+
+	_, err = Open(Config{Backend: BackendBolt, TTL: time.Minute, Path: filepath.Join(t.TempDir(), "missing", "cache.db")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval é o intervalo mínimo entre as remoções das entradas expiradas
+const memorySweepInterval = time.Minute
+
+// Memory é o backend em memória (estado local ao processo, perdido ao reiniciar)
+// As entradas expiradas são removidas a cada minuto, mesmo sem serem lidas, e com maxEntries > 0
+// as menos usadas recentemente dão lugar às novas quando o limite é atingido
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	recent     *list.List // da mais para a menos usada recentemente
+	maxEntries int
+	now        func() time.Time
+	lastSweep  time.Time
+}
+
+// memoryEntry representa uma entrada com o instante de expiração (zero = não expira)
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemory cria um backend em memória sem limite de entradas
+func NewMemory() *Memory {
+	return NewMemoryWithLimit(0)
+}
+
+// NewMemoryWithLimit cria um backend em memória com no máximo maxEntries entradas (0 = sem limite)
+func NewMemoryWithLimit(maxEntries int) *Memory {
+	return newMemory(maxEntries, time.Now)
+}
+
+// NewMemoryWithClock cria um backend em memória com relógio customizado (para testes)
+func NewMemoryWithClock(now func() time.Time) *Memory {
+	return newMemory(0, now)
+}
+
+func newMemory(maxEntries int, now func() time.Time) *Memory {
+	return &Memory{
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+		maxEntries: maxEntries,
+		now:        now,
+		lastSweep:  now(),
+	}
+}
+
+// Get retorna uma cópia do valor se a entrada ainda não expirou
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maybeSweep(now)
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if entry.expired(now) {
+		m.remove(element)
+		return nil, false, nil
+	}
+	m.recent.MoveToFront(element)
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+// Set grava uma cópia do valor
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	now := m.now()
+	entry := &memoryEntry{key: key, value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maybeSweep(now)
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.recent.MoveToFront(element)
+		return nil
+	}
+	m.entries[key] = m.recent.PushFront(entry)
+	for m.maxEntries > 0 && len(m.entries) > m.maxEntries {
+		m.remove(m.recent.Back())
+	}
+	return nil
+}
+
+// Delete remove a chave
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.entries[key]; ok {
+		m.remove(element)
+	}
+	return nil
+}
+
+// DeletePrefix remove as chaves com o prefixo
+func (m *Memory) DeletePrefix(prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, element := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(element)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Count retorna quantas chaves não expiradas têm o prefixo
+func (m *Memory) Count(prefix string) (int, error) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for key, element := range m.entries {
+		if strings.HasPrefix(key, prefix) && !element.Value.(*memoryEntry).expired(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Close não tem recursos a liberar no backend em memória
+func (m *Memory) Close() error {
+	return nil
+}
+
+// maybeSweep remove as entradas expiradas, no máximo uma vez por memorySweepInterval (chamado com o lock)
+func (m *Memory) maybeSweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for _, element := range m.entries {
+		if element.Value.(*memoryEntry).expired(now) {
+			m.remove(element)
+		}
+	}
+}
+
+// remove tira a entrada do índice e da lista de uso (chamado com o lock)
+func (m *Memory) remove(element *list.Element) {
+	delete(m.entries, element.Value.(*memoryEntry).key)
+	m.recent.Remove(element)
+}
+
+// expired informa se a entrada venceu no instante informado
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout limita cada operação no Redis para que uma instância lenta não trave as requisições
+const redisTimeout = 2 * time.Second
+
+// Redis é o backend compatível com o protocolo do Redis (Redis, Valkey, KeyDB...), compartilhado entre réplicas
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis conecta ao Redis a partir de uma URL (ex: redis://:senha@localhost:6379/0)
+func NewRedis(rawURL string) (*Redis, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to redis: %w", err)
+	}
+	return NewRedisWithClient(client), nil
+}
+
+// NewRedisWithClient cria o backend com um client já configurado
+func NewRedisWithClient(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+// Get retorna o valor da chave
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading cache: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set grava o valor com expiração nativa do Redis
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("error writing cache: %w", err)
+	}
+	return nil
+}
+
+// Delete remove a chave
+func (r *Redis) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("error deleting cache entry: %w", err)
+	}
+	return nil
+}
+
+// redisScanBatch é o número de chaves pedidas a cada SCAN
+const redisScanBatch = 500
+
+// DeletePrefix remove as chaves com o prefixo (via SCAN, sem bloquear o Redis como KEYS)
+func (r *Redis) DeletePrefix(prefix string) (int, error) {
+	removed := 0
+	err := r.scan(prefix, func(keys []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		defer cancel()
+		n, err := r.client.Del(ctx, keys...).Result()
+		removed += int(n)
+		return err
+	})
+	if err != nil {
+		return removed, fmt.Errorf("error purging cache: %w", err)
+	}
+	return removed, nil
+}
+
+// Count retorna quantas chaves têm o prefixo (o Redis remove as expiradas sozinho)
+func (r *Redis) Count(prefix string) (int, error) {
+	count := 0
+	err := r.scan(prefix, func(keys []string) error {
+		count += len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading cache: %w", err)
+	}
+	return count, nil
+}
+
+// scan percorre as chaves com o prefixo em lotes
+func (r *Redis) scan(prefix string, fn func(keys []string) error) error {
+	pattern := redisGlobEscaper.Replace(prefix) + "*"
+	var cursor uint64
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, redisScanBatch).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// redisGlobEscaper escapa os caracteres especiais do MATCH do Redis
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// Ping verifica a conexão com o Redis
+func (r *Redis) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("error connecting to redis: %w", err)
+	}
+	return nil
+}
+
+// Close encerra as conexões com o Redis
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
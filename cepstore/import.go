@@ -0,0 +1,169 @@
+package cepstore
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvColumns mapeia os nomes de coluna aceitos (em minúsculas) para o campo do registro
+var csvColumns = map[string]string{
+	"cep":         "cep",
+	"localidade":  "city",
+	"cidade":      "city",
+	"city":        "city",
+	"municipio":   "city",
+	"uf":          "uf",
+	"estado":      "uf",
+	"state":       "uf",
+	"ibge":        "ibge",
+	"codigo_ibge": "ibge",
+}
+
+// jsonRecord aceita tanto registros no formato da ViaCEP quanto nomes em inglês
+type jsonRecord struct {
+	CEP        string `json:"cep"`
+	Localidade string `json:"localidade"`
+	City       string `json:"city"`
+	UF         string `json:"uf"`
+	State      string `json:"state"`
+	IBGE       string `json:"ibge"`
+}
+
+// toRecord converte o registro JSON para o formato do banco
+func (r jsonRecord) toRecord() Record {
+	record := Record{CEP: r.CEP, City: r.Localidade, UF: r.UF, IBGE: r.IBGE}
+	if record.City == "" {
+		record.City = r.City
+	}
+	if record.UF == "" {
+		record.UF = r.State
+	}
+	return record
+}
+
+// ImportCSV lê um dump CSV com cabeçalho (cep, localidade/cidade, uf, ibge opcional) para o builder
+// O separador (vírgula ou ponto e vírgula) é detectado pelo cabeçalho; retorna o número de linhas importadas
+func ImportCSV(r io.Reader, b *Builder) (int, error) {
+	reader := bufio.NewReader(r)
+	header, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	csvReader := csv.NewReader(io.MultiReader(strings.NewReader(header), reader))
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		csvReader.Comma = ';'
+	}
+	csvReader.TrimLeadingSpace = true
+	csvReader.ReuseRecord = true
+
+	columns, err := csvReader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	positions := make(map[string]int)
+	for i, name := range columns {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			if _, seen := positions[field]; !seen {
+				positions[field] = i
+			}
+		}
+	}
+	for _, required := range []string{"cep", "city", "uf"} {
+		if _, ok := positions[required]; !ok {
+			return 0, fmt.Errorf("missing CSV column: %s", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := positions[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	imported := 0
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, fmt.Errorf("error reading CSV: %w", err)
+		}
+
+		record := Record{
+			CEP:  field(row, "cep"),
+			City: field(row, "city"),
+			UF:   field(row, "uf"),
+			IBGE: field(row, "ibge"),
+		}
+		if err := b.Add(record); err != nil {
+			line, _ := csvReader.FieldPos(0)
+			return imported, fmt.Errorf("line %d: %w", line, err)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+// ImportJSON lê um dump JSON para o builder: um array de registros ou um registro por linha (NDJSON)
+// Registros no formato da ViaCEP (cep, localidade, uf, ibge) são aceitos diretamente
+func ImportJSON(r io.Reader, b *Builder) (int, error) {
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+
+	// Um array é percorrido elemento a elemento para não carregar o dump inteiro em memória
+	first, err := firstNonSpace(reader)
+	if err != nil {
+		if err == io.EOF {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error reading JSON: %w", err)
+	}
+	isArray := first == '['
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return 0, fmt.Errorf("error reading JSON: %w", err)
+		}
+	}
+
+	imported := 0
+	for decoder.More() {
+		var item jsonRecord
+		if err := decoder.Decode(&item); err != nil {
+			return imported, fmt.Errorf("record %d: error decoding JSON: %w", imported+1, err)
+		}
+		if err := b.Add(item.toRecord()); err != nil {
+			return imported, fmt.Errorf("record %d: %w", imported+1, err)
+		}
+		imported++
+	}
+
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return imported, fmt.Errorf("error reading JSON: %w", err)
+		}
+	}
+	return imported, nil
+}
+
+// firstNonSpace retorna o primeiro byte que não é espaço, sem consumi-lo
+func firstNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c, reader.UnreadByte()
+	}
+}
@@ -0,0 +1,97 @@
+package cepstore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "Comma separated",
+			content: "cep,logradouro,localidade,uf,ibge\n01310-100,Avenida Paulista,São Paulo,SP,3550308\n20040020,Rua da Assembleia,Rio de Janeiro,RJ,3304557\n",
+		},
+		{
+			name:    "Semicolon separated with BOM",
+			content: "\ufeffCEP;Cidade;Estado\n01310100;São Paulo;SP\n20040-020;Rio de Janeiro;rj\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			imported, err := ImportCSV(strings.NewReader(tt.content), builder)
+			require.NoError(t, err)
+			assert.Equal(t, 2, imported)
+			assert.Equal(t, 2, builder.Len())
+			assert.Equal(t, "São Paulo", builder.records["01310100"].City)
+			assert.Equal(t, "RJ", builder.records["20040020"].UF)
+		})
+	}
+}
+
+func TestImportCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{name: "Missing column", content: "cep,localidade\n01310100,São Paulo\n", errMsg: "missing CSV column: uf"},
+		{name: "Invalid row", content: "cep,localidade,uf\n01310100,São Paulo,SP\n123,Cidade,SP\n", errMsg: "line 3"},
+		{name: "Empty file", content: "", errMsg: "error reading CSV header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportCSV(strings.NewReader(tt.content), NewBuilder())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "ViaCEP array",
+			content: `[{"cep":"01310-100","localidade":"São Paulo","uf":"SP","ibge":"3550308"},{"cep":"20040-020","localidade":"Rio de Janeiro","uf":"RJ"}]`,
+		},
+		{
+			name:    "NDJSON with english names",
+			content: "{\"cep\":\"01310100\",\"city\":\"São Paulo\",\"state\":\"SP\",\"ibge\":\"3550308\"}\n{\"cep\":\"20040020\",\"city\":\"Rio de Janeiro\",\"state\":\"RJ\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			imported, err := ImportJSON(strings.NewReader(tt.content), builder)
+			require.NoError(t, err)
+			assert.Equal(t, 2, imported)
+			assert.Equal(t, "3550308", builder.records["01310100"].IBGE)
+			assert.Equal(t, "Rio de Janeiro", builder.records["20040020"].City)
+		})
+	}
+}
+
+func TestImportJSON_Errors(t *testing.T) {
+	_, err := ImportJSON(strings.NewReader(`[{"cep":"01310100","localidade":"São Paulo","uf":"SP"},{"cep":"1"}]`), NewBuilder())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "record 2")
+
+	_, err = ImportJSON(strings.NewReader(`[{"cep":`), NewBuilder())
+	assert.Error(t, err)
+
+	imported, err := ImportJSON(strings.NewReader("  "), NewBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, imported)
+}
@@ -0,0 +1,376 @@
+// Package cepstore implementa um banco local de CEPs (chave-valor embutido) para operação offline
+//
+// Os registros ficam ordenados por CEP e agrupados em blocos. Dentro de cada bloco as chaves
+// são armazenadas com compressão de prefixo (front coding) e os valores referenciam uma tabela
+// de strings deduplicada, o que mantém milhões de CEPs em poucos megabytes.
+package cepstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"weather-cep-api/utils"
+)
+
+// fileMagic identifica o formato do arquivo
+const fileMagic = "CEPSTORE"
+
+// fileVersion é a versão atual do formato
+const fileVersion = 1
+
+// DefaultBlockSize é o número de registros por bloco
+const DefaultBlockSize = 128
+
+// keyLength é o tamanho fixo da chave (CEP com 8 dígitos)
+const keyLength = 8
+
+// Record representa um CEP do banco local
+type Record struct {
+	CEP  string
+	City string
+	UF   string
+	IBGE string
+}
+
+// blockIndex aponta para o início de um bloco
+type blockIndex struct {
+	firstKey string
+	offset   int
+	count    int
+}
+
+// Store é o banco de CEPs carregado em memória, somente leitura e seguro para uso concorrente
+type Store struct {
+	strings []string
+	blocks  []blockIndex
+	data    []byte
+	count   int
+}
+
+// Builder acumula registros e grava o arquivo do banco
+type Builder struct {
+	records   map[string]Record
+	blockSize int
+}
+
+// NewBuilder cria um builder com o tamanho de bloco padrão
+func NewBuilder() *Builder {
+	return &Builder{
+		records:   make(map[string]Record),
+		blockSize: DefaultBlockSize,
+	}
+}
+
+// Add valida e adiciona um registro; um CEP repetido substitui o anterior
+func (b *Builder) Add(record Record) error {
+	if !utils.IsValidCEP(record.CEP) {
+		return fmt.Errorf("invalid zipcode %q", record.CEP)
+	}
+	if record.City == "" {
+		return fmt.Errorf("missing city for zipcode %q", record.CEP)
+	}
+	record.CEP = utils.NormalizeCEP(record.CEP)
+	record.UF = utils.NormalizeUF(record.UF)
+	if !utils.IsValidUF(record.UF) {
+		return fmt.Errorf("invalid UF %q for zipcode %q", record.UF, record.CEP)
+	}
+
+	b.records[record.CEP] = record
+	return nil
+}
+
+// Len retorna o número de registros distintos acumulados
+func (b *Builder) Len() int {
+	return len(b.records)
+}
+
+// WriteTo grava o banco no formato binário
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	keys := make([]string, 0, len(b.records))
+	for key := range b.records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Tabela de strings deduplicada (índice 0 é a string vazia)
+	stringIndex := map[string]uint64{"": 0}
+	stringTable := []string{""}
+	intern := func(value string) uint64 {
+		if idx, ok := stringIndex[value]; ok {
+			return idx
+		}
+		idx := uint64(len(stringTable))
+		stringIndex[value] = idx
+		stringTable = append(stringTable, value)
+		return idx
+	}
+
+	var data bytes.Buffer
+	var blocks []blockIndex
+	var previous string
+	for i, key := range keys {
+		if i%b.blockSize == 0 {
+			blocks = append(blocks, blockIndex{firstKey: key, offset: data.Len()})
+			previous = ""
+		}
+		blocks[len(blocks)-1].count++
+
+		// Front coding: bytes compartilhados com a chave anterior + sufixo
+		shared := sharedPrefix(previous, key)
+		data.WriteByte(byte(shared))
+		data.WriteString(key[shared:])
+
+		record := b.records[key]
+		writeUvarint(&data, intern(record.City))
+		writeUvarint(&data, intern(record.UF))
+		writeUvarint(&data, intern(record.IBGE))
+		previous = key
+	}
+
+	var out bytes.Buffer
+	out.WriteString(fileMagic)
+	out.WriteByte(fileVersion)
+	writeUvarint(&out, uint64(len(keys)))
+	writeUvarint(&out, uint64(len(stringTable)))
+	for _, value := range stringTable {
+		writeUvarint(&out, uint64(len(value)))
+		out.WriteString(value)
+	}
+	writeUvarint(&out, uint64(len(blocks)))
+	for _, block := range blocks {
+		out.WriteString(block.firstKey)
+		writeUvarint(&out, uint64(block.offset))
+		writeUvarint(&out, uint64(block.count))
+	}
+	writeUvarint(&out, uint64(data.Len()))
+	out.Write(data.Bytes())
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// WriteFile grava o banco no caminho informado
+func (b *Builder) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating CEP database: %w", err)
+	}
+	if _, err := b.WriteTo(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing CEP database: %w", err)
+	}
+	return file.Close()
+}
+
+// Open carrega o banco a partir de um arquivo
+func Open(path string) (*Store, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening CEP database: %w", err)
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Load carrega o banco a partir de um reader
+// Cada contagem e tamanho do cabeçalho é conferido com os bytes restantes antes da alocação, para que
+// um arquivo truncado ou corrompido resulte em erro e não em pânico
+func Load(r io.Reader) (*Store, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CEP database: %w", err)
+	}
+	reader := bytes.NewReader(content)
+
+	magic := make([]byte, len(fileMagic)+1)
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return nil, fmt.Errorf("error reading CEP database header: %w", err)
+	}
+	if string(magic[:len(fileMagic)]) != fileMagic {
+		return nil, errors.New("invalid CEP database: bad magic")
+	}
+	if magic[len(fileMagic)] != fileVersion {
+		return nil, fmt.Errorf("unsupported CEP database version %d", magic[len(fileMagic)])
+	}
+
+	store := &Store{}
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, corrupted(err)
+	}
+
+	// Cada string ocupa ao menos o byte do tamanho
+	stringCount, err := readCount(reader, 1)
+	if err != nil {
+		return nil, err
+	}
+	store.strings = make([]string, 0, stringCount)
+	for i := uint64(0); i < stringCount; i++ {
+		value, err := readString(reader)
+		if err != nil {
+			return nil, err
+		}
+		store.strings = append(store.strings, value)
+	}
+
+	// Cada bloco ocupa ao menos a chave e dois varints (offset e contagem)
+	blockCount, err := readCount(reader, keyLength+2)
+	if err != nil {
+		return nil, err
+	}
+	store.blocks = make([]blockIndex, 0, blockCount)
+	key := make([]byte, keyLength)
+	for i := uint64(0); i < blockCount; i++ {
+		if _, err := io.ReadFull(reader, key); err != nil {
+			return nil, corrupted(err)
+		}
+		offset, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, corrupted(err)
+		}
+		blockLen, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, corrupted(err)
+		}
+		if offset > uint64(len(content)) || blockLen > count {
+			return nil, corrupted(errors.New("block out of range"))
+		}
+		store.blocks = append(store.blocks, blockIndex{firstKey: string(key), offset: int(offset), count: int(blockLen)})
+	}
+
+	dataLen, err := readCount(reader, 1)
+	if err != nil {
+		return nil, err
+	}
+	store.data = make([]byte, dataLen)
+	if _, err := io.ReadFull(reader, store.data); err != nil {
+		return nil, corrupted(err)
+	}
+
+	// Cada registro ocupa ao menos o byte do prefixo e as três referências
+	if count > dataLen/4 {
+		return nil, corrupted(errors.New("record count out of range"))
+	}
+	store.count = int(count)
+	for _, block := range store.blocks {
+		if block.offset > len(store.data) {
+			return nil, corrupted(errors.New("block offset out of range"))
+		}
+	}
+
+	return store, nil
+}
+
+// Len retorna o número de CEPs do banco
+func (s *Store) Len() int {
+	return s.count
+}
+
+// Get busca um CEP (com ou sem hífen) no banco
+func (s *Store) Get(cep string) (Record, bool) {
+	key := utils.NormalizeCEP(cep)
+	if len(key) != keyLength || len(s.blocks) == 0 {
+		return Record{}, false
+	}
+
+	// Último bloco cuja primeira chave é <= key
+	i := sort.Search(len(s.blocks), func(i int) bool { return s.blocks[i].firstKey > key }) - 1
+	if i < 0 {
+		return Record{}, false
+	}
+	block := s.blocks[i]
+
+	data := s.data[block.offset:]
+	current := make([]byte, 0, keyLength)
+	pos := 0
+	for n := 0; n < block.count; n++ {
+		if pos >= len(data) {
+			return Record{}, false
+		}
+		shared := int(data[pos])
+		pos++
+		suffixLen := keyLength - shared
+		if shared > len(current) || suffixLen < 0 || pos+suffixLen > len(data) {
+			return Record{}, false
+		}
+		current = append(current[:shared], data[pos:pos+suffixLen]...)
+		pos += suffixLen
+
+		var refs [3]uint64
+		for j := range refs {
+			value, size := binary.Uvarint(data[pos:])
+			if size <= 0 {
+				return Record{}, false
+			}
+			refs[j] = value
+			pos += size
+		}
+
+		switch compare := string(current); {
+		case compare == key:
+			return Record{CEP: key, City: s.str(refs[0]), UF: s.str(refs[1]), IBGE: s.str(refs[2])}, true
+		case compare > key:
+			return Record{}, false
+		}
+	}
+	return Record{}, false
+}
+
+// str resolve uma referência da tabela de strings
+func (s *Store) str(idx uint64) string {
+	if idx >= uint64(len(s.strings)) {
+		return ""
+	}
+	return s.strings[idx]
+}
+
+// sharedPrefix retorna o tamanho do prefixo comum entre duas chaves
+func sharedPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// writeUvarint grava um inteiro sem sinal em formato varint
+func writeUvarint(buf *bytes.Buffer, value uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], value)
+	buf.Write(tmp[:n])
+}
+
+// readCount lê uma contagem (ou tamanho) cujos itens ocupam ao menos minSize bytes cada e
+// confere se ela cabe nos bytes restantes
+func readCount(reader *bytes.Reader, minSize int) (uint64, error) {
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, corrupted(err)
+	}
+	if count > uint64(reader.Len()/minSize) {
+		return 0, corrupted(fmt.Errorf("length %d exceeds the %d remaining bytes", count, reader.Len()))
+	}
+	return count, nil
+}
+
+// readString lê uma string prefixada pelo tamanho
+func readString(reader *bytes.Reader) (string, error) {
+	size, err := readCount(reader, 1)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		return "", corrupted(err)
+	}
+	return string(buf), nil
+}
+
+// corrupted padroniza os erros de leitura de um arquivo truncado ou inválido
+func corrupted(err error) error {
+	return fmt.Errorf("invalid CEP database: %w", err)
+}
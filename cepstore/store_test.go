@@ -0,0 +1,175 @@
+package cepstore
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildStore grava os registros e carrega o banco resultante
+func buildStore(t *testing.T, records ...Record) *Store {
+	t.Helper()
+	builder := NewBuilder()
+	for _, record := range records {
+		require.NoError(t, builder.Add(record))
+	}
+	var buf bytes.Buffer
+	_, err := builder.WriteTo(&buf)
+	require.NoError(t, err)
+
+	store, err := Load(&buf)
+	require.NoError(t, err)
+	return store
+}
+
+func TestStore_Get(t *testing.T) {
+	store := buildStore(t,
+		Record{CEP: "01310-100", City: "São Paulo", UF: "SP", IBGE: "3550308"},
+		Record{CEP: "20040020", City: "Rio de Janeiro", UF: "rj", IBGE: "3304557"},
+		Record{CEP: "01310-200", City: "São Paulo", UF: "SP", IBGE: "3550308"},
+	)
+
+	assert.Equal(t, 3, store.Len())
+
+	record, ok := store.Get("01310100")
+	require.True(t, ok)
+	assert.Equal(t, Record{CEP: "01310100", City: "São Paulo", UF: "SP", IBGE: "3550308"}, record)
+
+	record, ok = store.Get("20040-020")
+	require.True(t, ok)
+	assert.Equal(t, "Rio de Janeiro", record.City)
+	assert.Equal(t, "RJ", record.UF)
+
+	for _, missing := range []string{"01310150", "00000000", "99999999", "abc"} {
+		_, ok := store.Get(missing)
+		assert.False(t, ok, missing)
+	}
+}
+
+func TestStore_ManyBlocks(t *testing.T) {
+	builder := NewBuilder()
+	// Faixa contínua em SP, atravessando vários blocos
+	for i := 0; i < 10*DefaultBlockSize+7; i++ {
+		cep := fmt.Sprintf("%08d", 1000000+i*3)
+		require.NoError(t, builder.Add(Record{CEP: cep, City: fmt.Sprintf("Cidade %d", i%5), UF: "SP"}))
+	}
+
+	var buf bytes.Buffer
+	_, err := builder.WriteTo(&buf)
+	require.NoError(t, err)
+
+	// A compressão de prefixo mantém cada registro bem abaixo do tamanho bruto (8 bytes de chave + valor)
+	assert.Less(t, buf.Len(), builder.Len()*8)
+
+	store, err := Load(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, builder.Len(), store.Len())
+
+	for i := 0; i < builder.Len(); i++ {
+		cep := fmt.Sprintf("%08d", 1000000+i*3)
+		record, ok := store.Get(cep)
+		require.True(t, ok, cep)
+		assert.Equal(t, fmt.Sprintf("Cidade %d", i%5), record.City)
+
+		_, ok = store.Get(fmt.Sprintf("%08d", 1000000+i*3+1))
+		assert.False(t, ok)
+	}
+}
+
+func TestBuilder_Add_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{name: "Invalid CEP", record: Record{CEP: "1234", City: "São Paulo", UF: "SP"}},
+		{name: "Missing city", record: Record{CEP: "01310100", UF: "SP"}},
+		{name: "Invalid UF", record: Record{CEP: "01310100", City: "São Paulo", UF: "XX"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, NewBuilder().Add(tt.record))
+		})
+	}
+}
+
+func TestBuilder_Add_DuplicateReplaces(t *testing.T) {
+	store := buildStore(t,
+		Record{CEP: "01310100", City: "Antiga", UF: "SP"},
+		Record{CEP: "01310-100", City: "São Paulo", UF: "SP"},
+	)
+
+	assert.Equal(t, 1, store.Len())
+	record, ok := store.Get("01310100")
+	require.True(t, ok)
+	assert.Equal(t, "São Paulo", record.City)
+}
+
+func TestOpen_WriteFile(t *testing.T) {
+	builder := NewBuilder()
+	require.NoError(t, builder.Add(Record{CEP: "70040010", City: "Brasília", UF: "DF", IBGE: "5300108"}))
+
+	path := filepath.Join(t.TempDir(), "ceps.db")
+	require.NoError(t, builder.WriteFile(path))
+
+	store, err := Open(path)
+	require.NoError(t, err)
+	record, ok := store.Get("70040-010")
+	require.True(t, ok)
+	assert.Equal(t, "Brasília", record.City)
+
+	_, err = Open(filepath.Join(t.TempDir(), "missing.db"))
+	assert.Error(t, err)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	builder := NewBuilder()
+	require.NoError(t, builder.Add(Record{CEP: "70040010", City: "Brasília", UF: "DF"}))
+	var buf bytes.Buffer
+	_, err := builder.WriteTo(&buf)
+	require.NoError(t, err)
+	valid := buf.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "Bad magic", data: append([]byte("NOTACEPS"), valid[8:]...)},
+		{name: "Unsupported version", data: append(append([]byte(fileMagic), 99), valid[9:]...)},
+		{name: "Truncated", data: valid[:len(valid)-3]},
+		{name: "Huge string count", data: header(1, 1<<62)},
+		{name: "Huge string length", data: header(1, 1, 1<<62)},
+		{name: "Huge block count", data: header(1, 1, 0, 1<<62)},
+		{name: "Huge data length", data: header(1, 1, 0, 0, 1<<62)},
+		{name: "Record count larger than data", data: header(1<<40, 1, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(bytes.NewReader(tt.data))
+			assert.Error(t, err)
+		})
+	}
+
+	// Qualquer truncamento resulta em erro, nunca em pânico
+	for n := 0; n < len(valid); n++ {
+		_, err := Load(bytes.NewReader(valid[:n]))
+		assert.Error(t, err, "truncated at %d bytes", n)
+	}
+}
+
+// header monta um arquivo com o cabeçalho válido seguido dos varints informados
+func header(values ...uint64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fileMagic)
+	buf.WriteByte(fileVersion)
+	for _, value := range values {
+		writeUvarint(&buf, value)
+	}
+	return buf.Bytes()
+}
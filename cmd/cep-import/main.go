@@ -0,0 +1,75 @@
+// Comando cep-import converte um dump CSV/JSON de CEPs no banco local usado pelo modo offline
+//
+// Uso:
+//
+//	go run ./cmd/cep-import -input ceps.csv -output ceps.db
+//	go run ./cmd/cep-import -input ceps.json -format json -output ceps.db
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"weather-cep-api/cepstore"
+)
+
+func main() {
+	input := flag.String("input", "", "arquivo CSV/JSON com os CEPs (\"-\" para stdin)")
+	output := flag.String("output", "ceps.db", "arquivo do banco local a ser gerado")
+	format := flag.String("format", "", "formato da entrada: csv ou json (padrão: pela extensão)")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	imported, err := run(*input, *output, *format)
+	if err != nil {
+		log.Fatalf("Erro ao importar CEPs: %v", err)
+	}
+	log.Printf("%d CEPs importados para %s", imported, *output)
+}
+
+// run importa a entrada e grava o banco, retornando o número de CEPs distintos
+func run(input, output, format string) (int, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(input)), ".")
+		if format == "ndjson" || format == "jsonl" {
+			format = "json"
+		}
+	}
+
+	var reader io.Reader = os.Stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	builder := cepstore.NewBuilder()
+	var err error
+	switch format {
+	case "csv":
+		_, err = cepstore.ImportCSV(reader, builder)
+	case "json":
+		_, err = cepstore.ImportJSON(reader, builder)
+	default:
+		return 0, fmt.Errorf("unsupported format %q (use -format csv or -format json)", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := builder.WriteFile(output); err != nil {
+		return 0, err
+	}
+	return builder.Len(), nil
+}
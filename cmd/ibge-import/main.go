@@ -0,0 +1,58 @@
+// Comando ibge-import gera a tabela de municípios embutida (ibge/municipios.csv) a partir de uma
+// tabela pública com código IBGE, nome e coordenadas de todos os municípios
+//
+// Uso:
+//
+//	curl -sL https://raw.githubusercontent.com/kelvins/municipios-brasileiros/main/csv/municipios.csv -o municipios.csv
+//	go run ./cmd/ibge-import -input municipios.csv -output ibge/municipios.csv
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"weather-cep-api/ibge"
+)
+
+func main() {
+	input := flag.String("input", "", "CSV com as colunas codigo_ibge, nome, latitude e longitude (\"-\" para stdin)")
+	output := flag.String("output", "ibge/municipios.csv", "arquivo da tabela a ser gerada")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	imported, err := run(*input, *output)
+	if err != nil {
+		log.Fatalf("Erro ao importar municípios: %v", err)
+	}
+	log.Printf("%d municípios importados para %s", imported, *output)
+}
+
+// run converte a entrada e grava a tabela, retornando o número de municípios
+// A tabela gerada é validada como a embutida antes de substituir o arquivo de saída
+func run(input, output string) (int, error) {
+	var reader io.Reader = os.Stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var buf bytes.Buffer
+	imported, err := ibge.Import(reader, &buf)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := ibge.Parse(bytes.NewReader(buf.Bytes())); err != nil {
+		return 0, err
+	}
+	return imported, os.WriteFile(output, buf.Bytes(), 0o644)
+}
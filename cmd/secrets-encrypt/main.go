@@ -0,0 +1,84 @@
+// Comando secrets-encrypt gera o arquivo de segredos criptografado lido pela API (SECRETS_FILE)
+//
+// A entrada tem uma configuração por linha, no formato do .env (ex: WEATHER_API_KEY=...). A chave
+// vem de SECRETS_KEY, para não ficar no histórico do shell.
+//
+// Uso:
+//
+//	go run ./cmd/secrets-encrypt -generate-key
+//	SECRETS_KEY=... go run ./cmd/secrets-encrypt -input secrets.env -output secrets.enc
+//	SECRETS_KEY=... go run ./cmd/secrets-encrypt -decrypt -input secrets.enc
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"weather-cep-api/secrets"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	input := flag.String("input", "-", "arquivo de entrada (\"-\" para stdin)")
+	output := flag.String("output", "-", "arquivo de saída (\"-\" para stdout)")
+	decrypt := flag.Bool("decrypt", false, "descriptografa a entrada em vez de criptografar")
+	generateKey := flag.Bool("generate-key", false, "gera uma chave nova para SECRETS_KEY e sai")
+	flag.Parse()
+
+	if *generateKey {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			log.Fatalf("Erro ao gerar chave: %v", err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	if err := run(*input, *output, os.Getenv("SECRETS_KEY"), *decrypt); err != nil {
+		log.Fatalf("Erro: %v", err)
+	}
+}
+
+// run criptografa ou descriptografa a entrada com a chave em base64
+func run(input, output, encodedKey string, decrypt bool) error {
+	key, err := secrets.ParseKey(encodedKey)
+	if err != nil {
+		return fmt.Errorf("invalid SECRETS_KEY: %w", err)
+	}
+
+	var data []byte
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return err
+	}
+
+	var result string
+	if decrypt {
+		plaintext, err := secrets.Decrypt(key, string(data))
+		if err != nil {
+			return err
+		}
+		result = string(plaintext)
+	} else {
+		// Confere o formato antes de criptografar: o erro aparece agora, não na inicialização da API
+		if _, err := godotenv.Unmarshal(string(data)); err != nil {
+			return fmt.Errorf("invalid input: %w", err)
+		}
+		if result, err = secrets.Encrypt(key, data); err != nil {
+			return err
+		}
+	}
+
+	if output == "-" {
+		_, err = io.WriteString(os.Stdout, result)
+		return err
+	}
+	return os.WriteFile(output, []byte(result), 0o600)
+}
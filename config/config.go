@@ -0,0 +1,583 @@
+// Package config centraliza a configuração da API
+//
+// Cada configuração tem um nome (o da variável de ambiente, ex: CEP_CACHE_TTL) e pode vir,
+// em ordem crescente de precedência, do valor padrão, de um arquivo YAML ou TOML
+// (--config ou CONFIG_FILE), do arquivo .env, das variáveis de ambiente e das flags da
+// linha de comando. Todos os valores são validados na inicialização e os erros são
+// reportados juntos; o resultado é injetado nos construtores dos serviços.
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"weather-cep-api/auth"
+	"weather-cep-api/breaker"
+	"weather-cep-api/cache"
+	"weather-cep-api/health"
+	"weather-cep-api/logging"
+	"weather-cep-api/models"
+	"weather-cep-api/ratelimit"
+	"weather-cep-api/secrets"
+	"weather-cep-api/server"
+	"weather-cep-api/services"
+	"weather-cep-api/tracing"
+	"weather-cep-api/warmup"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+)
+
+// Source identifica de onde veio o valor de uma configuração
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceDotEnv  Source = ".env"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+
+	// Origens dos segredos (ver pacote secrets), entre o ambiente e o .env na precedência
+	SourceSecretFile    Source = "secret-file"
+	SourceVault         Source = "vault"
+	SourceEncryptedFile Source = "encrypted-file"
+)
+
+// Geocodificadores reversos aceitos em REVERSE_GEOCODER
+const (
+	GeocoderNominatim = "nominatim"
+	GeocoderLocal     = "local"
+	GeocoderNone      = "none"
+)
+
+// Config é a configuração efetiva da API
+type Config struct {
+	Port          int
+	GinMode       string
+	WeatherAPIKey string
+	// WeatherAPIKeys são as chaves adicionais do pool da WeatherAPI (ver WeatherKeys)
+	WeatherAPIKeys []string
+	WeatherKeyPool services.KeyPoolConfig
+	AdminToken     string
+	Auth           auth.Config
+	// CORSOrigins são as origens aceitas pelo CORS ("*" aceita qualquer uma)
+	CORSOrigins []string
+	// TrustedProxies são os proxies cujo X-Forwarded-For define o IP do cliente (vazio usa o IP da conexão)
+	TrustedProxies []string
+	// TrustedPlatform é o cabeçalho com o IP do cliente preenchido pela plataforma ("" desativa)
+	TrustedPlatform string
+	// WatchInterval é o intervalo de verificação do arquivo de configuração e do .env (0 desativa)
+	WatchInterval time.Duration
+
+	Log     logging.Config
+	Tracing tracing.Config
+
+	RedisURL     string
+	CEPCache     cache.Config
+	WeatherCache cache.Config
+	RateLimit    ratelimit.Config
+
+	CEPDatabase        string
+	CEPOfflineFallback bool
+	IBGEDataset        string
+	ReverseGeocoder    string
+
+	Secrets   secrets.Config
+	Warmup    warmup.Config
+	Breaker   breaker.Config
+	Readiness health.Config
+	Shutdown  server.Config
+
+	// File é o arquivo de configuração carregado ("" quando não há)
+	File string
+	// PrintConfig pede para exibir a configuração efetiva e sair (--print-config)
+	PrintConfig bool
+
+	sources map[string]Source
+}
+
+// Options descreve as fontes usadas por LoadWith
+type Options struct {
+	// Args são os argumentos da linha de comando (sem o nome do programa)
+	Args []string
+	// LookupEnv consulta as variáveis de ambiente (os.LookupEnv)
+	LookupEnv func(key string) (string, bool)
+	// Setenv exporta as variáveis do .env que não são configurações da API (ex: OTEL_EXPORTER_OTLP_ENDPOINT,
+	// lida diretamente pelo exportador); nil não exporta
+	Setenv func(key, value string) error
+	// DotEnvFile é o caminho do .env (arquivo inexistente é ignorado; "" não lê)
+	DotEnvFile string
+}
+
+// ProcessOptions descreve as fontes do processo: argumentos, variáveis de ambiente e o .env do diretório atual
+func ProcessOptions(args []string) Options {
+	return Options{
+		Args:       args,
+		LookupEnv:  os.LookupEnv,
+		Setenv:     os.Setenv,
+		DotEnvFile: ".env",
+	}
+}
+
+// Load carrega a configuração do processo (ver ProcessOptions)
+func Load(args []string) (*Config, error) {
+	return LoadWith(ProcessOptions(args))
+}
+
+// LoadWith carrega a configuração das fontes informadas
+// Retorna flag.ErrHelp quando a ajuda foi pedida (-h ou --help)
+func LoadWith(opts Options) (*Config, error) {
+	flags := flag.NewFlagSet("weather-cep-api", flag.ContinueOnError)
+	configFile := flags.String("config", "", "arquivo de configuração YAML ou TOML (padrão: CONFIG_FILE)")
+	printConfig := flags.Bool("print-config", false, "exibe a configuração efetiva (sem segredos) e sai")
+	for _, s := range settings {
+		flags.String(flagName(s.key), "", s.usage)
+	}
+	if err := flags.Parse(opts.Args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument: %q", flags.Arg(0))
+	}
+	flagValues := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		flagValues[f.Name] = f.Value.String()
+	})
+
+	dotenv, err := readDotEnv(opts.DotEnvFile)
+	if err != nil {
+		return nil, err
+	}
+	lookupEnv := func(key string) (string, bool) {
+		if value, ok := opts.LookupEnv(key); ok && value != "" {
+			return value, true
+		}
+		return "", false
+	}
+	if opts.Setenv != nil {
+		for key, value := range dotenv {
+			if _, known := lookupSetting(key); known {
+				continue
+			}
+			if _, ok := lookupEnv(key); !ok {
+				if err := opts.Setenv(key, value); err != nil {
+					return nil, fmt.Errorf("error exporting %s from %s: %w", key, opts.DotEnvFile, err)
+				}
+			}
+		}
+	}
+
+	path := *configFile
+	if path == "" {
+		if value, ok := lookupEnv("CONFIG_FILE"); ok {
+			path = value
+		} else {
+			path = dotenv["CONFIG_FILE"]
+		}
+	}
+	var fileValues map[string]string
+	if path != "" {
+		if fileValues, err = readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// Precedência: flag > ambiente > segredos > .env > arquivo > padrão
+	raw := make(map[string]string, len(settings))
+	sources := make(map[string]Source, len(settings))
+	for _, s := range settings {
+		if value, ok := flagValues[flagName(s.key)]; ok {
+			raw[s.key], sources[s.key] = value, SourceFlag
+		} else if value, ok := lookupEnv(s.key); ok {
+			raw[s.key], sources[s.key] = value, SourceEnv
+		} else if value := dotenv[s.key]; value != "" {
+			raw[s.key], sources[s.key] = value, SourceDotEnv
+		} else if value := fileValues[s.key]; value != "" {
+			raw[s.key], sources[s.key] = value, SourceFile
+		} else {
+			sources[s.key] = SourceDefault
+		}
+	}
+
+	// Segredos de <NOME>_FILE, do Vault e do arquivo criptografado
+	lookupSecretFile := func(key string) (string, bool) {
+		if value, ok := lookupEnv(key); ok {
+			return value, true
+		}
+		if value := dotenv[key]; value != "" {
+			return value, true
+		}
+		value, ok := fileValues[key]
+		return value, ok
+	}
+	if err := resolveSecrets(raw, sources, lookupSecretFile); err != nil {
+		return nil, err
+	}
+
+	cfg, err := parse(func(key string) string { return raw[key] })
+	if err != nil {
+		return nil, err
+	}
+	cfg.File = path
+	cfg.PrintConfig = *printConfig
+	cfg.sources = sources
+	return cfg, nil
+}
+
+// readDotEnv lê o .env sem alterar o ambiente do processo
+func readDotEnv(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	values, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// parse converte e valida todos os valores, reportando todos os erros de uma vez
+func parse(get func(string) string) (*Config, error) {
+	cfg := &Config{
+		Port:               8080,
+		GinMode:            gin.DebugMode,
+		WeatherAPIKey:      get("WEATHER_API_KEY"),
+		AdminToken:         get("ADMIN_TOKEN"),
+		RedisURL:           get("REDIS_URL"),
+		CEPDatabase:        get("CEP_DATABASE"),
+		CEPOfflineFallback: true,
+		IBGEDataset:        get("IBGE_DATASET"),
+		ReverseGeocoder:    GeocoderNominatim,
+		CORSOrigins:        []string{"*"},
+		WatchInterval:      5 * time.Second,
+	}
+
+	var errs []error
+	add := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if raw := strings.TrimSpace(get("PORT")); raw != "" {
+		port, err := strconv.Atoi(raw)
+		if err != nil || port < 1 || port > 65535 {
+			add(fmt.Errorf("invalid PORT: %q", raw))
+		}
+		cfg.Port = port
+	}
+	if raw := strings.TrimSpace(get("GIN_MODE")); raw != "" {
+		switch raw {
+		case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+			cfg.GinMode = raw
+		default:
+			add(fmt.Errorf("invalid GIN_MODE: %q (use debug, release or test)", raw))
+		}
+	}
+	for _, key := range strings.Split(get("WEATHER_API_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			cfg.WeatherAPIKeys = append(cfg.WeatherAPIKeys, key)
+		}
+	}
+	if raw := strings.TrimSpace(get("CORS_ALLOWED_ORIGINS")); raw != "" {
+		origins, err := parseOrigins(raw)
+		add(err)
+		cfg.CORSOrigins = origins
+	}
+	if raw := strings.TrimSpace(get("TRUSTED_PROXIES")); raw != "" {
+		proxies, err := parseProxies(raw)
+		add(err)
+		cfg.TrustedProxies = proxies
+	}
+	if raw := strings.TrimSpace(get("TRUSTED_PLATFORM")); raw != "" {
+		switch strings.ToLower(raw) {
+		case "cloudflare":
+			cfg.TrustedPlatform = gin.PlatformCloudflare
+		case "appengine":
+			cfg.TrustedPlatform = gin.PlatformGoogleAppEngine
+		default:
+			cfg.TrustedPlatform = http.CanonicalHeaderKey(raw)
+		}
+	}
+	if raw := strings.TrimSpace(get("CONFIG_WATCH_INTERVAL")); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval < 0 {
+			add(fmt.Errorf("invalid CONFIG_WATCH_INTERVAL: %q", raw))
+		}
+		cfg.WatchInterval = interval
+	}
+	if raw := strings.TrimSpace(get("CEP_OFFLINE_FALLBACK")); raw != "" {
+		fallback, err := strconv.ParseBool(raw)
+		if err != nil {
+			add(fmt.Errorf("invalid CEP_OFFLINE_FALLBACK: %q", raw))
+		}
+		cfg.CEPOfflineFallback = fallback
+	}
+	if raw := strings.TrimSpace(get("REVERSE_GEOCODER")); raw != "" {
+		switch raw {
+		case GeocoderNominatim, GeocoderLocal, GeocoderNone:
+			cfg.ReverseGeocoder = raw
+		default:
+			add(fmt.Errorf("invalid REVERSE_GEOCODER: %q (use nominatim, local or none)", raw))
+		}
+	}
+
+	var err error
+	cfg.Log, err = logging.ConfigFrom(get)
+	add(err)
+	cfg.Tracing, err = tracing.ConfigFrom(get)
+	add(err)
+	cfg.CEPCache, err = cache.ConfigFrom("CEP", services.DefaultCEPCacheTTL, get)
+	add(err)
+	cfg.WeatherCache, err = cache.ConfigFrom("WEATHER", services.DefaultWeatherCacheTTL, get)
+	add(err)
+	cfg.RateLimit, err = ratelimit.ConfigFrom(get)
+	add(err)
+	cfg.WeatherKeyPool, err = services.KeyPoolConfigFrom(get)
+	add(err)
+	cfg.Auth, err = auth.ConfigFrom(get)
+	add(err)
+	if cfg.Auth.RequireAPIKey && cfg.AdminToken == "" && cfg.Auth.KeysFile == "" && !cfg.Auth.JWT.Enabled() {
+		// Sem ADMIN_TOKEN, arquivo ou token JWT com o escopo admin não haveria como emitir a primeira chave
+		add(errors.New("API_KEY_AUTH requires ADMIN_TOKEN, API_KEYS_FILE or JWT authentication"))
+	}
+	cfg.Secrets, err = secrets.ConfigFrom(get)
+	add(err)
+	cfg.Warmup, err = warmup.ConfigFrom(get)
+	add(err)
+	cfg.Breaker, err = breaker.ConfigFrom(get)
+	add(err)
+	cfg.Readiness, err = health.ConfigFrom(get)
+	add(err)
+	cfg.Shutdown, err = server.ConfigFrom(get)
+	add(err)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// parseProxies valida a lista de proxies confiáveis (IPs ou redes CIDR, separados por vírgula)
+func parseProxies(raw string) ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(raw, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %q is not an IP or CIDR", proxy)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// parseOrigins valida a lista de origens do CORS ("*" ou scheme://host[:porta], separadas por vírgula)
+func parseOrigins(raw string) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(raw, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin != "*" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+				return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %q is not an origin (use scheme://host[:port] or *)", origin)
+			}
+			origin = strings.TrimSuffix(origin, "/")
+		}
+		origins = append(origins, origin)
+	}
+	if len(origins) == 0 {
+		return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %q", raw)
+	}
+	return origins, nil
+}
+
+// formatScopeMap formata JWT_SCOPE_MAP como lido por auth.ConfigFrom, em ordem estável
+func formatScopeMap(scopeMap map[string][]auth.Scope) string {
+	var pairs []string
+	for value, scopes := range scopeMap {
+		for _, scope := range scopes {
+			pairs = append(pairs, value+"="+string(scope))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// flagName converte o nome da configuração no nome da flag (CEP_CACHE_TTL -> cep-cache-ttl)
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// WeatherKeys retorna todas as chaves do pool da WeatherAPI: WEATHER_API_KEY seguida de WEATHER_API_KEYS
+func (c *Config) WeatherKeys() []string {
+	if c.WeatherAPIKey == "" {
+		return c.WeatherAPIKeys
+	}
+	return append([]string{c.WeatherAPIKey}, c.WeatherAPIKeys...)
+}
+
+// Check é a verificação de prontidão da configuração: a API sobe sem a chave da WeatherAPI,
+// mas não fica pronta enquanto nenhuma for definida
+func (c *Config) Check(_ context.Context) error {
+	if len(c.WeatherKeys()) == 0 {
+		return errors.New("WEATHER_API_KEY is not set")
+	}
+	return nil
+}
+
+// Source retorna de onde veio o valor da configuração
+func (c *Config) Source(key string) Source {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// values formata a configuração efetiva no formato aceito pelas variáveis de ambiente
+func (c *Config) values() map[string]string {
+	values := map[string]string{
+		"PORT":            strconv.Itoa(c.Port),
+		"GIN_MODE":        c.GinMode,
+		"WEATHER_API_KEY": c.WeatherAPIKey,
+		"ADMIN_TOKEN":     c.AdminToken,
+		"API_KEY_AUTH":    strconv.FormatBool(c.Auth.RequireAPIKey),
+		"API_KEYS_FILE":   c.Auth.KeysFile,
+
+		"JWT_ISSUER":         c.Auth.JWT.Issuer,
+		"JWT_AUDIENCE":       c.Auth.JWT.Audience,
+		"JWT_JWKS_URL":       c.Auth.JWT.JWKSURL,
+		"JWT_HMAC_SECRET":    c.Auth.JWT.HMACSecret,
+		"JWT_JWKS_CACHE_TTL": c.Auth.JWT.JWKSCacheTTL.String(),
+		"JWT_LEEWAY":         c.Auth.JWT.Leeway.String(),
+		"JWT_SCOPES_CLAIM":   c.Auth.JWT.ScopesClaim,
+		"JWT_SCOPE_MAP":      formatScopeMap(c.Auth.JWT.ScopeMap),
+
+		"WEATHER_API_KEYS":           strings.Join(c.WeatherAPIKeys, ","),
+		"WEATHER_API_KEY_STRATEGY":   c.WeatherKeyPool.Strategy,
+		"WEATHER_API_KEY_QUOTA":      strconv.FormatInt(c.WeatherKeyPool.MonthlyQuota, 10),
+		"WEATHER_API_KEY_QUARANTINE": c.WeatherKeyPool.Quarantine.String(),
+
+		"CORS_ALLOWED_ORIGINS":  strings.Join(c.CORSOrigins, ","),
+		"TRUSTED_PROXIES":       strings.Join(c.TrustedProxies, ","),
+		"TRUSTED_PLATFORM":      c.TrustedPlatform,
+		"CONFIG_WATCH_INTERVAL": c.WatchInterval.String(),
+
+		"LOG_LEVEL":  strings.ToLower(c.Log.Level.String()),
+		"LOG_FORMAT": string(c.Log.Format),
+
+		"OTEL_TRACES_EXPORTER":    string(c.Tracing.Exporter),
+		"OTEL_SERVICE_NAME":       c.Tracing.ServiceName,
+		"OTEL_TRACES_SAMPLER_ARG": strconv.FormatFloat(c.Tracing.SampleRatio, 'f', -1, 64),
+
+		"REDIS_URL": c.RedisURL,
+
+		"RATE_LIMIT_RPS":           strconv.FormatFloat(c.RateLimit.Rate, 'f', -1, 64),
+		"RATE_LIMIT_BURST":         strconv.Itoa(c.RateLimit.Burst),
+		"RATE_LIMIT_DAILY_QUOTA":   strconv.FormatInt(c.RateLimit.DailyQuota, 10),
+		"RATE_LIMIT_MONTHLY_QUOTA": strconv.FormatInt(c.RateLimit.MonthlyQuota, 10),
+		"RATE_LIMIT_BACKEND":       string(c.RateLimit.Backend),
+		"RATE_LIMIT_REDIS_URL":     c.RateLimit.RedisURL,
+
+		"CEP_DATABASE":         c.CEPDatabase,
+		"CEP_OFFLINE_FALLBACK": strconv.FormatBool(c.CEPOfflineFallback),
+		"IBGE_DATASET":         c.IBGEDataset,
+		"REVERSE_GEOCODER":     c.ReverseGeocoder,
+
+		"WARMUP_FILE":             c.Warmup.CEPFile,
+		"WARMUP_STATS_FILE":       c.Warmup.StatsFile,
+		"WARMUP_TOP":              strconv.Itoa(c.Warmup.Top),
+		"WARMUP_CONCURRENCY":      strconv.Itoa(c.Warmup.Concurrency),
+		"WARMUP_RATE":             strconv.FormatFloat(c.Warmup.Rate, 'f', -1, 64),
+		"WARMUP_TIMEOUT":          c.Warmup.Timeout.String(),
+		"WARMUP_REFRESH_INTERVAL": c.Warmup.RefreshInterval.String(),
+		"WARMUP_REFRESH_TOP":      strconv.Itoa(c.Warmup.RefreshTop),
+
+		"VAULT_ADDR":               c.Secrets.VaultAddr,
+		"VAULT_TOKEN":              c.Secrets.VaultToken,
+		"VAULT_SECRET_PATH":        c.Secrets.VaultPath,
+		"VAULT_NAMESPACE":          c.Secrets.VaultNamespace,
+		"SECRETS_FILE":             c.Secrets.File,
+		"SECRETS_KEY":              c.Secrets.Key,
+		"SECRETS_REFRESH_INTERVAL": c.Secrets.RefreshInterval.String(),
+
+		"CIRCUIT_BREAKER_THRESHOLD": strconv.Itoa(c.Breaker.Threshold),
+		"CIRCUIT_BREAKER_COOLDOWN":  c.Breaker.Cooldown.String(),
+		"READINESS_TIMEOUT":         c.Readiness.Timeout.String(),
+		"READINESS_PROBE_INTERVAL":  c.Readiness.ProbeInterval.String(),
+		"SHUTDOWN_DRAIN_PERIOD":     c.Shutdown.DrainPeriod.String(),
+		"SHUTDOWN_TIMEOUT":          c.Shutdown.ShutdownTimeout.String(),
+		"SHUTDOWN_CLEANUP_TIMEOUT":  c.Shutdown.CleanupTimeout.String(),
+	}
+	for prefix, cacheCfg := range map[string]cache.Config{"CEP": c.CEPCache, "WEATHER": c.WeatherCache} {
+		values[prefix+"_CACHE_BACKEND"] = string(cacheCfg.Backend)
+		values[prefix+"_CACHE_TTL"] = cacheCfg.TTL.String()
+		values[prefix+"_CACHE_PATH"] = cacheCfg.Path
+		values[prefix+"_CACHE_REDIS_URL"] = cacheCfg.RedisURL
+		values[prefix+"_CACHE_MAX_ENTRIES"] = strconv.Itoa(cacheCfg.MaxEntries)
+	}
+	return values
+}
+
+// Settings retorna a configuração efetiva, sem segredos, na ordem das configurações
+func (c *Config) Settings() []models.ConfigSetting {
+	values := c.values()
+	result := make([]models.ConfigSetting, 0, len(settings))
+	for _, s := range settings {
+		value := values[s.key]
+		if s.secret {
+			value = redact(value)
+		}
+		result = append(result, models.ConfigSetting{Key: s.key, Value: value, Source: string(c.Source(s.key))})
+	}
+	return result
+}
+
+// Response retorna a configuração efetiva no formato da rota de administração
+func (c *Config) Response() models.ConfigResponse {
+	return models.ConfigResponse{File: c.File, Settings: c.Settings()}
+}
+
+// Print escreve a configuração efetiva, sem segredos, no formato KEY=valor
+func (c *Config) Print(w io.Writer) error {
+	for _, s := range c.Settings() {
+		if _, err := fmt.Fprintf(w, "%s=%s # %s\n", s.Key, s.Value, s.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redact esconde um segredo; em URLs (ex: redis://:senha@host) só a senha é escondida
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), logging.Redacted)
+		}
+		return u.String()
+	}
+	return logging.Redacted
+}
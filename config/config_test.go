@@ -0,0 +1,280 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"weather-cep-api/auth"
+	"weather-cep-api/cache"
+	"weather-cep-api/logging"
+	"weather-cep-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapEnv simula as variáveis de ambiente do processo
+func mapEnv(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+// writeFile grava um arquivo temporário e devolve o caminho
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadWith_Defaults(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(nil)})
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, "debug", cfg.GinMode)
+	assert.Equal(t, GeocoderNominatim, cfg.ReverseGeocoder)
+	assert.True(t, cfg.CEPOfflineFallback)
+	assert.Equal(t, cache.BackendMemory, cfg.CEPCache.Backend)
+	assert.Equal(t, 24*time.Hour, cfg.CEPCache.TTL)
+	assert.Equal(t, 5*time.Minute, cfg.WeatherCache.TTL)
+	assert.Equal(t, logging.FormatJSON, cfg.Log.Format)
+	assert.Equal(t, SourceDefault, cfg.Source("PORT"))
+	assert.Empty(t, cfg.File)
+	assert.EqualError(t, cfg.Check(nil), "WEATHER_API_KEY is not set")
+}
+
+func TestLoadWith_Precedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+port: 9000
+log:
+  level: debug
+  format: text
+weather_api_key: from-file
+cep_cache:
+  ttl: 1h
+weather-cache:
+  backend: none
+warmup:
+  rate: 2.5
+`)
+	dotenv := writeFile(t, ".env", "PORT=9100\nLOG_LEVEL=warn\nOTEL_EXPORTER_OTLP_ENDPOINT=http://collector:4318\n")
+	env := map[string]string{
+		"CONFIG_FILE": file,
+		"PORT":        "9200",
+		"GIN_MODE":    "",
+	}
+	exported := map[string]string{}
+
+	cfg, err := LoadWith(Options{
+		Args:       []string{"--port", "9300"},
+		LookupEnv:  mapEnv(env),
+		Setenv:     func(key, value string) error { exported[key] = value; return nil },
+		DotEnvFile: dotenv,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, file, cfg.File)
+	assert.Equal(t, 9300, cfg.Port)
+	assert.Equal(t, SourceFlag, cfg.Source("PORT"))
+	assert.Equal(t, slog.LevelWarn, cfg.Log.Level)
+	assert.Equal(t, SourceDotEnv, cfg.Source("LOG_LEVEL"))
+	assert.Equal(t, logging.FormatText, cfg.Log.Format)
+	assert.Equal(t, SourceFile, cfg.Source("LOG_FORMAT"))
+	assert.Equal(t, "from-file", cfg.WeatherAPIKey)
+	assert.Equal(t, time.Hour, cfg.CEPCache.TTL)
+	assert.Equal(t, cache.BackendNone, cfg.WeatherCache.Backend)
+	assert.Equal(t, 2.5, cfg.Warmup.Rate)
+	// Variável vazia no ambiente não esconde os valores das outras fontes
+	assert.Equal(t, SourceDefault, cfg.Source("GIN_MODE"))
+	assert.NoError(t, cfg.Check(nil))
+
+	// Variáveis do .env que não são configurações da API são exportadas para as bibliotecas
+	assert.Equal(t, map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, exported)
+}
+
+func TestLoadWith_TOMLFile(t *testing.T) {
+	file := writeFile(t, "config.toml", `
+port = 8181
+reverse_geocoder = "local"
+
+[circuit_breaker]
+threshold = 3
+cooldown = "1m"
+`)
+
+	cfg, err := LoadWith(Options{Args: []string{"--config", file}, LookupEnv: mapEnv(nil)})
+	require.NoError(t, err)
+	assert.Equal(t, 8181, cfg.Port)
+	assert.Equal(t, GeocoderLocal, cfg.ReverseGeocoder)
+	assert.Equal(t, 3, cfg.Breaker.Threshold)
+	assert.Equal(t, time.Minute, cfg.Breaker.Cooldown)
+}
+
+func TestLoadWith_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		file     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Every invalid value is reported",
+			env:      map[string]string{"PORT": "http", "LOG_LEVEL": "verbose", "CEP_CACHE_BACKEND": "memcached", "GIN_MODE": "prod"},
+			expected: []string{"invalid PORT", "invalid LOG_LEVEL", "invalid CEP_CACHE_BACKEND", "invalid GIN_MODE"},
+		},
+		{
+			name:     "Invalid flag value",
+			args:     []string{"--reverse-geocoder", "google"},
+			expected: []string{"invalid REVERSE_GEOCODER"},
+		},
+		{
+			name:     "Client authentication without a way to issue keys",
+			env:      map[string]string{"API_KEY_AUTH": "true"},
+			expected: []string{"API_KEY_AUTH requires ADMIN_TOKEN, API_KEYS_FILE or JWT authentication"},
+		},
+		{
+			name:     "Unknown flag",
+			args:     []string{"--colour", "blue"},
+			expected: []string{"flag provided but not defined"},
+		},
+		{
+			name:     "Unknown file key",
+			file:     "config.yaml",
+			content:  "cep_cache:\n  ttl_seconds: 60\n",
+			expected: []string{"unknown setting CEP_CACHE_TTL_SECONDS"},
+		},
+		{
+			name:     "Unsupported file",
+			file:     "config.json",
+			content:  "{}",
+			expected: []string{"unsupported config file"},
+		},
+		{
+			name:     "Malformed file",
+			file:     "config.yaml",
+			content:  "port: [8080",
+			expected: []string{"error parsing config file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tt.env
+			if tt.file != "" {
+				env = map[string]string{"CONFIG_FILE": writeFile(t, tt.file, tt.content)}
+			}
+
+			_, err := LoadWith(Options{Args: tt.args, LookupEnv: mapEnv(env)})
+			require.Error(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestLoadWith_Help(t *testing.T) {
+	_, err := LoadWith(Options{Args: []string{"-h"}, LookupEnv: mapEnv(nil)})
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestConfig_Settings_RedactsSecrets(t *testing.T) {
+	cfg, err := LoadWith(Options{
+		Args: []string{"--print-config"},
+		LookupEnv: mapEnv(map[string]string{
+			"WEATHER_API_KEY":   "abc123",
+			"CEP_CACHE_BACKEND": "redis",
+			"REDIS_URL":         "redis://:s3cret@redis:6379/0",
+		}),
+	})
+	require.NoError(t, err)
+	assert.True(t, cfg.PrintConfig)
+
+	values := make(map[string]string)
+	for _, s := range cfg.Settings() {
+		values[s.Key] = s.Value
+	}
+	assert.Equal(t, "REDACTED", values["WEATHER_API_KEY"])
+	assert.Equal(t, "", values["ADMIN_TOKEN"])
+	assert.Equal(t, "redis://:REDACTED@redis:6379/0", values["REDIS_URL"])
+	assert.Equal(t, "redis://:REDACTED@redis:6379/0", values["CEP_CACHE_REDIS_URL"])
+	assert.Equal(t, "24h0m0s", values["CEP_CACHE_TTL"])
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Print(&out))
+	assert.Contains(t, out.String(), "WEATHER_API_KEY=REDACTED # env\n")
+	assert.Contains(t, out.String(), "PORT=8080 # default\n")
+	assert.NotContains(t, out.String(), "abc123")
+	assert.NotContains(t, out.String(), "s3cret")
+}
+
+func TestConfig_ValuesCoverEverySetting(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(nil)})
+	require.NoError(t, err)
+
+	// Toda configuração registrada precisa aparecer no dump (e vice-versa)
+	values := cfg.values()
+	assert.Len(t, values, len(settings))
+	for _, s := range settings {
+		assert.Contains(t, values, s.key)
+	}
+}
+
+func TestConfig_WeatherKeys(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(map[string]string{
+		"WEATHER_API_KEYS":         "second, third,",
+		"WEATHER_API_KEY_STRATEGY": "quota-aware",
+		"WEATHER_API_KEY_QUOTA":    "1000000",
+	})})
+	require.NoError(t, err)
+
+	// Só as chaves do pool bastam para a API ficar pronta
+	assert.Equal(t, []string{"second", "third"}, cfg.WeatherKeys())
+	assert.NoError(t, cfg.Check(nil))
+	assert.Equal(t, services.KeyPoolConfig{Strategy: services.KeySelectionQuotaAware, MonthlyQuota: 1000000, Quarantine: time.Hour}, cfg.WeatherKeyPool)
+
+	cfg, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"WEATHER_API_KEY": "first", "WEATHER_API_KEYS": "second"})})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, cfg.WeatherKeys())
+	for _, s := range cfg.Settings() {
+		assert.NotContains(t, s.Value, "second")
+	}
+}
+
+func TestLoadWith_JWT(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(map[string]string{
+		"JWT_ISSUER":      "https://auth.example.com",
+		"JWT_AUDIENCE":    "weather-cep-api",
+		"JWT_HMAC_SECRET": "hmac-secret",
+		"JWT_SCOPE_MAP":   "weather.read=temperature, ops=admin,weather.read=forecast",
+	})})
+	require.NoError(t, err)
+	assert.True(t, cfg.Auth.Enabled())
+	assert.Equal(t, []auth.Scope{auth.ScopeTemperature, auth.ScopeForecast}, cfg.Auth.JWT.ScopeMap["weather.read"])
+
+	values := cfg.values()
+	assert.Equal(t, "ops=admin,weather.read=forecast,weather.read=temperature", values["JWT_SCOPE_MAP"])
+	for _, s := range cfg.Settings() {
+		if s.Key == "JWT_HMAC_SECRET" {
+			assert.Equal(t, logging.Redacted, s.Value)
+		}
+	}
+
+	// Com JWT, o escopo admin do token basta para emitir as chaves de clientes
+	_, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{
+		"API_KEY_AUTH":    "true",
+		"JWT_ISSUER":      "https://auth.example.com",
+		"JWT_AUDIENCE":    "weather-cep-api",
+		"JWT_HMAC_SECRET": "hmac-secret",
+	})})
+	require.NoError(t, err)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile lê o arquivo de configuração (YAML ou TOML, pela extensão) e o converte em nomes de configuração
+// Seções aninhadas são unidas por "_": cep_cache: {ttl: 1h} equivale a CEP_CACHE_TTL=1h
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	document := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unsupported config file %s (use .yaml, .yml or .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flatten("", document, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flatten converte as chaves aninhadas em nomes de configuração e rejeita nomes desconhecidos
+func flatten(prefix string, document map[string]interface{}, values map[string]string) error {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch value := document[key].(type) {
+		case map[string]interface{}:
+			if err := flatten(name, value, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			return fmt.Errorf("%s: lists are not supported", name)
+		case nil:
+		default:
+			if _, ok := lookupSetting(name); !ok && !isSecretFileKey(name) {
+				return fmt.Errorf("unknown setting %s", name)
+			}
+			values[name] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
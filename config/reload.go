@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Change descreve uma configuração alterada pela recarga (valores sem segredos)
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Diff retorna as configurações cujo valor efetivo difere em next, na ordem das configurações
+func (c *Config) Diff(next *Config) []Change {
+	oldValues, newValues := c.values(), next.values()
+	var changes []Change
+	for _, s := range settings {
+		if oldValues[s.key] == newValues[s.key] {
+			continue
+		}
+		change := Change{Key: s.key, Old: oldValues[s.key], New: newValues[s.key]}
+		if s.secret {
+			change.Old, change.New = redact(change.Old), redact(change.New)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Reloader mantém a configuração atual e a substitui, sem reiniciar a API, quando o arquivo de
+// configuração ou o .env mudam ou quando o processo recebe SIGHUP
+// Só as configurações reloadable podem mudar: a recarga que altera as demais ou que traz valores
+// inválidos é rejeitada por inteiro e a configuração atual é mantida
+// A exceção são os segredos que exigem reinício (ver restartSecrets): rotacionados no provedor, eles
+// mantêm o valor em vigor, com um aviso, para não bloquear a recarga dos demais
+type Reloader struct {
+	opts    Options
+	current atomic.Pointer[Config]
+
+	mu    sync.Mutex // serializa as recargas e protege hooks, files e pending
+	hooks []func(*Config)
+	// files identifica o estado dos arquivos na última leitura (ver fingerprint)
+	files string
+	// pending são os novos valores dos segredos que aguardam o reinício (o aviso só se repete quando mudam)
+	pending map[string]string
+}
+
+// restartSecrets copiam, da configuração em vigor para a nova, os segredos que só valem depois de reiniciar
+var restartSecrets = map[string]func(dst, src *Config){
+	"ADMIN_TOKEN":             func(dst, src *Config) { dst.AdminToken = src.AdminToken },
+	"JWT_HMAC_SECRET":         func(dst, src *Config) { dst.Auth.JWT.HMACSecret = src.Auth.JWT.HMACSecret },
+	"REDIS_URL":               func(dst, src *Config) { dst.RedisURL = src.RedisURL },
+	"CEP_CACHE_REDIS_URL":     func(dst, src *Config) { dst.CEPCache.RedisURL = src.CEPCache.RedisURL },
+	"WEATHER_CACHE_REDIS_URL": func(dst, src *Config) { dst.WeatherCache.RedisURL = src.WeatherCache.RedisURL },
+	"RATE_LIMIT_REDIS_URL":    func(dst, src *Config) { dst.RateLimit.RedisURL = src.RateLimit.RedisURL },
+}
+
+// keepRestartSecrets mantém em next os segredos em vigor que exigem reinício e retorna os novos valores
+// deles, por configuração
+func keepRestartSecrets(current, next *Config) map[string]string {
+	oldValues, newValues := current.values(), next.values()
+	pending := make(map[string]string)
+	for key, keep := range restartSecrets {
+		if oldValues[key] != newValues[key] {
+			pending[key] = newValues[key]
+			keep(next, current)
+		}
+	}
+	return pending
+}
+
+// NewReloader cria o Reloader a partir da configuração carregada na inicialização e das mesmas fontes
+func NewReloader(initial *Config, opts Options) *Reloader {
+	r := &Reloader{opts: opts}
+	r.current.Store(initial)
+	r.files = r.fingerprint()
+	return r
+}
+
+// Current retorna a configuração em vigor
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// Check é a verificação de prontidão da configuração em vigor (ver Config.Check)
+func (r *Reloader) Check(ctx context.Context) error {
+	return r.Current().Check(ctx)
+}
+
+// OnReload registra uma função chamada com a nova configuração depois de cada recarga com alterações
+// É nela que os serviços em execução recebem os novos valores (ex: WeatherService.SetAPIKey)
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, fn)
+}
+
+// Reload lê novamente todas as fontes e, se a nova configuração for aceita, a coloca em vigor
+// Retorna as alterações aplicadas (nil quando nada mudou)
+func (r *Reloader) Reload() ([]Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.Current()
+	// Os arquivos lidos agora não disparam outra recarga, mesmo que sejam rejeitados
+	r.files = r.fingerprint()
+	next, err := LoadWith(r.opts)
+	if err != nil {
+		return nil, err
+	}
+	// --print-config só vale na inicialização
+	next.PrintConfig = current.PrintConfig
+
+	pending := keepRestartSecrets(current, next)
+	changes := current.Diff(next)
+	if err := validateReload(current, next, changes); err != nil {
+		return nil, err
+	}
+
+	// A troca é atômica: Current passa a devolver a nova configuração de uma vez
+	r.current.Store(next)
+	r.files = r.fingerprint()
+	if len(pending) > 0 && !maps.Equal(pending, r.pending) {
+		var keys []string
+		for _, s := range settings {
+			if _, ok := pending[s.key]; ok {
+				keys = append(keys, s.key)
+			}
+		}
+		slog.Warn("Segredos alterados só valem depois de reiniciar a API; mantendo os valores em vigor", "keys", keys)
+	}
+	r.pending = pending
+	if len(changes) > 0 {
+		for _, hook := range r.hooks {
+			hook(next)
+		}
+	}
+	return changes, nil
+}
+
+// validateReload rejeita as alterações que só valem depois de reiniciar a API
+func validateReload(current, next *Config, changes []Change) error {
+	var errs []error
+	for _, change := range changes {
+		if s, _ := lookupSetting(change.Key); !s.reloadable {
+			errs = append(errs, fmt.Errorf("%s cannot change without a restart", change.Key))
+		}
+	}
+	// TTL 0 desativa o cache, o que só é decidido na inicialização
+	if (current.CEPCache.TTL > 0) != (next.CEPCache.TTL > 0) {
+		errs = append(errs, errors.New("CEP_CACHE_TTL cannot enable or disable the cache without a restart"))
+	}
+	if (current.WeatherCache.TTL > 0) != (next.WeatherCache.TTL > 0) {
+		errs = append(errs, errors.New("WEATHER_CACHE_TTL cannot enable or disable the cache without a restart"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("reload rejected: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Watch recarrega a configuração a cada sinal recebido em signals (SIGHUP) e quando o arquivo de
+// configuração ou o .env mudam, verificados a cada interval (interval <= 0 só atende aos sinais)
+// Se algum segredo veio dos provedores (ver pacote secrets), eles são relidos a cada SECRETS_REFRESH_INTERVAL
+// O resultado de cada recarga é registrado no log; bloqueia até o contexto ser cancelado
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, signals <-chan os.Signal) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	var refresh <-chan time.Time
+	if cfg := r.Current(); cfg.Secrets.RefreshInterval > 0 && cfg.usesSecrets() {
+		ticker := time.NewTicker(cfg.Secrets.RefreshInterval)
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			r.reloadAndLog(sig.String())
+		case <-tick:
+			if r.filesChanged() {
+				r.reloadAndLog("file")
+			}
+		case <-refresh:
+			r.reloadAndLog("secrets")
+		}
+	}
+}
+
+// filesChanged informa se o arquivo de configuração ou o .env mudaram desde a última leitura
+func (r *Reloader) filesChanged() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fingerprint() != r.files
+}
+
+// reloadAndLog executa a recarga e registra as alterações ou o motivo da rejeição
+// A releitura periódica dos segredos sem alterações só aparece no nível debug
+func (r *Reloader) reloadAndLog(trigger string) {
+	level := slog.LevelInfo
+	if trigger == "secrets" {
+		level = slog.LevelDebug
+	}
+	slog.Log(context.Background(), level, "Recarregando configuração", "trigger", trigger)
+
+	changes, err := r.Reload()
+	switch {
+	case err != nil:
+		slog.Error("Recarga da configuração rejeitada", "trigger", trigger, "error", err)
+	case len(changes) == 0:
+		slog.Log(context.Background(), level, "Configuração sem alterações", "trigger", trigger)
+	default:
+		slog.Info("Configuração recarregada", "trigger", trigger, "changes", changes)
+	}
+}
+
+// fingerprint identifica o estado atual do arquivo de configuração e do .env (tamanho e data de modificação)
+func (r *Reloader) fingerprint() string {
+	var result string
+	for _, path := range []string{r.Current().File, r.opts.DotEnvFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			result += fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			result += path + ":missing;"
+		}
+	}
+	return result
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestReloader carrega a configuração do arquivo informado e cria o Reloader sobre as mesmas fontes
+func newTestReloader(t *testing.T, path string) *Reloader {
+	t.Helper()
+	opts := Options{Args: []string{"--config", path}, LookupEnv: mapEnv(nil)}
+	cfg, err := LoadWith(opts)
+	require.NoError(t, err)
+	return NewReloader(cfg, opts)
+}
+
+func TestReloader_Reload(t *testing.T) {
+	path := writeFile(t, "config.yaml", "weather_api_key: old-key\ncep_cache:\n  ttl: 1h\n")
+	reloader := newTestReloader(t, path)
+
+	var applied []*Config
+	reloader.OnReload(func(cfg *Config) { applied = append(applied, cfg) })
+
+	// Sem alterações as funções de recarga não são chamadas
+	changes, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, applied)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+weather_api_key: new-key
+cors_allowed_origins: https://app.example.com, https://admin.example.com/
+cep_cache:
+  ttl: 2h
+`), 0o600))
+	changes, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Key: "WEATHER_API_KEY", Old: "REDACTED", New: "REDACTED"},
+		{Key: "CORS_ALLOWED_ORIGINS", Old: "*", New: "https://app.example.com,https://admin.example.com"},
+		{Key: "CEP_CACHE_TTL", Old: "1h0m0s", New: "2h0m0s"},
+	}, changes)
+
+	require.Len(t, applied, 1)
+	assert.Same(t, reloader.Current(), applied[0])
+	assert.Equal(t, "new-key", reloader.Current().WeatherAPIKey)
+	assert.Equal(t, 2*time.Hour, reloader.Current().CEPCache.TTL)
+	assert.NoError(t, reloader.Check(context.Background()))
+}
+
+func TestReloader_Reload_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Invalid value",
+			content:  "weather_api_key: new-key\ncors_allowed_origins: app.example.com\n",
+			expected: []string{"invalid CORS_ALLOWED_ORIGINS"},
+		},
+		{
+			name:     "Setting that requires a restart",
+			content:  "weather_api_key: new-key\nport: 9090\ncep_cache:\n  backend: none\n",
+			expected: []string{"PORT cannot change without a restart", "CEP_CACHE_BACKEND cannot change without a restart"},
+		},
+		{
+			name:     "TTL disabling the cache",
+			content:  "weather_cache:\n  ttl: 0s\n",
+			expected: []string{"WEATHER_CACHE_TTL cannot enable or disable the cache"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, "config.yaml", "weather_api_key: old-key\n")
+			reloader := newTestReloader(t, path)
+			previous := reloader.Current()
+			reloader.OnReload(func(*Config) { t.Error("hook called for a rejected reload") })
+
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			_, err := reloader.Reload()
+			require.Error(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, err.Error(), expected)
+			}
+
+			// A configuração em vigor não muda
+			assert.Same(t, previous, reloader.Current())
+			assert.Equal(t, "old-key", reloader.Current().WeatherAPIKey)
+		})
+	}
+}
+
+func TestReloader_Watch(t *testing.T) {
+	path := writeFile(t, "config.yaml", "weather_api_key: first\n")
+	reloader := newTestReloader(t, path)
+
+	keys := make(chan string, 4)
+	reloader.OnReload(func(cfg *Config) { keys <- cfg.WeatherAPIKey })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, 10*time.Millisecond, signals)
+		close(done)
+	}()
+
+	// Alteração do arquivo detectada pela verificação periódica
+	require.NoError(t, os.WriteFile(path, []byte("weather_api_key: second-key\n"), 0o600))
+	select {
+	case key := <-keys:
+		assert.Equal(t, "second-key", key)
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change not reloaded")
+	}
+
+	// SIGHUP recarrega mesmo sem alteração detectada pela verificação periódica
+	require.NoError(t, os.WriteFile(path, []byte("weather_api_key: third-key\n"), 0o600))
+	signals <- syscall.SIGHUP
+	select {
+	case key := <-keys:
+		assert.Equal(t, "third-key", key)
+	case <-time.After(2 * time.Second):
+		t.Fatal("SIGHUP not reloaded")
+	}
+
+	cancel()
+	<-done
+	assert.Equal(t, "third-key", reloader.Current().WeatherAPIKey)
+}
+
+func TestLoadWith_CORSOrigins(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(map[string]string{"CORS_ALLOWED_ORIGINS": "https://a.example.com,http://localhost:3000"})})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example.com", "http://localhost:3000"}, cfg.CORSOrigins)
+
+	_, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"CORS_ALLOWED_ORIGINS": "https://a.example.com/path"})})
+	assert.ErrorContains(t, err, "invalid CORS_ALLOWED_ORIGINS")
+}
+
+func TestReloader_Reload_RateLimit(t *testing.T) {
+	path := writeFile(t, "config.yaml", "rate_limit:\n  rps: 5\n")
+	reloader := newTestReloader(t, path)
+
+	// Os limites mudam sem reiniciar; o backend não
+	require.NoError(t, os.WriteFile(path, []byte("rate_limit:\n  rps: 10\n  burst: 20\n  daily_quota: 1000\n"), 0o600))
+	changes, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Key: "RATE_LIMIT_RPS", Old: "5", New: "10"},
+		{Key: "RATE_LIMIT_BURST", Old: "0", New: "20"},
+		{Key: "RATE_LIMIT_DAILY_QUOTA", Old: "0", New: "1000"},
+	}, changes)
+	assert.Equal(t, int64(1000), reloader.Current().RateLimit.DailyQuota)
+
+	require.NoError(t, os.WriteFile(path, []byte("redis_url: redis://localhost:6379/0\nrate_limit:\n  backend: redis\n"), 0o600))
+	_, err = reloader.Reload()
+	assert.ErrorContains(t, err, "RATE_LIMIT_BACKEND cannot change without a restart")
+}
+
+func TestReloader_Reload_Providers(t *testing.T) {
+	path := writeFile(t, "config.yaml", "cep_database: ceps.db\n")
+	reloader := newTestReloader(t, path)
+
+	// A escolha dos provedores muda sem reiniciar; os arquivos que eles usam não
+	require.NoError(t, os.WriteFile(path, []byte("cep_database: ceps.db\ncep_offline_fallback: false\nreverse_geocoder: none\n"), 0o600))
+	changes, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Key: "CEP_OFFLINE_FALLBACK", Old: "true", New: "false"},
+		{Key: "REVERSE_GEOCODER", Old: "nominatim", New: "none"},
+	}, changes)
+	assert.False(t, reloader.Current().CEPOfflineFallback)
+	assert.Equal(t, GeocoderNone, reloader.Current().ReverseGeocoder)
+
+	// O geocodificador local usa a tabela embutida do IBGE, sem exigir IBGE_DATASET
+	require.NoError(t, os.WriteFile(path, []byte("cep_database: ceps.db\nreverse_geocoder: local\n"), 0o600))
+	_, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, GeocoderLocal, reloader.Current().ReverseGeocoder)
+}
+
+func TestReloader_Reload_RestartSecrets(t *testing.T) {
+	path := writeFile(t, "config.yaml", "weather_api_key: old-key\nadmin_token: old-token\n")
+	reloader := newTestReloader(t, path)
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	// Segredos rotacionados no provedor: a chave da WeatherAPI é aplicada e os que exigem reinício
+	// mantêm o valor em vigor, sem rejeitar a recarga
+	content := "weather_api_key: new-key\nadmin_token: new-token\nredis_url: redis://cache:6379/0\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	changes, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Key: "WEATHER_API_KEY", Old: "REDACTED", New: "REDACTED"}}, changes)
+	assert.Equal(t, "new-key", reloader.Current().WeatherAPIKey)
+	assert.Equal(t, "old-token", reloader.Current().AdminToken)
+	assert.Empty(t, reloader.Current().RedisURL)
+	assert.Contains(t, logs.String(), "keys=\"[ADMIN_TOKEN REDIS_URL CEP_CACHE_REDIS_URL WEATHER_CACHE_REDIS_URL RATE_LIMIT_REDIS_URL]\"")
+	assert.NotContains(t, logs.String(), "new-token")
+
+	// O aviso não se repete enquanto os valores pendentes forem os mesmos
+	logs.Reset()
+	changes, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, logs.String())
+}
+
+func TestRestartSecrets(t *testing.T) {
+	// Todo segredo que exige reinício precisa ser mantido pela recarga
+	for _, s := range settings {
+		if s.secret && !s.reloadable {
+			assert.Contains(t, restartSecrets, s.key)
+		}
+	}
+}
+
+func TestLoadWith_TrustedProxies(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(nil)})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.TrustedProxies)
+	assert.Empty(t, cfg.TrustedPlatform)
+
+	cfg, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{
+		"TRUSTED_PROXIES":  "10.0.0.0/8, 192.168.1.10,fd00::/8",
+		"TRUSTED_PLATFORM": "cloudflare",
+	})})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10", "fd00::/8"}, cfg.TrustedProxies)
+	assert.Equal(t, "CF-Connecting-IP", cfg.TrustedPlatform)
+
+	cfg, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"TRUSTED_PLATFORM": "x-client-ip"})})
+	require.NoError(t, err)
+	assert.Equal(t, "X-Client-Ip", cfg.TrustedPlatform)
+
+	_, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,proxy.internal"})})
+	assert.ErrorContains(t, err, "invalid TRUSTED_PROXIES")
+}
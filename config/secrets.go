@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"weather-cep-api/secrets"
+)
+
+// secretsTimeout limita a leitura de todos os provedores de segredos
+const secretsTimeout = 10 * time.Second
+
+// secretsClient é o client HTTP do provedor do Vault
+var secretsClient secrets.HTTPClientInterface = &http.Client{Timeout: secretsTimeout}
+
+// resolveSecrets completa as configurações secretas que não vieram de flag nem do ambiente
+// Precedência entre os provedores: <NOME>_FILE > Vault > arquivo criptografado
+// O Vault e o arquivo criptografado são configurados pelos valores já resolvidos (inclusive os de
+// <NOME>_FILE, ex: VAULT_TOKEN_FILE); configurações inválidas são reportadas depois, por parse
+func resolveSecrets(raw map[string]string, sources map[string]Source, lookup func(string) (string, bool)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	if err := applySecrets(ctx, secrets.NewFileRefProvider(lookup), raw, sources, false); err != nil {
+		return err
+	}
+
+	cfg, err := secrets.ConfigFrom(func(key string) string { return raw[key] })
+	if err != nil {
+		return nil
+	}
+	for _, provider := range cfg.Providers(secretsClient) {
+		if err := applySecrets(ctx, provider, raw, sources, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySecrets busca no provedor as configurações secretas ainda sem valor de maior precedência
+func applySecrets(ctx context.Context, provider secrets.Provider, raw map[string]string, sources map[string]Source, skipCredentials bool) error {
+	var keys []string
+	for _, s := range settings {
+		if !s.secret || (skipCredentials && providerCredentials[s.key]) {
+			continue
+		}
+		switch sources[s.key] {
+		case SourceDefault, SourceDotEnv, SourceFile:
+			keys = append(keys, s.key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values, err := provider.Fetch(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("error loading secrets from %s: %w", provider.Name(), err)
+	}
+	for key, value := range values {
+		raw[key], sources[key] = value, Source(provider.Name())
+	}
+	return nil
+}
+
+// usesSecrets informa se alguma configuração veio dos provedores de segredos
+func (c *Config) usesSecrets() bool {
+	for _, source := range c.sources {
+		switch source {
+		case SourceSecretFile, SourceVault, SourceEncryptedFile:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weather-cep-api/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeVault cria um servidor compatível com o KV v2 do Vault que devolve o segredo atual
+func newFakeVault(t *testing.T, token string, secret *atomic.Value) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token || r.URL.Path != "/v1/secret/data/weather-cep-api" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":` + secret.Load().(string) + `,"metadata":{"version":1}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLoadWith_SecretFiles(t *testing.T) {
+	keyFile := writeFile(t, "weather_api_key", "from-secret-file\n")
+	dotEnv := writeFile(t, ".env", "WEATHER_API_KEY=from-dotenv\nADMIN_TOKEN_FILE="+writeFile(t, "admin_token", "admin-secret")+"\n")
+
+	// <NOME>_FILE tem precedência sobre o .env e o arquivo de configuração
+	cfg, err := LoadWith(Options{
+		LookupEnv:  mapEnv(map[string]string{"WEATHER_API_KEY_FILE": keyFile}),
+		DotEnvFile: dotEnv,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "from-secret-file", cfg.WeatherAPIKey)
+	assert.Equal(t, SourceSecretFile, cfg.Source("WEATHER_API_KEY"))
+	assert.Equal(t, "admin-secret", cfg.AdminToken)
+	assert.Equal(t, SourceSecretFile, cfg.Source("ADMIN_TOKEN"))
+
+	// A variável de ambiente com o próprio valor continua com precedência
+	cfg, err = LoadWith(Options{
+		LookupEnv: mapEnv(map[string]string{"WEATHER_API_KEY": "from-env", "WEATHER_API_KEY_FILE": keyFile}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.WeatherAPIKey)
+	assert.Equal(t, SourceEnv, cfg.Source("WEATHER_API_KEY"))
+
+	_, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"WEATHER_API_KEY_FILE": keyFile + ".missing"})})
+	assert.ErrorContains(t, err, "error loading secrets from secret-file")
+}
+
+func TestLoadWith_Vault(t *testing.T) {
+	var secret atomic.Value
+	secret.Store(`{"weather_api_key":"from-vault","redis_url":"redis://:pw@redis:6379"}`)
+	server := newFakeVault(t, "s.vault-token", &secret)
+	tokenFile := writeFile(t, "vault_token", "s.vault-token\n")
+
+	env := map[string]string{
+		"VAULT_ADDR":        server.URL,
+		"VAULT_TOKEN_FILE":  tokenFile,
+		"VAULT_SECRET_PATH": "secret/data/weather-cep-api",
+	}
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(env)})
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", cfg.WeatherAPIKey)
+	assert.Equal(t, SourceVault, cfg.Source("WEATHER_API_KEY"))
+	assert.Equal(t, "redis://:pw@redis:6379", cfg.RedisURL)
+	assert.Equal(t, SourceSecretFile, cfg.Source("VAULT_TOKEN"))
+	assert.True(t, cfg.usesSecrets())
+
+	// O Vault indisponível ou recusando o token impede a inicialização
+	env["VAULT_TOKEN_FILE"] = writeFile(t, "wrong_token", "s.wrong")
+	_, err = LoadWith(Options{LookupEnv: mapEnv(env)})
+	assert.ErrorContains(t, err, "error loading secrets from vault")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestLoadWith_EncryptedFile(t *testing.T) {
+	encodedKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	key, err := secrets.ParseKey(encodedKey)
+	require.NoError(t, err)
+	encrypted, err := secrets.Encrypt(key, []byte("WEATHER_API_KEY=from-encrypted\nADMIN_TOKEN=encrypted-admin\n"))
+	require.NoError(t, err)
+	path := writeFile(t, "secrets.enc", encrypted)
+
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(map[string]string{
+		"SECRETS_FILE": path,
+		"SECRETS_KEY":  encodedKey,
+		"ADMIN_TOKEN":  "from-env",
+	})})
+	require.NoError(t, err)
+	assert.Equal(t, "from-encrypted", cfg.WeatherAPIKey)
+	assert.Equal(t, SourceEncryptedFile, cfg.Source("WEATHER_API_KEY"))
+	assert.Equal(t, "from-env", cfg.AdminToken)
+
+	// A chave aparece redigida na listagem das configurações
+	for _, s := range cfg.Settings() {
+		if s.Key == "SECRETS_KEY" {
+			assert.NotContains(t, s.Value, encodedKey)
+		}
+	}
+
+	_, err = LoadWith(Options{LookupEnv: mapEnv(map[string]string{"SECRETS_FILE": path, "SECRETS_KEY": "short"})})
+	assert.ErrorContains(t, err, "invalid SECRETS_KEY")
+}
+
+func TestReloader_Watch_RefreshSecrets(t *testing.T) {
+	var secret atomic.Value
+	secret.Store(`{"weather_api_key":"first"}`)
+	server := newFakeVault(t, "s.vault-token", &secret)
+
+	opts := Options{LookupEnv: mapEnv(map[string]string{
+		"VAULT_ADDR":               server.URL,
+		"VAULT_TOKEN":              "s.vault-token",
+		"VAULT_SECRET_PATH":        "secret/data/weather-cep-api",
+		"SECRETS_REFRESH_INTERVAL": "20ms",
+	})}
+	cfg, err := LoadWith(opts)
+	require.NoError(t, err)
+	reloader := NewReloader(cfg, opts)
+
+	keys := make(chan string, 4)
+	reloader.OnReload(func(cfg *Config) { keys <- cfg.WeatherAPIKey })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, 0, nil)
+		close(done)
+	}()
+
+	// A chave rotacionada no Vault é aplicada na releitura periódica, sem sinal nem alteração de arquivo
+	secret.Store(`{"weather_api_key":"rotated"}`)
+	select {
+	case key := <-keys:
+		assert.Equal(t, "rotated", key)
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotated secret not reloaded")
+	}
+
+	cancel()
+	<-done
+	assert.Equal(t, SourceVault, reloader.Current().Source("WEATHER_API_KEY"))
+}
+
+func TestConfig_Secrets_NotUsed(t *testing.T) {
+	cfg, err := LoadWith(Options{LookupEnv: mapEnv(map[string]string{"WEATHER_API_KEY": "from-env"})})
+	require.NoError(t, err)
+	assert.False(t, cfg.usesSecrets())
+	assert.Empty(t, cfg.Secrets.Providers(http.DefaultClient))
+}
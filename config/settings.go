@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+	"weather-cep-api/secrets"
+)
+
+// setting descreve uma configuração aceita pela API
+// O nome é o da variável de ambiente; no arquivo ele vira chave aninhada em minúsculas
+// (ex: cep_cache.ttl ou cep_cache_ttl) e na linha de comando vira flag (ex: --cep-cache-ttl)
+// As configurações reloadable podem mudar sem reiniciar a API (ver Reloader)
+type setting struct {
+	key        string
+	usage      string
+	secret     bool
+	reloadable bool
+}
+
+// settings lista todas as configurações, na ordem exibida pela ajuda e pelo dump
+var settings = []setting{
+	{key: "PORT", usage: "porta HTTP do servidor"},
+	{key: "GIN_MODE", usage: "modo do Gin: debug, release ou test"},
+	{key: "WEATHER_API_KEY", usage: "chave da WeatherAPI", secret: true, reloadable: true},
+	{key: "WEATHER_API_KEYS", usage: "chaves adicionais da WeatherAPI, separadas por vírgula", secret: true, reloadable: true},
+	{key: "WEATHER_API_KEY_STRATEGY", usage: "escolha da chave da WeatherAPI: round-robin ou quota-aware", reloadable: true},
+	{key: "WEATHER_API_KEY_QUOTA", usage: "chamadas por mês de cada chave da WeatherAPI (0 sem limite)", reloadable: true},
+	{key: "WEATHER_API_KEY_QUARANTINE", usage: "tempo fora do pool de uma chave recusada pela WeatherAPI", reloadable: true},
+	{key: "ADMIN_TOKEN", usage: "token das rotas /admin (vazio desativa)", secret: true},
+	{key: "API_KEY_AUTH", usage: "exige chave de cliente (X-API-Key ou api_key) nas consultas"},
+	{key: "API_KEYS_FILE", usage: "arquivo com os hashes das chaves de clientes (vazio mantém em memória)"},
+	{key: "JWT_ISSUER", usage: "emissor (iss) exigido nos tokens JWT"},
+	{key: "JWT_AUDIENCE", usage: "audiência (aud) exigida nos tokens JWT"},
+	{key: "JWT_JWKS_URL", usage: "URL do JWKS com as chaves dos tokens RS256/ES256 (vazio desativa)"},
+	{key: "JWT_HMAC_SECRET", usage: "segredo dos tokens HS256 (vazio desativa)", secret: true},
+	{key: "JWT_JWKS_CACHE_TTL", usage: "tempo de vida das chaves do JWKS em cache"},
+	{key: "JWT_LEEWAY", usage: "tolerância de relógio na validação da expiração dos tokens"},
+	{key: "JWT_SCOPES_CLAIM", usage: "claim com os escopos do cliente (ex: scope, roles, realm_access.roles)"},
+	{key: "JWT_SCOPE_MAP", usage: "conversão dos valores da claim em escopos: valor=escopo, separados por vírgula"},
+	{key: "CORS_ALLOWED_ORIGINS", usage: "origens aceitas pelo CORS, separadas por vírgula (* aceita todas)", reloadable: true},
+	{key: "TRUSTED_PROXIES", usage: "IPs ou redes (CIDR) dos proxies cujo X-Forwarded-For é aceito, separados por vírgula (vazio usa o IP da conexão)"},
+	{key: "TRUSTED_PLATFORM", usage: "cabeçalho com o IP do cliente preenchido pela plataforma: cloudflare, appengine ou o nome do cabeçalho"},
+	{key: "CONFIG_WATCH_INTERVAL", usage: "intervalo de verificação de alterações no arquivo de configuração e no .env (0 desativa)"},
+
+	{key: "LOG_LEVEL", usage: "nível de log: debug, info, warn ou error"},
+	{key: "LOG_FORMAT", usage: "formato dos logs: json ou text"},
+
+	{key: "OTEL_TRACES_EXPORTER", usage: "exportador de traces: otlp, stdout ou none"},
+	{key: "OTEL_SERVICE_NAME", usage: "nome do serviço nos traces"},
+	{key: "OTEL_TRACES_SAMPLER_ARG", usage: "proporção de traces amostrados (0 a 1)"},
+
+	{key: "REDIS_URL", usage: "URL do Redis usada pelos caches com backend redis", secret: true},
+	{key: "CEP_CACHE_BACKEND", usage: "cache de CEPs: memory, bolt, redis ou none"},
+	{key: "CEP_CACHE_TTL", usage: "tempo de vida do cache de CEPs (0 desativa)", reloadable: true},
+	{key: "CEP_CACHE_PATH", usage: "arquivo do cache de CEPs (backend bolt)"},
+	{key: "CEP_CACHE_REDIS_URL", usage: "URL do Redis do cache de CEPs (padrão: REDIS_URL)", secret: true},
+	{key: "CEP_CACHE_MAX_ENTRIES", usage: "máximo de entradas do cache de CEPs (backend memory; 0 sem limite)"},
+	{key: "WEATHER_CACHE_BACKEND", usage: "cache de clima: memory, bolt, redis ou none"},
+	{key: "WEATHER_CACHE_TTL", usage: "tempo de vida do cache de clima (0 desativa)", reloadable: true},
+	{key: "WEATHER_CACHE_PATH", usage: "arquivo do cache de clima (backend bolt)"},
+	{key: "WEATHER_CACHE_REDIS_URL", usage: "URL do Redis do cache de clima (padrão: REDIS_URL)", secret: true},
+	{key: "WEATHER_CACHE_MAX_ENTRIES", usage: "máximo de entradas do cache de clima (backend memory; 0 sem limite)"},
+
+	{key: "RATE_LIMIT_RPS", usage: "requisições por segundo de cada cliente (0 desativa)", reloadable: true},
+	{key: "RATE_LIMIT_BURST", usage: "rajada de requisições aceita de cada cliente (0 usa RATE_LIMIT_RPS)", reloadable: true},
+	{key: "RATE_LIMIT_DAILY_QUOTA", usage: "requisições por dia (UTC) de cada cliente (0 sem limite)", reloadable: true},
+	{key: "RATE_LIMIT_MONTHLY_QUOTA", usage: "requisições por mês (UTC) de cada cliente (0 sem limite)", reloadable: true},
+	{key: "RATE_LIMIT_BACKEND", usage: "estado dos limites: memory ou redis (compartilhado entre réplicas)"},
+	{key: "RATE_LIMIT_REDIS_URL", usage: "URL do Redis dos limites (padrão: REDIS_URL)", secret: true},
+
+	{key: "CEP_DATABASE", usage: "banco local de CEPs gerado por cmd/cep-import"},
+	{key: "CEP_OFFLINE_FALLBACK", usage: "consulta a ViaCEP quando o CEP não está no banco local", reloadable: true},
+	{key: "IBGE_DATASET", usage: "tabela de municípios do IBGE que substitui a embutida (opcional)"},
+	{key: "REVERSE_GEOCODER", usage: "geocodificação reversa: nominatim, local ou none", reloadable: true},
+
+	{key: "WARMUP_FILE", usage: "lista de CEPs do pré-aquecimento"},
+	{key: "WARMUP_STATS_FILE", usage: "arquivo das estatísticas de acesso"},
+	{key: "WARMUP_TOP", usage: "quantidade de CEPs mais consultados pré-aquecidos"},
+	{key: "WARMUP_CONCURRENCY", usage: "consultas simultâneas do pré-aquecimento"},
+	{key: "WARMUP_RATE", usage: "consultas por segundo do pré-aquecimento (0 sem limite)"},
+	{key: "WARMUP_TIMEOUT", usage: "duração máxima do pré-aquecimento"},
+	{key: "WARMUP_REFRESH_INTERVAL", usage: "intervalo de atualização das cidades mais consultadas (0 desativa)"},
+	{key: "WARMUP_REFRESH_TOP", usage: "cidades atualizadas a cada intervalo"},
+
+	{key: "VAULT_ADDR", usage: "endereço do Vault com os segredos (vazio desativa)", reloadable: true},
+	{key: "VAULT_TOKEN", usage: "token de acesso ao Vault", secret: true, reloadable: true},
+	{key: "VAULT_SECRET_PATH", usage: "caminho do segredo no Vault (ex: secret/data/weather-cep-api)", reloadable: true},
+	{key: "VAULT_NAMESPACE", usage: "namespace do Vault Enterprise", reloadable: true},
+	{key: "SECRETS_FILE", usage: "arquivo de segredos criptografado por cmd/secrets-encrypt", reloadable: true},
+	{key: "SECRETS_KEY", usage: "chave AES-256 (base64) do arquivo de segredos", secret: true, reloadable: true},
+	{key: "SECRETS_REFRESH_INTERVAL", usage: "intervalo de releitura dos segredos (0 desativa)"},
+
+	{key: "CIRCUIT_BREAKER_THRESHOLD", usage: "falhas seguidas que abrem o circuit breaker (0 desativa)"},
+	{key: "CIRCUIT_BREAKER_COOLDOWN", usage: "tempo com o circuit breaker aberto antes da chamada de teste"},
+	{key: "READINESS_TIMEOUT", usage: "prazo das verificações de /readyz"},
+	{key: "READINESS_PROBE_INTERVAL", usage: "intervalo mínimo entre verificações de cada provedor"},
+	{key: "SHUTDOWN_DRAIN_PERIOD", usage: "tempo atendendo com a prontidão reprovada no encerramento"},
+	{key: "SHUTDOWN_TIMEOUT", usage: "prazo para as requisições em andamento no encerramento"},
+	{key: "SHUTDOWN_CLEANUP_TIMEOUT", usage: "prazo das rotinas de encerramento"},
+}
+
+// providerCredentials são as configurações usadas para acessar os provedores de segredos, que não
+// podem vir deles (só de <NOME>_FILE)
+var providerCredentials = map[string]bool{"VAULT_TOKEN": true, "SECRETS_KEY": true}
+
+// isSecretFileKey informa se o nome é a variável <NOME>_FILE de uma configuração secreta
+func isSecretFileKey(key string) bool {
+	base, ok := strings.CutSuffix(key, secrets.FileSuffix)
+	if !ok {
+		return false
+	}
+	s, found := lookupSetting(base)
+	return found && s.secret
+}
+
+// lookupSetting retorna a configuração com o nome informado
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
@@ -20,7 +20,7 @@ func setupE2ERouter() *gin.Engine {
 	
 	// Cria instâncias dos serviços reais
 	cepService := services.NewCEPService()
-	weatherService := services.NewWeatherService()
+	weatherService := services.NewWeatherService(os.Getenv("WEATHER_API_KEY"))
 	
 	// Cria instância do handler
 	weatherHandler := handlers.NewWeatherHandler(cepService, weatherService)
@@ -29,8 +29,7 @@ func setupE2ERouter() *gin.Engine {
 	router := gin.New()
 	
 	// Define as rotas
-	router.GET("/health", weatherHandler.HealthCheck)
-	router.GET("/temperature/:cep", weatherHandler.GetTemperatureByCEP)
+	handlers.RegisterRoutes(router, weatherHandler)
 	
 	return router
 }
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"weather-cep-api/cache"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+	"weather-cep-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Nomes dos caches aceitos nas rotas de administração
+const (
+	cacheNameCEP     = "cep"
+	cacheNameWeather = "weather"
+)
+
+// CacheAdminHandler gerencia a administração dos caches de CEP e clima
+type CacheAdminHandler struct {
+	cepCache     *cache.Store[models.LocationInfo]
+	weatherCache *cache.Store[models.WeatherConditions]
+}
+
+// NewCacheAdminHandler cria o handler de administração (um cache nil é tratado como desativado)
+func NewCacheAdminHandler(cepCache *cache.Store[models.LocationInfo], weatherCache *cache.Store[models.WeatherConditions]) *CacheAdminHandler {
+	return &CacheAdminHandler{
+		cepCache:     cepCache,
+		weatherCache: weatherCache,
+	}
+}
+
+// GetCacheStats retorna as estatísticas dos caches
+// GET /admin/cache
+func (h *CacheAdminHandler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, models.CacheStatsResponse{
+		CEP:     storeStats(h.cepCache),
+		Weather: storeStats(h.weatherCache),
+	})
+}
+
+// storeStats converte as estatísticas de um cache tipado para a resposta da administração
+func storeStats[T any](store *cache.Store[T]) models.CacheStats {
+	if store == nil {
+		return models.CacheStats{Enabled: false}
+	}
+	stats, err := store.Stats()
+	response := models.CacheStats{
+		Enabled:   true,
+		Namespace: stats.Namespace,
+		TTL:       stats.TTL,
+		Entries:   stats.Entries,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		HitRatio:  stats.HitRatio,
+		Writes:    stats.Writes,
+		Deletes:   stats.Deletes,
+		Errors:    stats.Errors,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	return response
+}
+
+// GetCEPEntry retorna a localização em cache de um CEP
+// GET /admin/cache/cep/:cep
+func (h *CacheAdminHandler) GetCEPEntry(c *gin.Context) {
+	cep := c.Param("cep")
+	if !utils.IsValidCEP(cep) {
+		c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+	if h.cepCache == nil {
+		c.String(http.StatusNotFound, "cache disabled")
+		return
+	}
+
+	key := services.CEPCacheKey(cep)
+	location, found, err := h.cepCache.Peek(key)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "error reading cache")
+		return
+	}
+	if !found {
+		c.String(http.StatusNotFound, "entry not found")
+		return
+	}
+	c.JSON(http.StatusOK, models.CacheEntryResponse{Cache: cacheNameCEP, Key: key, Value: location})
+}
+
+// DeleteCEPEntry remove do cache a localização de um CEP
+// DELETE /admin/cache/cep/:cep
+func (h *CacheAdminHandler) DeleteCEPEntry(c *gin.Context) {
+	cep := c.Param("cep")
+	if !utils.IsValidCEP(cep) {
+		c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+	if h.cepCache == nil {
+		c.String(http.StatusNotFound, "cache disabled")
+		return
+	}
+
+	if err := h.cepCache.Delete(services.CEPCacheKey(cep)); err != nil {
+		c.String(http.StatusInternalServerError, "error purging cache")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// weatherKeys retorna as chaves possíveis de uma cidade no cache de clima
+// As rotas por CEP usam o nome da ViaCEP (com acentos) e a rota por cidade usa o nome normalizado
+func weatherKeys(city, uf string) []string {
+	keys := []string{services.WeatherCacheKey(city, uf)}
+	if normalized := services.WeatherCacheKey(utils.NormalizeCityName(city), uf); normalized != keys[0] {
+		keys = append(keys, normalized)
+	}
+	return keys
+}
+
+// parseCityParams valida os parâmetros :uf e :city e escreve 422 quando são inválidos
+func parseCityParams(c *gin.Context) (string, string, bool) {
+	uf := utils.NormalizeUF(c.Param("uf"))
+	if !utils.IsValidUF(uf) {
+		c.String(http.StatusUnprocessableEntity, "invalid state")
+		return "", "", false
+	}
+	city := c.Param("city")
+	if !utils.IsValidCityName(city) {
+		c.String(http.StatusUnprocessableEntity, "invalid city")
+		return "", "", false
+	}
+	return city, uf, true
+}
+
+// GetWeatherEntry retorna as condições em cache de uma cidade
+// GET /admin/cache/weather/:uf/:city
+func (h *CacheAdminHandler) GetWeatherEntry(c *gin.Context) {
+	city, uf, ok := parseCityParams(c)
+	if !ok {
+		return
+	}
+	if h.weatherCache == nil {
+		c.String(http.StatusNotFound, "cache disabled")
+		return
+	}
+
+	for _, key := range weatherKeys(city, uf) {
+		conditions, found, err := h.weatherCache.Peek(key)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "error reading cache")
+			return
+		}
+		if found {
+			c.JSON(http.StatusOK, models.CacheEntryResponse{Cache: cacheNameWeather, Key: key, Value: conditions})
+			return
+		}
+	}
+	c.String(http.StatusNotFound, "entry not found")
+}
+
+// DeleteWeatherEntry remove do cache as condições de uma cidade
+// DELETE /admin/cache/weather/:uf/:city
+func (h *CacheAdminHandler) DeleteWeatherEntry(c *gin.Context) {
+	city, uf, ok := parseCityParams(c)
+	if !ok {
+		return
+	}
+	if h.weatherCache == nil {
+		c.String(http.StatusNotFound, "cache disabled")
+		return
+	}
+
+	for _, key := range weatherKeys(city, uf) {
+		if err := h.weatherCache.Delete(key); err != nil {
+			c.String(http.StatusInternalServerError, "error purging cache")
+			return
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PurgeCache remove todas as entradas de um cache (:name) ou de todos (sem :name)
+// DELETE /admin/cache e DELETE /admin/cache/:name
+func (h *CacheAdminHandler) PurgeCache(c *gin.Context) {
+	names := []string{cacheNameCEP, cacheNameWeather}
+	if name := c.Param("name"); name != "" {
+		if name != cacheNameCEP && name != cacheNameWeather {
+			c.String(http.StatusNotFound, "unknown cache")
+			return
+		}
+		names = []string{name}
+	}
+
+	response := models.CachePurgeResponse{Removed: make(map[string]int)}
+	for _, name := range names {
+		var removed int
+		var err error
+		switch {
+		case name == cacheNameCEP && h.cepCache != nil:
+			removed, err = h.cepCache.Purge()
+		case name == cacheNameWeather && h.weatherCache != nil:
+			removed, err = h.weatherCache.Purge()
+		default:
+			continue
+		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, "error purging cache")
+			return
+		}
+		response.Removed[name] = removed
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SetCacheTTL altera o TTL das próximas gravações de um cache
+// PUT /admin/cache/:name/ttl {"ttl": "10m"}
+func (h *CacheAdminHandler) SetCacheTTL(c *gin.Context) {
+	var request models.CacheTTLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.String(http.StatusBadRequest, "invalid ttl")
+		return
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(request.TTL))
+	if err != nil || ttl <= 0 {
+		c.String(http.StatusBadRequest, "invalid ttl")
+		return
+	}
+
+	switch c.Param("name") {
+	case cacheNameCEP:
+		if h.cepCache == nil {
+			c.String(http.StatusNotFound, "cache disabled")
+			return
+		}
+		_ = h.cepCache.SetTTL(ttl)
+		c.JSON(http.StatusOK, storeStats(h.cepCache))
+	case cacheNameWeather:
+		if h.weatherCache == nil {
+			c.String(http.StatusNotFound, "cache disabled")
+			return
+		}
+		_ = h.weatherCache.SetTTL(ttl)
+		c.JSON(http.StatusOK, storeStats(h.weatherCache))
+	default:
+		c.String(http.StatusNotFound, "unknown cache")
+	}
+}
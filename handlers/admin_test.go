@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-cep-api/auth"
+	"weather-cep-api/cache"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "s3cret-admin-token"
+
+// adminGuard exige o token de administração, como o main faz com ADMIN_TOKEN
+func adminGuard() gin.HandlerFunc {
+	return auth.Require(auth.NewStaticToken(testAdminToken, auth.ScopeAdmin), auth.ScopeAdmin)
+}
+
+// setupAdminRouter cria o router com as rotas de administração e caches em memória populados
+func setupAdminRouter(t *testing.T) (*gin.Engine, *cache.Store[models.LocationInfo], *cache.Store[models.WeatherConditions]) {
+	t.Helper()
+	backend := cache.NewMemory()
+	cepCache := cache.NewStore[models.LocationInfo](backend, "cep:v1:", 24*time.Hour)
+	weatherCache := cache.NewStore[models.WeatherConditions](backend, "weather:v1:", 5*time.Minute)
+
+	require.NoError(t, cepCache.Set("01310100", &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}))
+	require.NoError(t, cepCache.Set("20040020", &models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"}))
+	require.NoError(t, weatherCache.Set("são paulo|SP", &models.WeatherConditions{TempC: 22, Provider: "weatherapi"}))
+	require.NoError(t, weatherCache.Set("sao paulo|SP", &models.WeatherConditions{TempC: 22, Provider: "weatherapi"}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterAdminRoutes(router, NewCacheAdminHandler(cepCache, weatherCache), adminGuard())
+	return router, cepCache, weatherCache
+}
+
+// adminRequest executa uma requisição autenticada nas rotas de administração
+func adminRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCacheAdminHandler_Unauthorized(t *testing.T) {
+	router, _, _ := setupAdminRouter(t)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "Missing header", header: ""},
+		{name: "Wrong token", header: "Bearer wrong"},
+		{name: "Wrong scheme", header: "Basic " + testAdminToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("DELETE", "/admin/cache", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, "unauthorized", w.Body.String())
+			assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+		})
+	}
+}
+
+func TestCacheAdminHandler_GetCacheStats(t *testing.T) {
+	router, cepCache, _ := setupAdminRouter(t)
+	_, _, _ = cepCache.Get("01310100")
+	_, _, _ = cepCache.Get("70040010")
+
+	w := adminRequest(router, "GET", "/admin/cache", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.CacheStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.CEP.Enabled)
+	assert.Equal(t, "24h0m0s", response.CEP.TTL)
+	assert.Equal(t, 2, response.CEP.Entries)
+	assert.Equal(t, int64(1), response.CEP.Hits)
+	assert.Equal(t, int64(1), response.CEP.Misses)
+	assert.Equal(t, 0.5, response.CEP.HitRatio)
+	assert.Equal(t, 2, response.Weather.Entries)
+	assert.Equal(t, "5m0s", response.Weather.TTL)
+}
+
+func TestCacheAdminHandler_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterAdminRoutes(router, NewCacheAdminHandler(nil, nil), adminGuard())
+
+	w := adminRequest(router, "GET", "/admin/cache", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"cep":{"enabled":false,"entries":0,"hits":0,"misses":0,"hit_ratio":0,"writes":0,"deletes":0,"errors":0},
+		"weather":{"enabled":false,"entries":0,"hits":0,"misses":0,"hit_ratio":0,"writes":0,"deletes":0,"errors":0}}`, w.Body.String())
+
+	w = adminRequest(router, "GET", "/admin/cache/cep/01310100", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "cache disabled", w.Body.String())
+
+	w = adminRequest(router, "DELETE", "/admin/cache", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"removed":{}}`, w.Body.String())
+}
+
+func TestCacheAdminHandler_CEPEntry(t *testing.T) {
+	router, cepCache, _ := setupAdminRouter(t)
+
+	w := adminRequest(router, "GET", "/admin/cache/cep/01310-100", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"cache":"cep","key":"01310100","value":{"city":"São Paulo","state":"SP","cep":"01310-100"}}`, w.Body.String())
+
+	// A consulta administrativa não altera as estatísticas de hit/miss
+	stats, err := cepCache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Hits)
+
+	w = adminRequest(router, "GET", "/admin/cache/cep/70040010", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "entry not found", w.Body.String())
+
+	w = adminRequest(router, "GET", "/admin/cache/cep/123", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = adminRequest(router, "DELETE", "/admin/cache/cep/01310100", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	_, found, _ := cepCache.Peek("01310100")
+	assert.False(t, found)
+	_, found, _ = cepCache.Peek("20040020")
+	assert.True(t, found)
+}
+
+func TestCacheAdminHandler_WeatherEntry(t *testing.T) {
+	router, _, weatherCache := setupAdminRouter(t)
+
+	w := adminRequest(router, "GET", "/admin/cache/weather/sp/S%C3%A3o%20Paulo", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"key":"são paulo|SP"`)
+
+	w = adminRequest(router, "GET", "/admin/cache/weather/XX/Recife", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "invalid state", w.Body.String())
+
+	w = adminRequest(router, "GET", "/admin/cache/weather/PE/Recife", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// O purge remove tanto a chave com acentos (rotas por CEP) quanto a normalizada (rota por cidade)
+	w = adminRequest(router, "DELETE", "/admin/cache/weather/SP/S%C3%A3o%20Paulo", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	_, found, _ := weatherCache.Peek("são paulo|SP")
+	assert.False(t, found)
+	_, found, _ = weatherCache.Peek("sao paulo|SP")
+	assert.False(t, found)
+}
+
+func TestCacheAdminHandler_PurgeCache(t *testing.T) {
+	router, cepCache, weatherCache := setupAdminRouter(t)
+
+	w := adminRequest(router, "DELETE", "/admin/cache/cep", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"removed":{"cep":2}}`, w.Body.String())
+
+	stats, _ := weatherCache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+
+	w = adminRequest(router, "DELETE", "/admin/cache/unknown", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	require.NoError(t, cepCache.Set("01310100", &models.LocationInfo{City: "São Paulo", State: "SP"}))
+	w = adminRequest(router, "DELETE", "/admin/cache", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"removed":{"cep":1,"weather":2}}`, w.Body.String())
+}
+
+func TestCacheAdminHandler_SetCacheTTL(t *testing.T) {
+	router, cepCache, weatherCache := setupAdminRouter(t)
+
+	w := adminRequest(router, "PUT", "/admin/cache/weather/ttl", `{"ttl":"90s"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"ttl":"1m30s"`)
+	assert.Equal(t, 90*time.Second, weatherCache.TTL())
+	assert.Equal(t, 24*time.Hour, cepCache.TTL())
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		expected int
+	}{
+		{name: "Invalid duration", path: "/admin/cache/cep/ttl", body: `{"ttl":"soon"}`, expected: http.StatusBadRequest},
+		{name: "Zero TTL", path: "/admin/cache/cep/ttl", body: `{"ttl":"0s"}`, expected: http.StatusBadRequest},
+		{name: "Invalid JSON", path: "/admin/cache/cep/ttl", body: `ttl=1m`, expected: http.StatusBadRequest},
+		{name: "Unknown cache", path: "/admin/cache/geo/ttl", body: `{"ttl":"1m"}`, expected: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := adminRequest(router, "PUT", tt.path, tt.body)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+	assert.Equal(t, 24*time.Hour, cepCache.TTL())
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"weather-cep-api/auth"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler gerencia as chaves dos clientes da API
+type APIKeyHandler struct {
+	keys *auth.KeyStore
+}
+
+// NewAPIKeyHandler cria o handler de gestão das chaves
+func NewAPIKeyHandler(keys *auth.KeyStore) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// ListKeys retorna as chaves emitidas, sem os valores
+// GET /admin/keys
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys := h.keys.List()
+	response := make([]models.APIKey, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, apiKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateKey emite uma chave com nome e escopos; o valor só aparece nesta resposta
+// POST /admin/keys
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var request models.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.String(http.StatusBadRequest, "invalid request")
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		c.String(http.StatusBadRequest, "name is required")
+		return
+	}
+	scopes, err := auth.ParseScopes(request.Scopes)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plaintext, key, err := h.keys.Create(request.Name, scopes)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Erro ao emitir chave de cliente", "error", err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Chave de cliente emitida", "key_id", key.ID, "name", key.Name, "scopes", key.Scopes, "by", principalID(c))
+	c.JSON(http.StatusCreated, models.APIKeyCreatedResponse{APIKey: apiKeyResponse(key), Key: plaintext})
+}
+
+// RevokeKey revoga a chave; as requisições seguintes com ela recebem 401
+// DELETE /admin/keys/:id
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.keys.Revoke(id); err != nil {
+		if errors.Is(err, auth.ErrKeyNotFound) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "Erro ao revogar chave de cliente", "key_id", id, "error", err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Chave de cliente revogada", "key_id", id, "by", principalID(c))
+	c.Status(http.StatusNoContent)
+}
+
+// apiKeyResponse converte a chave para a resposta da administração
+func apiKeyResponse(key auth.Key) models.APIKey {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return models.APIKey{ID: key.ID, Name: key.Name, Scopes: scopes, CreatedAt: key.CreatedAt}
+}
+
+// principalID identifica nos logs quem fez a alteração
+func principalID(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		return principal.ID
+	}
+	return ""
+}
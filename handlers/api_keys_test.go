@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/auth"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAPIKeyRouter cria o router com a gestão das chaves, liberada pelo token de administração
+// ou por chave com o escopo admin, e uma rota de consulta que exige o escopo temperature
+func setupAPIKeyRouter(t *testing.T) (*gin.Engine, *auth.KeyStore) {
+	t.Helper()
+	store, err := auth.NewKeyStore("")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	guard := auth.Require(auth.Chain{auth.NewStaticToken(testAdminToken, auth.ScopeAdmin), store}, auth.ScopeAdmin)
+	RegisterAPIKeyRoutes(router, NewAPIKeyHandler(store), guard)
+	router.GET("/temperature/:cep", auth.Require(store, auth.ScopeTemperature), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router, store
+}
+
+// createKey emite uma chave pela rota de administração
+func createKey(t *testing.T, router *gin.Engine, body string) models.APIKeyCreatedResponse {
+	t.Helper()
+	w := adminRequest(router, "POST", "/admin/keys", body)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response models.APIKeyCreatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestAPIKeyHandler_Lifecycle(t *testing.T) {
+	router, _ := setupAPIKeyRouter(t)
+
+	created := createKey(t, router, `{"name":"mobile app","scopes":["temperature"]}`)
+	assert.Equal(t, "mobile app", created.Name)
+	assert.Equal(t, []string{"temperature"}, created.Scopes)
+	assert.NotEmpty(t, created.Key)
+
+	consult := func(key string) int {
+		req := httptest.NewRequest("GET", "/temperature/01310100", nil)
+		req.Header.Set(auth.HeaderAPIKey, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, consult(created.Key))
+
+	// A listagem não expõe o valor nem o hash da chave
+	w := adminRequest(router, "GET", "/admin/keys", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Key)
+	assert.NotContains(t, w.Body.String(), "hash")
+	var keys []models.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+	require.Len(t, keys, 1)
+	assert.Equal(t, created.APIKey, keys[0])
+
+	w = adminRequest(router, "DELETE", "/admin/keys/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, consult(created.Key))
+
+	w = adminRequest(router, "DELETE", "/admin/keys/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "api key not found", w.Body.String())
+}
+
+func TestAPIKeyHandler_CreateKey_Invalid(t *testing.T) {
+	router, store := setupAPIKeyRouter(t)
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedBody string
+	}{
+		{name: "Malformed body", body: `{"name":`, expectedBody: "invalid request"},
+		{name: "Missing name", body: `{"scopes":["temperature"]}`, expectedBody: "name is required"},
+		{name: "Missing scopes", body: `{"name":"mobile"}`, expectedBody: "at least one scope is required"},
+		{name: "Unknown scope", body: `{"name":"mobile","scopes":["root"]}`, expectedBody: `unknown scope "root" (use temperature, forecast or admin)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := adminRequest(router, "POST", "/admin/keys", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+	assert.Zero(t, store.Len())
+}
+
+func TestAPIKeyHandler_AdminScope(t *testing.T) {
+	router, _ := setupAPIKeyRouter(t)
+	admin := createKey(t, router, `{"name":"ops","scopes":["admin"]}`)
+	client := createKey(t, router, `{"name":"mobile","scopes":["temperature","forecast"]}`)
+
+	request := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/admin/keys", nil)
+		req.Header.Set(auth.HeaderAPIKey, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Uma chave com o escopo admin gerencia as chaves sem o ADMIN_TOKEN
+	assert.Equal(t, http.StatusOK, request(admin.Key).Code)
+	// A chave sem o escopo é autenticada, mas não autorizada
+	w := request(client.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "forbidden", w.Body.String())
+	// A chave admin não libera as consultas, que exigem o escopo temperature
+	req := httptest.NewRequest("GET", "/temperature/01310100", nil)
+	req.Header.Set(auth.HeaderAPIKey, admin.Key)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRegisterRoutes_Middleware_ExemptsHealth(t *testing.T) {
+	store, err := auth.NewKeyStore("")
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router, NewWeatherHandler(new(MockCEPService), new(MockWeatherService)), auth.Require(store, auth.ScopeTemperature))
+
+	for path, expected := range map[string]int{
+		"/health":                   http.StatusOK,
+		"/temperature/01310100":     http.StatusUnauthorized,
+		"/v1/temperature/01310100":  http.StatusUnauthorized,
+		"/v2/temperature/01310100":  http.StatusUnauthorized,
+		"/comfort/01310100":         http.StatusUnauthorized,
+		"/temperature/city/SP/Sé":   http.StatusUnauthorized,
+		"/temperature/ibge/3550308": http.StatusUnauthorized,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, expected, w.Code, path)
+	}
+}
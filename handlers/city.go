@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"weather-cep-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemperatureByCity busca temperatura pelo nome da cidade e sigla do estado, sem CEP
+// GET /temperature/city/:uf/:city[?scales=C,K,Ra&mode=exact&max_age=60]
+func (h *WeatherHandler) GetTemperatureByCity(c *gin.Context) {
+	// Valida a UF contra as 27 unidades federativas
+	uf := utils.NormalizeUF(c.Param("uf"))
+	if !utils.IsValidUF(uf) {
+		c.String(http.StatusUnprocessableEntity, "invalid state")
+		return
+	}
+
+	// Valida e normaliza o nome da cidade (acentos, espaços e capitalização)
+	city := c.Param("city")
+	if !utils.IsValidCityName(city) {
+		c.String(http.StatusUnprocessableEntity, "invalid city")
+		return
+	}
+	city = utils.NormalizeCityName(city)
+
+	// Parâmetros opcionais de conversão e frescor
+	opts, ok := parseTemperatureOptions(c)
+	if !ok {
+		return
+	}
+
+	// Busca temperatura diretamente pela cidade/estado
+	temperature, err := h.fetchTemperature(c.Request.Context(), city, uf, opts.maxAge)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "city", city, "state", uf)
+		writeWeatherError(c, err)
+		return
+	}
+
+	writeTemperature(c, temperature, opts)
+}
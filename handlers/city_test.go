@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWeatherHandler_GetTemperatureByCity_Success(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "Accents and lowercase UF", path: "/temperature/city/sp/S%C3%A3o%20Paulo"},
+		{name: "Uppercase without accents", path: "/temperature/city/SP/SAO%20PAULO"},
+		{name: "Versioned route", path: "/v1/temperature/city/SP/sao%20paulo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup mocks (o CEP service não deve ser usado)
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+
+			tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+			mockWeatherService.On("GetTemperatureByCity", mock.Anything, "Sao Paulo", "SP").Return(tempResponse, nil)
+
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"temp_C":25,"temp_F":77,"temp_K":298}`, w.Body.String())
+			mockCEPService.AssertExpectations(t)
+			mockWeatherService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCity_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Unknown UF", path: "/temperature/city/XX/Recife", expected: "invalid state"},
+		{name: "Invalid city characters", path: "/temperature/city/PE/Recife123", expected: "invalid city"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Equal(t, tt.expected, w.Body.String())
+			mockWeatherService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCity_WeatherServiceError(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "Recife", "PE").Return(nil, errors.New("weather API error"))
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/city/PE/recife", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "error fetching weather data", w.Body.String())
+}
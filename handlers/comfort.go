@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"weather-cep-api/models"
+	"weather-cep-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetComfortByCEP retorna índices de conforto térmico e a categoria de risco para um CEP
+// GET /comfort/:cep
+func (h *WeatherHandler) GetComfortByCEP(c *gin.Context) {
+	// Extrai CEP dos parâmetros da URL
+	cep := c.Param("cep")
+	if cep == "" {
+		c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+
+	// 1. Busca informações de localização pelo CEP
+	location, ok := h.resolveLocation(c, cep)
+	if !ok {
+		return
+	}
+
+	// 2. Busca condições atuais (temperatura, umidade e vento)
+	conditions, err := h.weatherService.GetConditionsByCity(c.Request.Context(), location.City, location.State)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "city", location.City, "state", location.State)
+		writeWeatherError(c, err)
+		return
+	}
+
+	// 3. Calcula os índices derivados
+	c.JSON(http.StatusOK, buildComfortResponse(conditions))
+}
+
+// buildComfortResponse calcula os índices de conforto térmico a partir das condições atuais
+func buildComfortResponse(conditions *models.WeatherConditions) *models.ComfortResponse {
+	heatIndex := utils.HeatIndex(conditions.TempC, conditions.Humidity)
+	windChill := utils.WindChill(conditions.TempC, conditions.WindKph)
+	dewPoint := utils.DewPoint(conditions.TempC, conditions.Humidity)
+
+	return &models.ComfortResponse{
+		TempC:      conditions.TempC,
+		Humidity:   conditions.Humidity,
+		WindKph:    conditions.WindKph,
+		HeatIndexC: heatIndex,
+		WindChillC: windChill,
+		DewPointC:  dewPoint,
+		Humidex:    utils.Humidex(conditions.TempC, dewPoint),
+		WBGTC:      utils.WBGT(conditions.TempC, conditions.Humidity),
+		Risk:       string(utils.ThermalRisk(heatIndex, windChill)),
+	}
+}
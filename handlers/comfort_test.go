@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWeatherHandler_GetComfortByCEP_Success(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "Cuiabá", State: "MT", CEP: "78005-000"}
+	conditions := &models.WeatherConditions{TempC: 38.0, Humidity: 40, WindKph: 10}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "78005000").Return(locationInfo, nil)
+	mockWeatherService.On("GetConditionsByCity", mock.Anything, "Cuiabá", "MT").Return(conditions, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	// Create request
+	req, _ := http.NewRequest("GET", "/comfort/78005000", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ComfortResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 38.0, response.TempC)
+	assert.Equal(t, 40.0, response.Humidity)
+	assert.Greater(t, response.HeatIndexC, response.TempC)
+	assert.Equal(t, 38.0, response.WindChillC)
+	assert.Equal(t, "danger", response.Risk)
+
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetComfortByCEP_CEPNotFound(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "99999999").Return(nil, errors.New("can not find zipcode"))
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/comfort/99999999", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "can not find zipcode", w.Body.String())
+	mockCEPService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetComfortByCEP_WeatherServiceError(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetConditionsByCity", mock.Anything, "São Paulo", "SP").Return(nil, errors.New("weather API error"))
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/comfort/01310100", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "error fetching weather data", w.Body.String())
+}
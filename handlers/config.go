@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler exibe a configuração efetiva da API na administração
+type ConfigHandler struct {
+	config func() models.ConfigResponse
+}
+
+// NewConfigHandler cria o handler a partir da função que devolve a configuração efetiva (sem segredos)
+func NewConfigHandler(config func() models.ConfigResponse) *ConfigHandler {
+	return &ConfigHandler{config: config}
+}
+
+// GetConfig retorna cada configuração com o valor efetivo e a origem (default, file, .env, env ou flag)
+// GET /admin/config
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.config())
+}
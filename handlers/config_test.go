@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler_GetConfig(t *testing.T) {
+	expected := models.ConfigResponse{
+		File: "config.yaml",
+		Settings: []models.ConfigSetting{
+			{Key: "PORT", Value: "8080", Source: "default"},
+			{Key: "WEATHER_API_KEY", Value: "REDACTED", Source: "env"},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterConfigRoutes(router, NewConfigHandler(func() models.ConfigResponse { return expected }), adminGuard())
+
+	// Sem o token a configuração não é exibida
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/config", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, "GET", "/admin/config", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, expected, response)
+}
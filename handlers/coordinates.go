@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"weather-cep-api/models"
+	"weather-cep-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemperatureByCoordinates busca temperatura por latitude/longitude
+// Quando há geocodificador configurado, resolve também a cidade/CEP mais próximos (?resolve=false desativa)
+// GET /temperature/coords?lat=-23.5613&lon=-46.6565[&mode=exact&resolve=false]
+func (h *WeatherHandler) GetTemperatureByCoordinates(c *gin.Context) {
+	// Valida as coordenadas
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(c.Query("lat")), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(c.Query("lon")), 64)
+	if latErr != nil || lonErr != nil || !utils.IsValidCoordinates(lat, lon) {
+		c.String(http.StatusUnprocessableEntity, "invalid coordinates")
+		return
+	}
+
+	mode, err := utils.ParseConversionMode(c.Query("mode"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid mode")
+		return
+	}
+	resolve := true
+	if raw := c.Query("resolve"); raw != "" {
+		resolve, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid resolve")
+			return
+		}
+	}
+
+	// 1. Busca as condições atuais pelas coordenadas
+	conditions, err := h.weatherService.GetConditionsByCoordinates(c.Request.Context(), lat, lon)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "lat", lat, "lon", lon)
+		writeWeatherError(c, err)
+		return
+	}
+
+	tempC, tempF, tempK := utils.ConvertTemperaturesWithMode(conditions.TempC, mode)
+	response := &models.CoordinatesTemperatureResponse{
+		TemperatureResponse: models.TemperatureResponse{TempC: tempC, TempF: tempF, TempK: tempK},
+	}
+
+	// 2. Geocodificação reversa é opcional: falhas não impedem a resposta de temperatura
+	if resolve && h.geocoder != nil {
+		if location, err := h.geocoder.ReverseGeocode(c.Request.Context(), lat, lon); err == nil {
+			response.Location = location
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGeocoder cria um geocodificador local com algumas capitais
+func newTestGeocoder() services.ReverseGeocoderInterface {
+	return services.NewLocalReverseGeocoder([]services.KnownPlace{
+		{Location: models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}, Lat: -23.5613, Lon: -46.6565},
+		{Location: models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"}, Lat: -22.9035, Lon: -43.2096},
+	}, 50)
+}
+
+func setupCoordinatesRouter(weatherService *MockWeatherService) *gin.Engine {
+	handler := NewWeatherHandlerWithGeocoder(new(MockCEPService), weatherService, newTestGeocoder())
+	return setupRouter(handler)
+}
+
+func TestWeatherHandler_GetTemperatureByCoordinates_Success(t *testing.T) {
+	mockWeatherService := new(MockWeatherService)
+	mockWeatherService.On("GetConditionsByCoordinates", mock.Anything, -23.55, -46.63).Return(&models.WeatherConditions{TempC: 25.0}, nil)
+
+	router := setupCoordinatesRouter(mockWeatherService)
+
+	req, _ := http.NewRequest("GET", "/temperature/coords?lat=-23.55&lon=-46.63", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.CoordinatesTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, response.TempC)
+	assert.Equal(t, 77.0, response.TempF)
+	assert.Equal(t, 298.0, response.TempK)
+	require.NotNil(t, response.Location)
+	assert.Equal(t, "São Paulo", response.Location.City)
+	assert.Equal(t, "01310-100", response.Location.CEP)
+
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetTemperatureByCoordinates_Unresolved(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "Resolve disabled", query: "lat=-23.55&lon=-46.63&resolve=false"},
+		{name: "No known place nearby", query: "lat=-3.1&lon=-60.02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWeatherService := new(MockWeatherService)
+			mockWeatherService.On("GetConditionsByCoordinates", mock.Anything, mock.Anything, mock.Anything).Return(&models.WeatherConditions{TempC: 30.0}, nil)
+
+			router := setupCoordinatesRouter(mockWeatherService)
+
+			req, _ := http.NewRequest("GET", "/temperature/coords?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			// A temperatura é retornada mesmo sem localidade resolvida
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"temp_C":30,"temp_F":86,"temp_K":303}`, w.Body.String())
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCoordinates_InvalidCoordinates(t *testing.T) {
+	for _, query := range []string{"", "lat=abc&lon=-46.6", "lat=-91&lon=-46.6", "lat=-23.5&lon=181", "lat=NaN&lon=0"} {
+		t.Run(query, func(t *testing.T) {
+			mockWeatherService := new(MockWeatherService)
+			router := setupCoordinatesRouter(mockWeatherService)
+
+			req, _ := http.NewRequest("GET", "/temperature/coords?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Equal(t, "invalid coordinates", w.Body.String())
+			mockWeatherService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCoordinates_WeatherServiceError(t *testing.T) {
+	mockWeatherService := new(MockWeatherService)
+	mockWeatherService.On("GetConditionsByCoordinates", mock.Anything, -23.55, -46.63).Return(nil, errors.New("weather API error"))
+
+	router := setupCoordinatesRouter(mockWeatherService)
+
+	req, _ := http.NewRequest("GET", "/temperature/coords?lat=-23.55&lon=-46.63", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "error fetching weather data", w.Body.String())
+}
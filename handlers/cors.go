@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS libera as requisições das origens devolvidas por origins ("*" libera qualquer origem)
+// A lista é consultada a cada requisição, portanto pode mudar com a recarga da configuração
+func CORS(origins func() []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if origin, ok := allowedOrigin(origins(), c.GetHeader("Origin")); ok {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
+			c.Header("Access-Control-Expose-Headers", "X-Request-ID, RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After")
+		}
+		// A resposta depende da origem quando a lista não é "*"
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allowedOrigin retorna o valor de Access-Control-Allow-Origin para a origem da requisição
+func allowedOrigin(origins []string, origin string) (string, bool) {
+	for _, allowed := range origins {
+		if allowed == "*" {
+			return "*", true
+		}
+		if origin != "" && allowed == origin {
+			return origin, true
+		}
+	}
+	return "", false
+}
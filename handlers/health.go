@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"weather-cep-api/health"
+	"weather-cep-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler gerencia as sondas de vida e prontidão da aplicação
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler cria o handler das sondas com as verificações de prontidão informadas
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Livez informa que o processo está de pé (não consulta nenhuma dependência)
+// GET /livez
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, models.LivenessResponse{Status: health.StatusOK})
+}
+
+// Readyz executa as verificações de prontidão e devolve o detalhamento de cada uma
+// GET /readyz - 200 quando todas passam, 503 caso contrário
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	response := h.checker.Run(c.Request.Context())
+	status := http.StatusOK
+	if response.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
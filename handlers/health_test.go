@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"weather-cep-api/health"
+	"weather-cep-api/models"
+	"weather-cep-api/warmup"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthRouter(checker *health.Checker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterHealthRoutes(router, NewHealthHandler(checker))
+	return router
+}
+
+func TestHealthHandler_Livez(t *testing.T) {
+	// A sonda de vida não depende das verificações de prontidão
+	checker := health.NewChecker(time.Second)
+	checker.Add("config", func(context.Context) error { return errors.New("WEATHER_API_KEY is not set") })
+	router := setupHealthRouter(checker)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		configErr      error
+		expectedStatus int
+		expectedState  string
+	}{
+		{name: "Ready", expectedStatus: http.StatusOK, expectedState: "ok"},
+		{name: "Missing configuration", configErr: errors.New("WEATHER_API_KEY is not set"), expectedStatus: http.StatusServiceUnavailable, expectedState: "fail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker(time.Second)
+			checker.Add("config", func(context.Context) error { return tt.configErr })
+			checker.Add("cache:cep", func(context.Context) error { return nil })
+			router := setupHealthRouter(checker)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ReadinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedState, response.Status)
+			assert.Equal(t, tt.expectedState, response.Checks["config"].Status)
+			assert.Equal(t, "ok", response.Checks["cache:cep"].Status)
+			if tt.configErr != nil {
+				assert.Equal(t, tt.configErr.Error(), response.Checks["config"].Error)
+			}
+		})
+	}
+}
+
+// blockingCEPService segura as consultas até release ser fechado (pré-aquecimento em andamento)
+type blockingCEPService struct {
+	release chan struct{}
+}
+
+func (s *blockingCEPService) GetLocationByCEP(ctx context.Context, _ string) (*models.LocationInfo, error) {
+	select {
+	case <-s.release:
+		return nil, errors.New("can not find zipcode")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestHealthHandler_DuringWarmup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ceps.txt")
+	require.NoError(t, os.WriteFile(path, []byte("01310100\n"), 0o600))
+	cepService := &blockingCEPService{release: make(chan struct{})}
+	cfg := warmup.DefaultConfig()
+	cfg.CEPFile, cfg.Rate, cfg.RefreshInterval = path, 0, 0
+	warmer := warmup.NewWarmer(cepService, new(MockWeatherService), nil, cfg)
+
+	checker := health.NewChecker(time.Second)
+	checker.Add("warmup", health.Ready(warmer.Ready, "cache warmup in progress"))
+	router := setupHealthRouter(checker)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	warmer.Start(ctx)
+
+	// Enquanto o pré-aquecimento roda, o processo está vivo mas ainda não pronto
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "cache warmup in progress")
+
+	close(cepService.release)
+	assert.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+		return w.Code == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+}
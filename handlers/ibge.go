@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"weather-cep-api/ibge"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemperatureByIBGE busca temperatura pelo código de município do IBGE
+// GET /temperature/ibge/:code[?scales=C,K,Ra&mode=exact&max_age=60]
+func (h *WeatherHandler) GetTemperatureByIBGE(c *gin.Context) {
+	// Valida o código (7 dígitos)
+	code := strings.TrimSpace(c.Param("code"))
+	if !ibge.IsValidCode(code) {
+		c.String(http.StatusUnprocessableEntity, "invalid ibge code")
+		return
+	}
+
+	// Parâmetros opcionais de conversão e frescor
+	opts, ok := parseTemperatureOptions(c)
+	if !ok {
+		return
+	}
+
+	// 1. Busca o município na tabela do IBGE
+	municipality, found := ibge.Lookup(code)
+	if !found {
+		c.String(http.StatusNotFound, "can not find municipality")
+		return
+	}
+
+	// 2. Busca temperatura pela cidade/estado, como na rota de CEP
+	temperature, err := h.fetchTemperature(c.Request.Context(), municipality.Name, municipality.UF, opts.maxAge)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "city", municipality.Name, "state", municipality.UF)
+		writeWeatherError(c, err)
+		return
+	}
+
+	writeTemperature(c, temperature, opts)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWeatherHandler_GetTemperatureByIBGE_Success(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/ibge/3550308", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// Mesmo contrato da rota de CEP
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"temp_C":25,"temp_F":77,"temp_K":298}`, w.Body.String())
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetTemperatureByIBGE_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Invalid code format", code: "35503", expectedCode: http.StatusUnprocessableEntity, expectedBody: "invalid ibge code"},
+		{name: "Unknown municipality", code: "9999999", expectedCode: http.StatusNotFound, expectedBody: "can not find municipality"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", "/temperature/ibge/"+tt.code, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByIBGE_WeatherServiceError(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "Recife", "PE").Return(nil, errors.New("weather API error"))
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/ibge/2611606", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "error fetching weather data", w.Body.String())
+}
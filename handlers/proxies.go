@@ -0,0 +1,16 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// TrustProxies define de onde vem o IP do cliente (c.ClientIP), usado no log de acesso e nos limites por IP
+// Por padrão o Gin aceita X-Forwarded-For de qualquer origem, o que permite a um cliente trocar de IP a
+// cada requisição; aqui o cabeçalho só vale quando a conexão vem de um dos proxies informados (IPs ou
+// redes CIDR). Sem proxies vale o IP da conexão. platform, quando informado, é o cabeçalho preenchido
+// pela plataforma (ex: gin.PlatformCloudflare), que tem precedência
+func TrustProxies(router *gin.Engine, proxies []string, platform string) error {
+	router.TrustedPlatform = platform
+	if len(proxies) == 0 {
+		return router.SetTrustedProxies(nil)
+	}
+	return router.SetTrustedProxies(proxies)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registra todas as rotas da API no router
+// As rotas sem versão são aliases da v1 para manter os clientes existentes funcionando
+// Os middlewares informados (ex: autenticação) valem para as consultas, mas não para /health
+func RegisterRoutes(router gin.IRouter, h *WeatherHandler, middleware ...gin.HandlerFunc) {
+	router.GET("/health", h.HealthCheck)
+
+	api := router.Group("", middleware...)
+	registerV1Routes(api, h)
+	registerV1Routes(api.Group("/v1"), h)
+	registerV2Routes(api.Group("/v2"), h)
+}
+
+// registerV1Routes registra as rotas com o contrato original da API
+func registerV1Routes(router gin.IRouter, h *WeatherHandler) {
+	router.GET("/temperature/:cep", h.GetTemperatureByCEP)
+	router.GET("/temperature/city/:uf/:city", h.GetTemperatureByCity)
+	router.GET("/temperature/coords", h.GetTemperatureByCoordinates)
+	router.GET("/temperature/ibge/:code", h.GetTemperatureByIBGE)
+	router.GET("/comfort/:cep", h.GetComfortByCEP)
+}
+
+// registerV2Routes registra as rotas da v2 (respostas com metadados)
+func registerV2Routes(router gin.IRouter, h *WeatherHandler) {
+	router.GET("/temperature/:cep", h.GetTemperatureByCEPV2)
+	router.GET("/comfort/:cep", h.GetComfortByCEP)
+}
+
+// RegisterHealthRoutes registra as sondas de vida (/livez) e prontidão (/readyz)
+func RegisterHealthRoutes(router gin.IRouter, h *HealthHandler) {
+	router.GET("/livez", h.Livez)
+	router.GET("/readyz", h.Readyz)
+}
+
+// RegisterAdminRoutes registra as rotas de administração do cache em /admin, protegidas por guard
+func RegisterAdminRoutes(router gin.IRouter, h *CacheAdminHandler, guard gin.HandlerFunc) {
+	admin := router.Group("/admin", guard)
+	admin.GET("/cache", h.GetCacheStats)
+	admin.DELETE("/cache", h.PurgeCache)
+	admin.GET("/cache/cep/:cep", h.GetCEPEntry)
+	admin.DELETE("/cache/cep/:cep", h.DeleteCEPEntry)
+	admin.GET("/cache/weather/:uf/:city", h.GetWeatherEntry)
+	admin.DELETE("/cache/weather/:uf/:city", h.DeleteWeatherEntry)
+	admin.DELETE("/cache/:name", h.PurgeCache)
+	admin.PUT("/cache/:name/ttl", h.SetCacheTTL)
+}
+
+// RegisterConfigRoutes registra a consulta da configuração efetiva em /admin, protegida por guard
+func RegisterConfigRoutes(router gin.IRouter, h *ConfigHandler, guard gin.HandlerFunc) {
+	router.Group("/admin", guard).GET("/config", h.GetConfig)
+}
+
+// RegisterAPIKeyRoutes registra a gestão das chaves de clientes em /admin/keys, protegida por guard
+func RegisterAPIKeyRoutes(router gin.IRouter, h *APIKeyHandler, guard gin.HandlerFunc) {
+	keys := router.Group("/admin/keys", guard)
+	keys.GET("", h.ListKeys)
+	keys.POST("", h.CreateKey)
+	keys.DELETE("/:id", h.RevokeKey)
+}
@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+	"weather-cep-api/models"
 	"weather-cep-api/services"
+	"weather-cep-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +20,7 @@ import (
 type WeatherHandler struct {
 	cepService     services.CEPServiceInterface
 	weatherService services.WeatherServiceInterface
+	geocoder       services.ReverseGeocoderInterface
 }
 
 // NewWeatherHandler cria uma nova instância do handler de clima
@@ -22,8 +31,15 @@ func NewWeatherHandler(cepService services.CEPServiceInterface, weatherService s
 	}
 }
 
+// NewWeatherHandlerWithGeocoder cria uma nova instância com geocodificação reversa para a rota de coordenadas
+func NewWeatherHandlerWithGeocoder(cepService services.CEPServiceInterface, weatherService services.WeatherServiceInterface, geocoder services.ReverseGeocoderInterface) *WeatherHandler {
+	handler := NewWeatherHandler(cepService, weatherService)
+	handler.geocoder = geocoder
+	return handler
+}
+
 // GetTemperatureByCEP busca temperatura por CEP
-// GET /temperature/:cep
+// GET /temperature/:cep[?scales=C,K,Ra&mode=exact&max_age=60]
 func (h *WeatherHandler) GetTemperatureByCEP(c *gin.Context) {
 	// Extrai CEP dos parâmetros da URL
 	cep := c.Param("cep")
@@ -32,32 +48,191 @@ func (h *WeatherHandler) GetTemperatureByCEP(c *gin.Context) {
 		return
 	}
 
+	// Parâmetros opcionais de conversão e frescor (sem eles o contrato original é mantido)
+	opts, ok := parseTemperatureOptions(c)
+	if !ok {
+		return
+	}
+
 	// 1. Busca informações de localização pelo CEP
-	location, err := h.cepService.GetLocationByCEP(cep)
+	location, ok := h.resolveLocation(c, cep)
+	if !ok {
+		return
+	}
+
+	// 2. Busca temperatura pela cidade/estado
+	temperature, err := h.fetchTemperature(c.Request.Context(), location.City, location.State, opts.maxAge)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "city", location.City, "state", location.State)
+		writeWeatherError(c, err)
+		return
+	}
+
+	// 3. Retorna resposta com temperaturas
+	writeTemperature(c, temperature, opts)
+}
+
+// weatherRetryAfter é o Retry-After padrão quando o pool não informa quando haverá chave disponível
+const weatherRetryAfter = time.Minute
+
+// writeWeatherError responde a falha na consulta do clima: 503 com Retry-After quando nenhuma chave
+// da WeatherAPI está disponível (todas em quarentena ou sem cota) e 500 nos demais casos
+func writeWeatherError(c *gin.Context, err error) {
+	if !errors.Is(err, services.ErrNoAPIKeyAvailable) {
+		c.String(http.StatusInternalServerError, "error fetching weather data")
+		return
+	}
+
+	retryAfter := weatherRetryAfter
+	var noKey *services.NoAPIKeyError
+	if errors.As(err, &noKey) && noKey.RetryAfter > 0 {
+		retryAfter = noKey.RetryAfter
+	}
+	c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+	c.String(http.StatusServiceUnavailable, "weather data temporarily unavailable")
+}
+
+// logError registra a causa de uma falha interna; o cliente recebe apenas a mensagem genérica
+func logError(c *gin.Context, msg string, err error, args ...any) {
+	slog.ErrorContext(c.Request.Context(), msg, append([]any{"error", err}, args...)...)
+}
+
+// temperatureOptions reúne os parâmetros opcionais das rotas de temperatura da v1
+type temperatureOptions struct {
+	mode   utils.ConversionMode
+	scales []utils.Scale
+	maxAge time.Duration
+}
+
+// parseTemperatureOptions interpreta ?mode=, ?scales= e ?max_age=
+// Escreve 400 e retorna false quando algum valor é inválido
+func parseTemperatureOptions(c *gin.Context) (temperatureOptions, bool) {
+	var opts temperatureOptions
+
+	mode, err := utils.ParseConversionMode(c.Query("mode"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid mode")
+		return opts, false
+	}
+	opts.mode = mode
+
+	if raw, ok := c.GetQuery("scales"); ok {
+		opts.scales, err = utils.ParseScales(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid scales")
+			return opts, false
+		}
+	}
+
+	maxAge, ok := parseMaxAge(c)
+	if !ok {
+		return opts, false
+	}
+	opts.maxAge = maxAge
+
+	return opts, true
+}
+
+// writeTemperature escreve a resposta no formato original ou com as escalas pedidas via ?scales=
+func writeTemperature(c *gin.Context, temperature *models.TemperatureResponse, opts temperatureOptions) {
+	if len(opts.scales) > 0 {
+		c.JSON(http.StatusOK, buildScaledResponse(temperature.TempC, opts.scales, opts.mode))
+		return
+	}
+	if opts.mode != utils.ModeLegacy {
+		temperature.TempK = utils.CelsiusToKelvinWithMode(temperature.TempC, opts.mode)
+	}
+	c.JSON(http.StatusOK, temperature)
+}
+
+// fetchTemperature busca a temperatura da cidade/estado, exigindo frescor quando maxAge > 0
+func (h *WeatherHandler) fetchTemperature(ctx context.Context, city, state string, maxAge time.Duration) (*models.TemperatureResponse, error) {
+	if maxAge <= 0 {
+		return h.weatherService.GetTemperatureByCity(ctx, city, state)
+	}
+
+	conditions, err := h.weatherService.GetFreshConditionsByCity(ctx, city, state, maxAge)
+	if err != nil {
+		return nil, err
+	}
+	tempC, tempF, tempK := utils.ConvertTemperatures(conditions.TempC)
+	return &models.TemperatureResponse{TempC: tempC, TempF: tempF, TempK: tempK}, nil
+}
+
+// parseMaxAge interpreta ?max_age= (segundos ou duração Go, ex: "90" ou "5m")
+// Escreve 400 e retorna false quando o valor é inválido
+func parseMaxAge(c *gin.Context) (time.Duration, bool) {
+	raw := strings.TrimSpace(c.Query("max_age"))
+	if raw == "" {
+		return 0, true
+	}
+
+	var maxAge time.Duration
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		maxAge = time.Duration(seconds) * time.Second
+	} else if parsed, err := time.ParseDuration(raw); err == nil {
+		maxAge = parsed
+	} else {
+		c.String(http.StatusBadRequest, "invalid max_age")
+		return 0, false
+	}
+
+	if maxAge <= 0 {
+		c.String(http.StatusBadRequest, "invalid max_age")
+		return 0, false
+	}
+	return maxAge, true
+}
+
+// resolveLocation busca a localização do CEP e escreve a resposta de erro adequada quando falha
+// Com ?lenient=true o CEP é extraído de entradas como "CEP: 01.310-100" antes da consulta
+func (h *WeatherHandler) resolveLocation(c *gin.Context, cep string) (*models.LocationInfo, bool) {
+	if lenient, _ := strconv.ParseBool(c.Query("lenient")); lenient {
+		parsed, err := utils.ParseCEP(cep, utils.CEPParseLenient)
+		if err != nil {
+			c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+			return nil, false
+		}
+		if len(parsed.Normalizations) > 0 {
+			applied := make([]string, len(parsed.Normalizations))
+			for i, n := range parsed.Normalizations {
+				applied[i] = string(n)
+			}
+			c.Header("X-CEP-Normalized", strings.Join(applied, ","))
+		}
+		cep = parsed.CEP
+	}
+
+	location, err := h.cepService.GetLocationByCEP(c.Request.Context(), cep)
 	if err != nil {
 		if strings.Contains(err.Error(), "invalid zipcode") {
 			c.String(http.StatusUnprocessableEntity, "invalid zipcode")
-			return
+			return nil, false
 		}
 		if strings.Contains(err.Error(), "can not find zipcode") {
 			c.String(http.StatusNotFound, "can not find zipcode")
-			return
+			return nil, false
 		}
 		// Erro interno do servidor
+		logError(c, "Falha ao consultar o CEP", err, "cep", cep)
 		c.String(http.StatusInternalServerError, "internal server error")
-		return
+		return nil, false
 	}
+	return location, true
+}
 
-	// 2. Busca temperatura pela cidade/estado
-	temperature, err := h.weatherService.GetTemperatureByCity(location.City, location.State)
-	if err != nil {
-		// Log do erro para debug (em produção usar logger)
-		c.String(http.StatusInternalServerError, "error fetching weather data")
-		return
+// buildScaledResponse monta a resposta com as escalas solicitadas via ?scales=
+func buildScaledResponse(celsius float64, scales []utils.Scale, mode utils.ConversionMode) *models.ScaledTemperatureResponse {
+	response := &models.ScaledTemperatureResponse{
+		Mode:         string(mode),
+		Temperatures: make(map[string]float64, len(scales)),
 	}
-
-	// 3. Retorna resposta com temperaturas
-	c.JSON(http.StatusOK, temperature)
+	for _, scale := range scales {
+		// ParseScales só devolve escalas suportadas, portanto o erro é impossível aqui
+		value, _ := utils.ConvertToScale(celsius, scale, mode)
+		response.Temperatures[string(scale)] = value
+	}
+	return response
 }
 
 // HealthCheck endpoint para verificação de saúde da aplicação
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"weather-cep-api/models"
+	"weather-cep-api/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -18,8 +22,8 @@ type MockCEPService struct {
 	mock.Mock
 }
 
-func (m *MockCEPService) GetLocationByCEP(cep string) (*models.LocationInfo, error) {
-	args := m.Called(cep)
+func (m *MockCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	args := m.Called(ctx, cep)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -31,19 +35,42 @@ type MockWeatherService struct {
 	mock.Mock
 }
 
-func (m *MockWeatherService) GetTemperatureByCity(city, state string) (*models.TemperatureResponse, error) {
-	args := m.Called(city, state)
+func (m *MockWeatherService) GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error) {
+	args := m.Called(ctx, city, state)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.TemperatureResponse), args.Error(1)
 }
 
+func (m *MockWeatherService) GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state, maxAge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
 func setupRouter(handler *WeatherHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.GET("/health", handler.HealthCheck)
-	router.GET("/temperature/:cep", handler.GetTemperatureByCEP)
+	RegisterRoutes(router, handler)
 	return router
 }
 
@@ -90,8 +117,8 @@ func TestWeatherHandler_GetTemperatureByCEP_Success(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockCEPService.On("GetLocationByCEP", "01310100").Return(locationInfo, nil)
-	mockWeatherService.On("GetTemperatureByCity", "São Paulo", "SP").Return(tempResponse, nil)
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
 
 	// Setup handler and router
 	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
@@ -125,7 +152,7 @@ func TestWeatherHandler_GetTemperatureByCEP_InvalidCEP(t *testing.T) {
 	mockWeatherService := new(MockWeatherService)
 
 	// Setup mock expectations
-	mockCEPService.On("GetLocationByCEP", "123").Return(nil, errors.New("invalid zipcode"))
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "123").Return(nil, errors.New("invalid zipcode"))
 
 	// Setup handler and router
 	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
@@ -152,7 +179,7 @@ func TestWeatherHandler_GetTemperatureByCEP_CEPNotFound(t *testing.T) {
 	mockWeatherService := new(MockWeatherService)
 
 	// Setup mock expectations
-	mockCEPService.On("GetLocationByCEP", "99999999").Return(nil, errors.New("can not find zipcode"))
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "99999999").Return(nil, errors.New("can not find zipcode"))
 
 	// Setup handler and router
 	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
@@ -186,8 +213,8 @@ func TestWeatherHandler_GetTemperatureByCEP_WeatherServiceError(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockCEPService.On("GetLocationByCEP", "01310100").Return(locationInfo, nil)
-	mockWeatherService.On("GetTemperatureByCity", "São Paulo", "SP").Return(nil, errors.New("weather API error"))
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(nil, errors.New("weather API error"))
 
 	// Setup handler and router
 	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
@@ -209,6 +236,56 @@ func TestWeatherHandler_GetTemperatureByCEP_WeatherServiceError(t *testing.T) {
 	mockWeatherService.AssertExpectations(t)
 }
 
+func TestWeatherHandler_NoAPIKeyAvailable(t *testing.T) {
+	tests := []struct {
+		name               string
+		path               string
+		err                error
+		expectedRetryAfter string
+	}{
+		{
+			name:               "Keys quarantined",
+			path:               "/temperature/01310100",
+			err:                &services.NoAPIKeyError{RetryAfter: 90*time.Second + time.Millisecond},
+			expectedRetryAfter: "91",
+		},
+		{
+			name:               "Wrapped error from the v2 route",
+			path:               "/v2/temperature/01310100",
+			err:                fmt.Errorf("%w: quota exceeded", &services.NoAPIKeyError{RetryAfter: time.Hour}),
+			expectedRetryAfter: "3600",
+		},
+		{
+			name:               "Sentinel without retry information",
+			path:               "/comfort/01310100",
+			err:                services.ErrNoAPIKeyAvailable,
+			expectedRetryAfter: "60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+			mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+			mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(nil, tt.err)
+			mockWeatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", mock.Anything).Return(nil, tt.err)
+			mockWeatherService.On("GetConditionsByCity", mock.Anything, "São Paulo", "SP").Return(nil, tt.err)
+
+			router := setupRouter(NewWeatherHandler(mockCEPService, mockWeatherService))
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Todas as chaves da WeatherAPI em quarentena ou sem cota: indisponibilidade temporária, não 500
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Equal(t, "weather data temporarily unavailable", w.Body.String())
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
 func TestWeatherHandler_GetTemperatureByCEP_EmptyCEP(t *testing.T) {
 	// Setup mocks
 	mockCEPService := new(MockCEPService)
@@ -235,7 +312,7 @@ func TestWeatherHandler_GetTemperatureByCEP_CEPServiceInternalError(t *testing.T
 	mockWeatherService := new(MockWeatherService)
 
 	// Setup mock expectations - internal error different from validation errors
-	mockCEPService.On("GetLocationByCEP", "01310100").Return(nil, errors.New("internal database error"))
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(nil, errors.New("internal database error"))
 
 	// Setup handler and router
 	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
@@ -254,4 +331,153 @@ func TestWeatherHandler_GetTemperatureByCEP_CEPServiceInternalError(t *testing.T
 
 	// Verify mock calls
 	mockCEPService.AssertExpectations(t)
-} 
\ No newline at end of file
+} 
+
+func TestWeatherHandler_GetTemperatureByCEP_Scales(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	// Create request
+	req, _ := http.NewRequest("GET", "/temperature/01310100?scales=K,Ra,Re&mode=exact", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ScaledTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "exact", response.Mode)
+	assert.Len(t, response.Temperatures, 3)
+	assert.InDelta(t, 298.15, response.Temperatures["K"], 1e-9)
+	assert.InDelta(t, 536.67, response.Temperatures["Ra"], 1e-9)
+	assert.InDelta(t, 20.0, response.Temperatures["Re"], 1e-9)
+}
+
+func TestWeatherHandler_GetTemperatureByCEP_ExactModeKeepsShape(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/01310100?mode=exact", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, response.TempC)
+	assert.Equal(t, 77.0, response.TempF)
+	assert.InDelta(t, 298.15, response.TempK, 1e-9)
+}
+
+func TestWeatherHandler_GetTemperatureByCEP_InvalidConversionParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "Unknown scale",
+			query:    "?scales=C,X",
+			expected: "invalid scales",
+		},
+		{
+			name:     "Empty scales",
+			query:    "?scales=",
+			expected: "invalid scales",
+		},
+		{
+			name:     "Unknown mode",
+			query:    "?mode=precise",
+			expected: "invalid mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nenhum serviço deve ser chamado quando os parâmetros são inválidos
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", "/temperature/01310100"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expected, w.Body.String())
+			mockCEPService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCEP_Lenient(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+
+	// O serviço de CEP recebe o CEP canônico
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	// "CEP: 01.310-100" codificado na URL
+	req, _ := http.NewRequest("GET", "/temperature/CEP:%2001.310-100?lenient=true", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "removed_prefix,removed_separators", w.Header().Get("X-CEP-Normalized"))
+	mockCEPService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetTemperatureByCEP_LenientInvalid(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/CEP:%200131?lenient=true", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "invalid zipcode", w.Body.String())
+	mockCEPService.AssertExpectations(t)
+}
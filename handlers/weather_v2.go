@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"weather-cep-api/models"
+	"weather-cep-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultV2Scales são as escalas retornadas pela v2 quando ?scales= não é informado
+var defaultV2Scales = []utils.Scale{utils.ScaleCelsius, utils.ScaleFahrenheit, utils.ScaleKelvin}
+
+// GetTemperatureByCEPV2 busca temperatura por CEP com localização, observação, provedor e unidades
+// GET /v2/temperature/:cep[?scales=C,K,Ra&mode=legacy&max_age=60]
+func (h *WeatherHandler) GetTemperatureByCEPV2(c *gin.Context) {
+	// Extrai CEP dos parâmetros da URL
+	cep := c.Param("cep")
+	if cep == "" {
+		c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+
+	// Na v2 o Kelvin é exato por padrão; ?mode=legacy restaura o offset de 273
+	mode := utils.ModeExact
+	if raw := c.Query("mode"); raw != "" {
+		parsed, err := utils.ParseConversionMode(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid mode")
+			return
+		}
+		mode = parsed
+	}
+	scales := defaultV2Scales
+	if raw, ok := c.GetQuery("scales"); ok {
+		parsed, err := utils.ParseScales(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid scales")
+			return
+		}
+		scales = parsed
+	}
+	maxAge, ok := parseMaxAge(c)
+	if !ok {
+		return
+	}
+
+	// 1. Busca informações de localização pelo CEP
+	location, ok := h.resolveLocation(c, cep)
+	if !ok {
+		return
+	}
+
+	// 2. Busca as condições atuais pela cidade/estado
+	conditions, err := h.weatherService.GetFreshConditionsByCity(c.Request.Context(), location.City, location.State, maxAge)
+	if err != nil {
+		logError(c, "Falha ao consultar o clima", err, "city", location.City, "state", location.State)
+		writeWeatherError(c, err)
+		return
+	}
+
+	// 3. Retorna resposta com metadados
+	c.JSON(http.StatusOK, buildV2Response(location, conditions, scales, mode, time.Now()))
+}
+
+// buildV2Response monta a resposta de temperatura da v2
+func buildV2Response(location *models.LocationInfo, conditions *models.WeatherConditions, scales []utils.Scale, mode utils.ConversionMode, now time.Time) *models.TemperatureResponseV2 {
+	scaled := buildScaledResponse(conditions.TempC, scales, mode)
+
+	units := models.UnitsInfo{
+		Mode:   string(mode),
+		Scales: make(map[string]string, len(scales)),
+	}
+	for _, scale := range scales {
+		units.Scales[string(scale)] = utils.ScaleName(scale)
+	}
+
+	response := &models.TemperatureResponseV2{
+		Location:     *location,
+		Temperatures: scaled.Temperatures,
+		Units:        units,
+		Freshness:    buildFreshness(conditions, now),
+		Provider:     conditions.Provider,
+		Cache:        conditions.CacheStatus,
+	}
+	if !conditions.ObservedAt.IsZero() {
+		observedAt := conditions.ObservedAt
+		response.ObservedAt = &observedAt
+	}
+	return response
+}
+
+// buildFreshness calcula o horário local da observação, a idade da leitura e se veio do cache
+func buildFreshness(conditions *models.WeatherConditions, now time.Time) models.FreshnessInfo {
+	freshness := models.FreshnessInfo{
+		TimeZone: conditions.TimeZone,
+		Cached:   conditions.CacheStatus == models.CacheStatusHit,
+	}
+
+	reference := conditions.ObservedAt
+	if reference.IsZero() {
+		reference = conditions.FetchedAt
+	}
+	if !reference.IsZero() {
+		if age := now.Sub(reference); age > 0 {
+			freshness.AgeSeconds = int64(age / time.Second)
+		}
+	}
+
+	if !conditions.ObservedAt.IsZero() && conditions.TimeZone != "" {
+		if loc, err := time.LoadLocation(conditions.TimeZone); err == nil {
+			freshness.ObservedAtLocal = conditions.ObservedAt.In(loc).Format(time.RFC3339)
+		}
+	}
+	return freshness
+}
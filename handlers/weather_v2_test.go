@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherHandler_V1RoutesMatchUnversioned(t *testing.T) {
+	for _, path := range []string{"/temperature/01310100", "/v1/temperature/01310100"} {
+		t.Run(path, func(t *testing.T) {
+			// Setup mocks
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+
+			locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+			tempResponse := &models.TemperatureResponse{TempC: 25.0, TempF: 77.0, TempK: 298.0}
+
+			mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+			mockWeatherService.On("GetTemperatureByCity", mock.Anything, "São Paulo", "SP").Return(tempResponse, nil)
+
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			// O contrato da v1 é exatamente o original
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"temp_C":25,"temp_F":77,"temp_K":298}`, w.Body.String())
+		})
+	}
+}
+
+func TestWeatherHandler_GetTemperatureByCEPV2_Success(t *testing.T) {
+	// Setup mocks
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	observedAt := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	conditions := &models.WeatherConditions{
+		TempC:       25.0,
+		ObservedAt:  observedAt,
+		Provider:    "weatherapi",
+		CacheStatus: models.CacheStatusMiss,
+	}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", time.Duration(0)).Return(conditions, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	// Create request
+	req, _ := http.NewRequest("GET", "/v2/temperature/01310100", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TemperatureResponseV2
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, *locationInfo, response.Location)
+	assert.Equal(t, map[string]float64{"C": 25, "F": 77, "K": 298.15}, response.Temperatures)
+	assert.Equal(t, "exact", response.Units.Mode)
+	assert.Equal(t, map[string]string{"C": "celsius", "F": "fahrenheit", "K": "kelvin"}, response.Units.Scales)
+	require.NotNil(t, response.ObservedAt)
+	assert.True(t, observedAt.Equal(*response.ObservedAt))
+	assert.Equal(t, "weatherapi", response.Provider)
+	assert.Equal(t, "miss", response.Cache)
+
+	mockCEPService.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetTemperatureByCEPV2_LegacyModeAndScales(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	conditions := &models.WeatherConditions{TempC: 25.0, Provider: "weatherapi", CacheStatus: models.CacheStatusMiss}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", time.Duration(0)).Return(conditions, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/v2/temperature/01310100?mode=legacy&scales=K,Re", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TemperatureResponseV2
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"K": 298, "Re": 20}, response.Temperatures)
+	assert.Equal(t, "legacy", response.Units.Mode)
+	assert.Nil(t, response.ObservedAt)
+}
+
+func TestWeatherHandler_GetTemperatureByCEPV2_InvalidCEP(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "123").Return(nil, errors.New("invalid zipcode"))
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/v2/temperature/123", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "invalid zipcode", w.Body.String())
+}
+
+func TestWeatherHandler_GetTemperatureByCEPV2_Freshness(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	observedAt := time.Now().Add(-90 * time.Second).UTC().Truncate(time.Second)
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	conditions := &models.WeatherConditions{
+		TempC:       25.0,
+		ObservedAt:  observedAt,
+		TimeZone:    "America/Sao_Paulo",
+		Provider:    "weatherapi",
+		CacheStatus: models.CacheStatusHit,
+	}
+
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", 5*time.Minute).Return(conditions, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/v2/temperature/01310100?max_age=5m", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TemperatureResponseV2
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.True(t, response.Freshness.Cached)
+	assert.Equal(t, "America/Sao_Paulo", response.Freshness.TimeZone)
+	assert.InDelta(t, 90, response.Freshness.AgeSeconds, 5)
+
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	assert.Equal(t, observedAt.In(loc).Format(time.RFC3339), response.Freshness.ObservedAtLocal)
+
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_GetTemperatureByCEP_MaxAge(t *testing.T) {
+	mockCEPService := new(MockCEPService)
+	mockWeatherService := new(MockWeatherService)
+
+	locationInfo := &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}
+	conditions := &models.WeatherConditions{TempC: 25.0, CacheStatus: models.CacheStatusBypass}
+
+	// ?max_age=60 em segundos deve forçar a consulta com exigência de frescor
+	mockCEPService.On("GetLocationByCEP", mock.Anything, "01310100").Return(locationInfo, nil)
+	mockWeatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", time.Minute).Return(conditions, nil)
+
+	handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/temperature/01310100?max_age=60", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// O contrato da v1 não muda
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"temp_C":25,"temp_F":77,"temp_K":298}`, w.Body.String())
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestWeatherHandler_InvalidMaxAge(t *testing.T) {
+	for _, value := range []string{"abc", "-10", "0"} {
+		t.Run(value, func(t *testing.T) {
+			mockCEPService := new(MockCEPService)
+			mockWeatherService := new(MockWeatherService)
+			handler := NewWeatherHandler(mockCEPService, mockWeatherService)
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", "/v2/temperature/01310100?max_age="+value, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "invalid max_age", w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Package health implementa as verificações de prontidão da API (/readyz)
+//
+// Cada verificação é uma função nomeada; o Checker roda todas em paralelo, com timeout,
+// e monta o detalhamento. Verificações caras (chamadas aos provedores) são envolvidas
+// por Cached para que probes frequentes do orquestrador não gerem tráfego externo.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc verifica uma dependência e retorna o motivo da falha (nil se estiver saudável)
+type CheckFunc func(ctx context.Context) error
+
+// Config descreve as verificações de prontidão
+type Config struct {
+	// Timeout limita cada execução de /readyz
+	Timeout time.Duration
+	// ProbeInterval é o intervalo mínimo entre duas chamadas reais a cada provedor
+	ProbeInterval time.Duration
+}
+
+// DefaultConfig retorna a configuração padrão
+func DefaultConfig() Config {
+	return Config{
+		Timeout:       3 * time.Second,
+		ProbeInterval: 30 * time.Second,
+	}
+}
+
+// ConfigFrom lê READINESS_TIMEOUT e READINESS_PROBE_INTERVAL
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	for name, target := range map[string]*time.Duration{
+		"READINESS_TIMEOUT":        &cfg.Timeout,
+		"READINESS_PROBE_INTERVAL": &cfg.ProbeInterval,
+	} {
+		raw := strings.TrimSpace(getenv(name))
+		if raw == "" {
+			continue
+		}
+		value, err := time.ParseDuration(raw)
+		if err != nil || value <= 0 {
+			return cfg, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		*target = value
+	}
+	return cfg, nil
+}
+
+// namedCheck associa uma verificação ao nome exibido no detalhamento
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Checker agrega as verificações de prontidão
+type Checker struct {
+	timeout time.Duration
+	now     func() time.Time
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// NewChecker cria um agregador de verificações com o timeout informado
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, now: time.Now}
+}
+
+// Add registra uma verificação (nomes repetidos substituem a anterior)
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i].check = check
+			return
+		}
+	}
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Run executa as verificações em paralelo; a API está pronta se todas passarem
+// Uma verificação que não termina dentro do timeout conta como falha
+func (c *Checker) Run(ctx context.Context) models.ReadinessResponse {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	response := models.ReadinessResponse{
+		Status: StatusOK,
+		Checks: make(map[string]models.HealthCheckResult, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			result := c.run(ctx, nc.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[nc.name] = result
+			if result.Status != StatusOK {
+				response.Status = StatusFail
+			}
+		}(nc)
+	}
+	wg.Wait()
+	return response
+}
+
+// run executa uma verificação respeitando o prazo do contexto
+func (c *Checker) run(ctx context.Context, check CheckFunc) models.HealthCheckResult {
+	start := c.now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	result := models.HealthCheckResult{
+		Status:    StatusOK,
+		CheckedAt: start.UTC().Format(time.RFC3339),
+		Duration:  c.now().Sub(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Cached limita a verificação a uma execução real por intervalo; no meio tempo repete o último resultado
+// Chamadas simultâneas esperam a execução em andamento em vez de disparar outras
+func Cached(check CheckFunc, interval time.Duration) CheckFunc {
+	return cachedWithClock(check, interval, time.Now)
+}
+
+// cachedWithClock implementa Cached com relógio customizado
+func cachedWithClock(check CheckFunc, interval time.Duration, now func() time.Time) CheckFunc {
+	var mu sync.Mutex
+	var lastRun time.Time
+	var lastErr error
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !lastRun.IsZero() && now().Sub(lastRun) < interval {
+			return lastErr
+		}
+		lastErr = check(ctx)
+		lastRun = now()
+		return lastErr
+	}
+}
+
+// HTTPCheck verifica se o provedor responde: erros de rede e respostas 5xx são falha
+// Respostas 4xx (ex: WeatherAPI sem chave) mostram que o provedor está acessível
+func HTTPCheck(client services.HTTPClientInterface, url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("unreachable: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Ready converte um indicador de prontidão (ex: pré-aquecimento concluído) em verificação
+func Ready(ready func() bool, reason string) CheckFunc {
+	return func(context.Context) error {
+		if !ready() {
+			return errors.New(reason)
+		}
+		return nil
+	}
+}
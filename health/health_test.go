@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHTTPClient é um mock do HTTPClientInterface
+type MockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+func TestChecker_Run(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("config", func(context.Context) error { return nil })
+	checker.Add("cache", func(context.Context) error { return errors.New("error connecting to redis") })
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	response := checker.Run(context.Background())
+	assert.Equal(t, StatusFail, response.Status)
+	require.Len(t, response.Checks, 3)
+	assert.Equal(t, StatusOK, response.Checks["config"].Status)
+	assert.Empty(t, response.Checks["config"].Error)
+	assert.NotEmpty(t, response.Checks["config"].CheckedAt)
+	assert.Equal(t, StatusFail, response.Checks["cache"].Status)
+	assert.Equal(t, "error connecting to redis", response.Checks["cache"].Error)
+	assert.Equal(t, StatusFail, response.Checks["slow"].Status)
+	assert.Contains(t, response.Checks["slow"].Error, "timed out")
+
+	// Substituir as verificações com falha deixa a API pronta
+	checker.Add("cache", func(context.Context) error { return nil })
+	checker.Add("slow", func(context.Context) error { return nil })
+	response = checker.Run(context.Background())
+	assert.Equal(t, StatusOK, response.Status)
+	assert.Len(t, response.Checks, 3)
+}
+
+func TestCached(t *testing.T) {
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	calls := 0
+	result := errors.New("unreachable")
+	check := cachedWithClock(func(context.Context) error {
+		calls++
+		return result
+	}, 30*time.Second, func() time.Time { return now })
+
+	assert.EqualError(t, check(context.Background()), "unreachable")
+	result = nil
+	now = now.Add(10 * time.Second)
+	assert.EqualError(t, check(context.Background()), "unreachable", "result reused inside the interval")
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(20 * time.Second)
+	assert.NoError(t, check(context.Background()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestHTTPCheck(t *testing.T) {
+	const url = "https://api.weatherapi.com/v1/current.json"
+
+	tests := []struct {
+		name        string
+		response    *http.Response
+		err         error
+		expectError string
+	}{
+		{name: "Reachable", response: &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}},
+		{name: "Client error means reachable", response: &http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}},
+		{name: "Server error", response: &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, expectError: "unexpected status 503"},
+		{name: "Network error", err: errors.New("dial tcp: i/o timeout"), expectError: "unreachable: dial tcp: i/o timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(MockHTTPClient)
+			client.On("Do", url).Return(tt.response, tt.err)
+
+			err := HTTPCheck(client, url)(context.Background())
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReady(t *testing.T) {
+	ready := false
+	check := Ready(func() bool { return ready }, "cache warmup in progress")
+	assert.EqualError(t, check(context.Background()), "cache warmup in progress")
+
+	ready = true
+	assert.NoError(t, check(context.Background()))
+}
+
+func TestConfigFrom(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	cfg, err := ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+
+	env["READINESS_TIMEOUT"] = "1s"
+	env["READINESS_PROBE_INTERVAL"] = "1m"
+	cfg, err = ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, Config{Timeout: time.Second, ProbeInterval: time.Minute}, cfg)
+
+	env["READINESS_PROBE_INTERVAL"] = "0"
+	_, err = ConfigFrom(getenv)
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Package ibge mantém a tabela de municípios do IBGE (código, nome, UF e coordenadas)
+package ibge
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"weather-cep-api/utils"
+)
+
+// embeddedCSV é a tabela de municípios embutida no binário, gerada por cmd/ibge-import; outra
+// tabela no mesmo formato pode substituí-la com LoadFile (IBGE_DATASET)
+//
+//go:embed municipios.csv
+var embeddedCSV string
+
+// codeRegex valida códigos de município do IBGE (7 dígitos)
+var codeRegex = regexp.MustCompile(`^\d{7}$`)
+
+// Municipality representa um município da tabela do IBGE
+type Municipality struct {
+	Code      string
+	Name      string
+	UF        string
+	Latitude  float64
+	Longitude float64
+}
+
+// Table indexa os municípios pelo código do IBGE
+type Table struct {
+	byCode  map[string]Municipality
+	ordered []Municipality
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultTable *Table
+)
+
+// IsValidCode verifica se o código tem o formato de código de município do IBGE
+func IsValidCode(code string) bool {
+	return codeRegex.MatchString(strings.TrimSpace(code))
+}
+
+// Parse lê uma tabela no formato CSV "codigo_ibge,nome,uf,latitude,longitude" (com cabeçalho)
+func Parse(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 5
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading IBGE table: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("error reading IBGE table: empty file")
+	}
+
+	table := &Table{byCode: make(map[string]Municipality, len(records)-1)}
+	for i, record := range records[1:] {
+		line := i + 2
+		code := strings.TrimSpace(record[0])
+		if !IsValidCode(code) {
+			return nil, fmt.Errorf("invalid IBGE code %q on line %d", code, line)
+		}
+		uf := utils.NormalizeUF(record[2])
+		if !utils.IsValidUF(uf) {
+			return nil, fmt.Errorf("invalid UF %q on line %d", record[2], line)
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		if latErr != nil || lonErr != nil || !utils.IsValidCoordinates(lat, lon) {
+			return nil, fmt.Errorf("invalid coordinates on line %d", line)
+		}
+
+		municipality := Municipality{
+			Code:      code,
+			Name:      strings.TrimSpace(record[1]),
+			UF:        uf,
+			Latitude:  lat,
+			Longitude: lon,
+		}
+		table.byCode[code] = municipality
+		table.ordered = append(table.ordered, municipality)
+	}
+
+	return table, nil
+}
+
+// Lookup busca um município pelo código do IBGE
+func (t *Table) Lookup(code string) (Municipality, bool) {
+	municipality, ok := t.byCode[strings.TrimSpace(code)]
+	return municipality, ok
+}
+
+// All retorna todos os municípios na ordem do arquivo
+func (t *Table) All() []Municipality {
+	return append([]Municipality(nil), t.ordered...)
+}
+
+// Len retorna o número de municípios da tabela
+func (t *Table) Len() int {
+	return len(t.ordered)
+}
+
+// Default retorna a tabela padrão (a embutida, a menos que LoadFile tenha sido chamado)
+func Default() *Table {
+	defaultMu.RLock()
+	table := defaultTable
+	defaultMu.RUnlock()
+	if table != nil {
+		return table
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultTable == nil {
+		parsed, err := Parse(strings.NewReader(embeddedCSV))
+		if err != nil {
+			// A tabela embutida é validada nos testes; falhar aqui é erro de build
+			panic(err)
+		}
+		defaultTable = parsed
+	}
+	return defaultTable
+}
+
+// LoadFile substitui a tabela padrão pelo conteúdo de um arquivo CSV (ex: a tabela completa do IBGE)
+func LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening IBGE table: %w", err)
+	}
+	defer file.Close()
+
+	table, err := Parse(file)
+	if err != nil {
+		return err
+	}
+
+	defaultMu.Lock()
+	defaultTable = table
+	defaultMu.Unlock()
+	return nil
+}
+
+// Lookup busca um município na tabela padrão
+func Lookup(code string) (Municipality, bool) {
+	return Default().Lookup(code)
+}
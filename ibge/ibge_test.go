@@ -0,0 +1,98 @@
+package ibge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault_EmbeddedTable(t *testing.T) {
+	table := Default()
+
+	// A tabela embutida inclui ao menos as 27 capitais
+	assert.GreaterOrEqual(t, table.Len(), 27)
+
+	municipality, ok := table.Lookup("3550308")
+	require.True(t, ok)
+	assert.Equal(t, "São Paulo", municipality.Name)
+	assert.Equal(t, "SP", municipality.UF)
+	assert.InDelta(t, -23.53, municipality.Latitude, 0.01)
+
+	_, ok = table.Lookup("9999999")
+	assert.False(t, ok)
+}
+
+func TestIsValidCode(t *testing.T) {
+	assert.True(t, IsValidCode("3550308"))
+	assert.True(t, IsValidCode(" 5300108 "))
+	assert.False(t, IsValidCode("355030"))
+	assert.False(t, IsValidCode("35503080"))
+	assert.False(t, IsValidCode("35a0308"))
+}
+
+func TestParse_InvalidRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Invalid code", content: "codigo_ibge,nome,uf,latitude,longitude\n123,Cidade,SP,-23,-46\n"},
+		{name: "Invalid UF", content: "codigo_ibge,nome,uf,latitude,longitude\n3550308,São Paulo,XX,-23,-46\n"},
+		{name: "Invalid latitude", content: "codigo_ibge,nome,uf,latitude,longitude\n3550308,São Paulo,SP,abc,-46\n"},
+		{name: "Missing columns", content: "codigo_ibge,nome,uf,latitude,longitude\n3550308,São Paulo,SP\n"},
+		{name: "Empty file", content: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	input := "\ufeffcodigo_ibge,nome,latitude,longitude,capital,codigo_uf\n" +
+		"5300108,Brasília,-15.7795,-47.9297,1,53\n" +
+		"3509007,Cajamar,-23.355,-46.8781,0,35\n"
+	var out strings.Builder
+	imported, err := Import(strings.NewReader(input), &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, "codigo_ibge,nome,uf,latitude,longitude\n"+
+		"5300108,Brasília,DF,-15.7795,-47.9297\n"+
+		"3509007,Cajamar,SP,-23.355,-46.8781\n", out.String())
+
+	// A saída é lida como a tabela embutida
+	table, err := Parse(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	assert.Equal(t, 2, table.Len())
+
+	_, err = Import(strings.NewReader("codigo_ibge,nome,latitude\n5300108,Brasília,-15.7795\n"), &out)
+	assert.ErrorContains(t, err, `missing column "longitude"`)
+	_, err = Import(strings.NewReader("codigo_ibge,nome,latitude,longitude\n9900108,Lugar,-15,-47\n"), &out)
+	assert.ErrorContains(t, err, "unknown UF")
+}
+
+func TestLoadFile_ReplacesDefault(t *testing.T) {
+	original := Default()
+	defer func() {
+		defaultMu.Lock()
+		defaultTable = original
+		defaultMu.Unlock()
+	}()
+
+	path := filepath.Join(t.TempDir(), "municipios.csv")
+	content := "codigo_ibge,nome,uf,latitude,longitude\n3509007,Cajamar,SP,-23.3550,-46.8781\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	require.NoError(t, LoadFile(path))
+
+	municipality, ok := Lookup("3509007")
+	require.True(t, ok)
+	assert.Equal(t, "Cajamar", municipality.Name)
+	assert.Equal(t, 1, Default().Len())
+}
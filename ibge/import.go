@@ -0,0 +1,88 @@
+package ibge
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ufByCode relaciona os dois primeiros dígitos do código de município do IBGE à UF
+var ufByCode = map[string]string{
+	"11": "RO", "12": "AC", "13": "AM", "14": "RR", "15": "PA", "16": "AP", "17": "TO",
+	"21": "MA", "22": "PI", "23": "CE", "24": "RN", "25": "PB", "26": "PE", "27": "AL", "28": "SE", "29": "BA",
+	"31": "MG", "32": "ES", "33": "RJ", "35": "SP",
+	"41": "PR", "42": "SC", "43": "RS",
+	"50": "MS", "51": "MT", "52": "GO", "53": "DF",
+}
+
+// Import converte uma tabela pública de municípios com as colunas codigo_ibge, nome, latitude e
+// longitude (ex: github.com/kelvins/municipios-brasileiros) no formato embutido e a grava em w
+// As demais colunas são ignoradas e a UF vem do código do município; retorna o número de municípios
+func Import(r io.Reader, w io.Writer) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("error reading municipalities: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	var indexes []int
+	for _, name := range []string{"codigo_ibge", "nome", "latitude", "longitude"} {
+		index, ok := columns[name]
+		if !ok {
+			return 0, fmt.Errorf("error reading municipalities: missing column %q", name)
+		}
+		indexes = append(indexes, index)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"codigo_ibge", "nome", "uf", "latitude", "longitude"}); err != nil {
+		return 0, err
+	}
+	count := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error reading municipalities: %w", err)
+		}
+		fields := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index >= len(record) {
+				return 0, fmt.Errorf("missing columns on line %d", line)
+			}
+			fields[i] = strings.TrimSpace(record[index])
+		}
+
+		code, name := fields[0], fields[1]
+		if !IsValidCode(code) {
+			return 0, fmt.Errorf("invalid IBGE code %q on line %d", code, line)
+		}
+		uf, ok := ufByCode[code[:2]]
+		if !ok {
+			return 0, fmt.Errorf("unknown UF for IBGE code %q on line %d", code, line)
+		}
+		lat, latErr := strconv.ParseFloat(fields[2], 64)
+		lon, lonErr := strconv.ParseFloat(fields[3], 64)
+		if latErr != nil || lonErr != nil {
+			return 0, fmt.Errorf("invalid coordinates on line %d", line)
+		}
+
+		row := []string{code, name, uf, strconv.FormatFloat(lat, 'f', -1, 64), strconv.FormatFloat(lon, 'f', -1, 64)}
+		if err := writer.Write(row); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	writer.Flush()
+	return count, writer.Error()
+}
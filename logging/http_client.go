@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+	"weather-cep-api/services"
+)
+
+// LoggingClient é o decorator de HTTPClientInterface que repassa o X-Request-ID e registra as falhas de um provedor
+type LoggingClient struct {
+	provider string
+	inner    services.HTTPClientInterface
+	logger   *slog.Logger
+}
+
+// InstrumentClient envolve o client HTTP de um provedor com o registro das falhas
+func InstrumentClient(logger *slog.Logger, provider string, inner services.HTTPClientInterface) *LoggingClient {
+	return &LoggingClient{
+		provider: provider,
+		inner:    inner,
+		logger:   logger,
+	}
+}
+
+// Do executa a requisição e registra falhas de rede e respostas 5xx com a causa
+// Respostas 4xx (ex: chave inválida na WeatherAPI) são registradas como aviso
+func (c *LoggingClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if id := RequestIDFromContext(ctx); id != "" {
+		req = req.Clone(ctx)
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	start := time.Now()
+	resp, err := c.inner.Do(req)
+	attrs := []slog.Attr{
+		slog.String("provider", c.provider),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+
+	switch {
+	case err != nil:
+		c.logger.LogAttrs(ctx, slog.LevelError, "Falha na chamada ao provedor", append(attrs, slog.Any("error", err))...)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		c.logger.LogAttrs(ctx, slog.LevelError, "Falha na chamada ao provedor", append(attrs, slog.Int("status", resp.StatusCode))...)
+	case resp.StatusCode >= http.StatusBadRequest:
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "Provedor recusou a requisição", append(attrs, slog.Int("status", resp.StatusCode))...)
+	}
+	return resp, err
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Format identifica o formato de saída dos logs
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+)
+
+// Config descreve o logger da API
+type Config struct {
+	Level  slog.Level
+	Format Format
+}
+
+// ConfigFrom lê LOG_LEVEL (debug, info, warn ou error; padrão info) e LOG_FORMAT (json ou text; padrão json)
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := Config{Level: slog.LevelInfo, Format: FormatJSON}
+
+	if raw := strings.TrimSpace(getenv("LOG_LEVEL")); raw != "" {
+		if err := cfg.Level.UnmarshalText([]byte(raw)); err != nil {
+			return cfg, fmt.Errorf("invalid LOG_LEVEL: %q", raw)
+		}
+	}
+	if raw := strings.TrimSpace(getenv("LOG_FORMAT")); raw != "" {
+		cfg.Format = Format(strings.ToLower(raw))
+		if cfg.Format != FormatJSON && cfg.Format != FormatText {
+			return cfg, fmt.Errorf("invalid LOG_FORMAT: %q (use json or text)", raw)
+		}
+	}
+	return cfg, nil
+}
+
+// New cria o logger estruturado que escreve em w
+// Toda mensagem e atributo passa pela remoção de segredos, e os logs com contexto recebem request_id e trace_id
+func New(w io.Writer, cfg Config) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       cfg.Level,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if cfg.Format == FormatText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// secretPattern encontra os parâmetros de query com segredos (ex: key= da WeatherAPI)
+var secretPattern = regexp.MustCompile(`(?i)([?&](?:key|api_key|apikey|token|access_token)=)[^&\s"']+`)
+
+// Redacted substitui o valor dos segredos removidos
+const Redacted = "REDACTED"
+
+// Redact remove os segredos de query string de um texto (URLs e mensagens de erro que as contêm)
+func Redact(s string) string {
+	return secretPattern.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// redactAttr aplica Redact nas mensagens, nos atributos de texto e nos erros
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(Redact(attr.Value.String()))
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case error:
+			attr.Value = slog.StringValue(Redact(value.Error()))
+		case fmt.Stringer:
+			attr.Value = slog.StringValue(Redact(value.String()))
+		}
+	}
+	return attr
+}
+
+// contextHandler acrescenta ao registro os identificadores da requisição presentes no contexto
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle inclui request_id, trace_id e span_id quando disponíveis
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if id := RequestIDFromContext(ctx); id != "" {
+			record.AddAttrs(slog.String("request_id", id))
+		}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs mantém o decorator ao derivar o handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup mantém o decorator ao derivar o handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
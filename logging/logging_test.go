@@ -0,0 +1,305 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MockHTTPClient é um mock do HTTPClientInterface que guarda as requisições enviadas
+type MockHTTPClient struct {
+	mock.Mock
+	sent []*http.Request
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.sent = append(m.sent, req)
+	args := m.Called(req.URL.String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+// decodeLines interpreta cada linha JSON escrita pelo logger
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "WeatherAPI URL",
+			input:    "https://api.weatherapi.com/v1/current.json?key=abc123&q=Recife&aqi=no",
+			expected: "https://api.weatherapi.com/v1/current.json?key=REDACTED&q=Recife&aqi=no",
+		},
+		{
+			name:     "Key as last parameter",
+			input:    "/v1/current.json?q=Recife&key=abc123",
+			expected: "/v1/current.json?q=Recife&key=REDACTED",
+		},
+		{
+			name:     "URL inside error message",
+			input:    `Get "https://api.weatherapi.com/v1/current.json?key=abc123&q=Recife": dial tcp: i/o timeout`,
+			expected: `Get "https://api.weatherapi.com/v1/current.json?key=REDACTED&q=Recife": dial tcp: i/o timeout`,
+		},
+		{
+			name:     "Other secrets",
+			input:    "/callback?access_token=xyz&API_KEY=k1",
+			expected: "/callback?access_token=REDACTED&API_KEY=REDACTED",
+		},
+		{
+			name:     "Similar parameter names are kept",
+			input:    "/search?monkey=1&keyword=sol",
+			expected: "/search?monkey=1&keyword=sol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Redact(tt.input))
+		})
+	}
+}
+
+func TestNew_RedactsMessagesAndAttributes(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, Config{Level: slog.LevelInfo, Format: FormatJSON})
+
+	err := fmt.Errorf("error fetching weather data: %w", errors.New(`Get "https://api.weatherapi.com/v1/current.json?key=abc123&q=Recife": EOF`))
+	logger.Error("Falha em https://api.weatherapi.com/v1/current.json?key=abc123", "error", err, "url", "https://api.weatherapi.com/v1/current.json?key=abc123")
+	logger.Debug("descartado pelo nível")
+
+	// log.Printf também passa pelo logger estruturado (e pela remoção de segredos)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	log.Printf("consultando https://api.weatherapi.com/v1/current.json?key=abc123")
+	slog.SetDefault(previous)
+
+	assert.NotContains(t, out.String(), "abc123")
+	entries := decodeLines(t, &out)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ERROR", entries[0]["level"])
+	assert.Equal(t, "Falha em https://api.weatherapi.com/v1/current.json?key=REDACTED", entries[0]["msg"])
+	assert.Contains(t, entries[0]["error"], "key=REDACTED&q=Recife")
+	assert.Equal(t, "https://api.weatherapi.com/v1/current.json?key=REDACTED", entries[0]["url"])
+	assert.Equal(t, "consultando https://api.weatherapi.com/v1/current.json?key=REDACTED", entries[1]["msg"])
+}
+
+func TestNew_ContextAttributes(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, Config{Level: slog.LevelDebug, Format: FormatJSON}).With("component", "test")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-123"),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	logger.DebugContext(ctx, "com contexto")
+	logger.Info("sem contexto")
+
+	entries := decodeLines(t, &out)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "req-123", entries[0]["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", entries[0]["span_id"])
+	assert.Equal(t, "test", entries[0]["component"])
+	assert.NotContains(t, entries[1], "request_id")
+}
+
+func TestConfigFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       string
+		format      string
+		expected    Config
+		expectError bool
+	}{
+		{name: "Defaults", expected: Config{Level: slog.LevelInfo, Format: FormatJSON}},
+		{name: "Debug text", level: "debug", format: "TEXT", expected: Config{Level: slog.LevelDebug, Format: FormatText}},
+		{name: "Warn", level: "WARN", expected: Config{Level: slog.LevelWarn, Format: FormatJSON}},
+		{name: "Invalid level", level: "verbose", expectError: true},
+		{name: "Invalid format", format: "xml", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"LOG_LEVEL": tt.level, "LOG_FORMAT": tt.format}
+
+			cfg, err := ConfigFrom(func(key string) string { return env[key] })
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, RequestIDFromContext(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Propagates received ID", header: "abc-123_X.y:z", expected: "abc-123_X.y:z"},
+		{name: "Generates when missing", header: ""},
+		{name: "Replaces unsafe ID", header: "abc\" injected=1"},
+		{name: "Replaces long ID", header: strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/health", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, id, w.Body.String())
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, id)
+			} else {
+				assert.Len(t, id, 32)
+				assert.NotEqual(t, tt.header, id)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, Config{Level: slog.LevelInfo, Format: FormatJSON})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logger))
+	router.GET("/temperature/:cep", func(c *gin.Context) {
+		if c.Param("cep") == "99999999" {
+			c.String(http.StatusNotFound, "can not find zipcode")
+			return
+		}
+		c.String(http.StatusInternalServerError, "error fetching weather data")
+	})
+
+	for _, path := range []string{"/temperature/99999999", "/temperature/01310100?key=abc123"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := decodeLines(t, &out)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "WARN", entries[0]["level"])
+	assert.Equal(t, "/temperature/:cep", entries[0]["route"])
+	assert.Equal(t, float64(http.StatusNotFound), entries[0]["status"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, "/temperature/01310100?key=REDACTED", entries[1]["path"])
+}
+
+func TestLoggingClient_Do(t *testing.T) {
+	const weatherURL = "https://api.weatherapi.com/v1/current.json?key=abc123&q=Recife"
+
+	tests := []struct {
+		name          string
+		response      *http.Response
+		err           error
+		expectedLevel string
+		expectedCause string
+	}{
+		{
+			name:     "Success is not logged",
+			response: &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))},
+		},
+		{
+			name:          "Rejected request",
+			response:      &http.Response{StatusCode: http.StatusForbidden, Body: http.NoBody},
+			expectedLevel: "WARN",
+			expectedCause: "status",
+		},
+		{
+			name:          "Server error",
+			response:      &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody},
+			expectedLevel: "ERROR",
+			expectedCause: "status",
+		},
+		{
+			name:          "Network error",
+			err:           fmt.Errorf(`Get "%s": dial tcp: i/o timeout`, weatherURL),
+			expectedLevel: "ERROR",
+			expectedCause: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			mockClient := new(MockHTTPClient)
+			mockClient.On("Do", weatherURL).Return(tt.response, tt.err)
+			client := InstrumentClient(New(&out, Config{Level: slog.LevelInfo, Format: FormatJSON}), "weatherapi", mockClient)
+
+			req := httptest.NewRequest("GET", weatherURL, nil).WithContext(WithRequestID(context.Background(), "req-42"))
+			resp, err := client.Do(req)
+			assert.Equal(t, tt.response, resp)
+			assert.Equal(t, tt.err, err)
+
+			// O X-Request-ID é repassado ao provedor sem alterar a requisição original
+			require.Len(t, mockClient.sent, 1)
+			assert.Equal(t, "req-42", mockClient.sent[0].Header.Get(RequestIDHeader))
+			assert.Empty(t, req.Header.Get(RequestIDHeader))
+
+			if tt.expectedLevel == "" {
+				assert.Empty(t, out.String())
+				return
+			}
+			assert.NotContains(t, out.String(), "abc123")
+			entries := decodeLines(t, &out)
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expectedLevel, entries[0]["level"])
+			assert.Equal(t, "weatherapi", entries[0]["provider"])
+			assert.Equal(t, "req-42", entries[0]["request_id"])
+			assert.Contains(t, entries[0], tt.expectedCause)
+			assert.Equal(t, "https://api.weatherapi.com/v1/current.json?key=REDACTED&q=Recife", entries[0]["url"])
+		})
+	}
+}
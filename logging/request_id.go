@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader é o cabeçalho que identifica a requisição (recebido do cliente ou gerado pela API)
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limita o tamanho do identificador aceito do cliente
+const maxRequestIDLength = 128
+
+// requestIDKey é a chave do identificador da requisição no contexto
+type requestIDKey struct{}
+
+// WithRequestID retorna um contexto com o identificador da requisição
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext retorna o identificador da requisição ("" se ausente)
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID reaproveita o X-Request-ID recebido (se válido) ou gera um novo
+// O identificador é devolvido na resposta e fica no contexto da requisição para os logs e chamadas externas
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// isValidRequestID aceita identificadores curtos com caracteres seguros para logs e cabeçalhos
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID gera um identificador aleatório de 128 bits em hexadecimal
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,70 +1,421 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+	_ "time/tzdata" // embute a base de fusos horários (a imagem final é "scratch")
+	"weather-cep-api/auth"
+	"weather-cep-api/breaker"
+	"weather-cep-api/cache"
+	"weather-cep-api/cepstore"
+	"weather-cep-api/config"
 	"weather-cep-api/handlers"
+	"weather-cep-api/health"
+	"weather-cep-api/ibge"
+	"weather-cep-api/logging"
+	"weather-cep-api/metrics"
+	"weather-cep-api/models"
+	"weather-cep-api/ratelimit"
+	"weather-cep-api/server"
 	"weather-cep-api/services"
+	"weather-cep-api/tracing"
+	"weather-cep-api/warmup"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	// Carrega variáveis do arquivo .env (ignora erro se arquivo não existir)
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Aviso: Arquivo .env não encontrado ou erro ao carregar: %v", err)
-		log.Printf("Usando variáveis de ambiente do sistema")
-	} else {
-		log.Printf("Arquivo .env carregado com sucesso")
+	// Configuração: padrões < arquivo YAML/TOML (--config ou CONFIG_FILE) < .env < ambiente < flags
+	// Todos os valores são validados aqui; os erros são listados juntos
+	opts := config.ProcessOptions(os.Args[1:])
+	cfg, err := config.LoadWith(opts)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("Configuração inválida", err)
 	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fatal("Erro ao exibir a configuração", err)
+		}
+		return
+	}
+
+	// Logger estruturado (LOG_LEVEL e LOG_FORMAT); log.Printf também passa por ele
+	logger := logging.New(os.Stdout, cfg.Log)
+	slog.SetDefault(logger)
+	slog.Info("Configuração carregada", "file", cfg.File)
+	slog.Debug("Configuração efetiva", "settings", cfg.Settings())
+
+	// SIGTERM (Cloud Run/Kubernetes) e SIGINT iniciam o encerramento gracioso
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Recarga da configuração sem reiniciar (SIGHUP ou alteração do arquivo/.env a cada CONFIG_WATCH_INTERVAL)
+	// Só as configurações recarregáveis mudam; as demais exigem reinício e rejeitam a recarga
+	reloader := config.NewReloader(cfg, opts)
+
+	// Configura modo do Gin (GIN_MODE)
+	gin.SetMode(cfg.GinMode)
+
+	// Métricas do Prometheus (expostas em /metrics)
+	appMetrics := metrics.New()
 
-	// Configura modo do Gin baseado na variável de ambiente
-	if os.Getenv("GIN_MODE") == "release" {
-		gin.SetMode(gin.ReleaseMode)
+	// Tracing com OpenTelemetry (OTEL_TRACES_EXPORTER=otlp ou stdout; desativado por padrão)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, os.Stdout)
+	if err != nil {
+		fatal("Erro ao configurar tracing", err)
+	}
+	if cfg.Tracing.Enabled() {
+		slog.Info("Tracing ativado", "exporter", cfg.Tracing.Exporter, "service", cfg.Tracing.ServiceName)
 	}
 
 	// Cria instâncias dos serviços
-	cepService := services.NewCEPService()
-	weatherService := services.NewWeatherService()
+	// Cache de cada serviço (memory, bolt ou redis), configurado por CEP_CACHE_* e WEATHER_CACHE_*
+	cepCache := openCache("CEP", cfg.CEPCache)
+	weatherCache := openCache("WEATHER", cfg.WeatherCache)
+
+	// Os clients HTTP de cada provedor registram latência e falhas das chamadas externas
+	// e propagam o trace (traceparent) e o X-Request-ID da requisição
+	// O circuit breaker corta as chamadas a um provedor depois de falhas seguidas (CIRCUIT_BREAKER_*)
+	viaCEPClient := breaker.NewClient(metrics.ProviderViaCEP,
+		instrumentClient(logger, appMetrics, metrics.ProviderViaCEP, &http.Client{}), cfg.Breaker)
+	weatherAPIClient := breaker.NewClient(metrics.ProviderWeatherAPI,
+		instrumentClient(logger, appMetrics, metrics.ProviderWeatherAPI, &http.Client{}), cfg.Breaker)
+	breakers := []*breaker.Client{weatherAPIClient}
+
+	onlineCEPService := services.NewCEPServiceWithCache(viaCEPClient, cepCache, cfg.CEPCache.TTL)
+	var cepService services.CEPServiceInterface = onlineCEPService
+	weatherService := services.NewWeatherServiceWithBackend(weatherAPIClient, cfg.WeatherAPIKey, weatherCache, cfg.WeatherCache.TTL)
+	// Pool de chaves da WeatherAPI (WEATHER_API_KEY e WEATHER_API_KEYS): chaves recusadas ou sem cota
+	// ficam em quarentena e a consulta é repetida com as demais
+	weatherService.KeyPool().SetKeys(cfg.WeatherKeys())
+	weatherService.KeyPool().SetConfig(cfg.WeatherKeyPool)
+	appMetrics.RegisterKeyPool(metrics.ProviderWeatherAPI, weatherService.KeyPool())
+	if store := onlineCEPService.CacheStore(); store != nil {
+		appMetrics.RegisterCache("cep", store)
+	}
+	if store := weatherService.CacheStore(); store != nil {
+		appMetrics.RegisterCache("weather", store)
+	}
+
+	// Banco local de CEPs para ambientes sem acesso à ViaCEP (gerado por cmd/cep-import)
+	// CEPs ausentes no banco são consultados na ViaCEP, exceto com CEP_OFFLINE_FALLBACK=false
+	// O fallback é trocado na recarga de CEP_OFFLINE_FALLBACK
+	var offlineCEPService *services.OfflineCEPService
+	offlineFallback := func(cfg *config.Config) services.CEPServiceInterface {
+		if !cfg.CEPOfflineFallback {
+			return nil
+		}
+		return onlineCEPService
+	}
+	if path := cfg.CEPDatabase; path != "" {
+		store, err := cepstore.Open(path)
+		if err != nil {
+			fatal("Erro ao carregar banco local de CEPs", err)
+		}
+		offlineCEPService = services.NewOfflineCEPService(store, offlineFallback(cfg))
+		cepService = offlineCEPService
+		slog.Info("Banco local de CEPs carregado", "path", path, "ceps", store.Len(), "online_fallback", cfg.CEPOfflineFallback)
+	}
+	breakers = append(breakers, viaCEPClient)
+
+	// Tabela de municípios do IBGE que substitui a embutida (opcional)
+	if path := cfg.IBGEDataset; path != "" {
+		if err := ibge.LoadFile(path); err != nil {
+			fatal("Erro ao carregar tabela do IBGE", err)
+		}
+		slog.Info("Tabela do IBGE carregada", "path", path, "municipalities", ibge.Default().Len())
+	}
+
+	// Geocodificação reversa da rota de coordenadas (nominatim, local ou none)
+	// Os provedores são criados uma vez e trocados na recarga de REVERSE_GEOCODER
+	nominatimClient := breaker.NewClient(metrics.ProviderNominatim,
+		instrumentClient(logger, appMetrics, metrics.ProviderNominatim, services.NewNominatimHTTPClient()), cfg.Breaker)
+	breakers = append(breakers, nominatimClient)
+	// As localidades do Nominatim ficam no cache de CEPs (mesmo backend e TTL) e as consultas
+	// respeitam o limite de 1 por segundo da política de uso
+	nominatimGeocoder := services.NewNominatimGeocoderWithCache(nominatimClient, cepCache, cfg.CEPCache.TTL)
+	if store := nominatimGeocoder.CacheStore(); store != nil {
+		appMetrics.RegisterCache("geocoder", store)
+	}
+	localGeocoder := services.NewIBGEReverseGeocoder(ibge.Default(), 50)
+	selectGeocoder := func(cfg *config.Config) services.ReverseGeocoderInterface {
+		switch cfg.ReverseGeocoder {
+		case config.GeocoderNone:
+			return nil
+		case config.GeocoderLocal:
+			return localGeocoder
+		default:
+			return nominatimGeocoder
+		}
+	}
+	geocoder := services.NewSwitchableGeocoder(selectGeocoder(cfg))
 
 	// Cria instância do handler
-	weatherHandler := handlers.NewWeatherHandler(cepService, weatherService)
+	// Pré-aquecimento dos caches com os CEPs mais consultados (WARMUP_FILE e/ou WARMUP_STATS_FILE)
+	// Roda em segundo plano (limitado por WARMUP_TIMEOUT): /livez responde desde o início e /readyz
+	// só fica pronto quando ele termina
+	handlerCEPService := cepService
+	var warmer *warmup.Warmer
+	warmupCfg := cfg.Warmup
+	if warmupCfg.Enabled() {
+		stats := warmup.NewStats()
+		if warmupCfg.StatsFile != "" {
+			if stats, err = warmup.LoadStats(warmupCfg.StatsFile); err != nil {
+				fatal("Erro ao carregar estatísticas de acesso", err)
+			}
+			handlerCEPService = warmup.NewRecordingCEPService(cepService, stats)
+		}
+
+		warmer = warmup.NewWarmer(cepService, weatherService, stats, warmupCfg)
+		warmer.Start(ctx)
+	}
+
+	// Verificações de prontidão (/readyz): configuração, provedores, circuit breakers e caches
+	checker := newReadinessChecker(reloader, breakers)
+	if store := onlineCEPService.CacheStore(); store != nil {
+		checker.Add("cache:cep", store.Ping)
+	}
+	if store := weatherService.CacheStore(); store != nil {
+		checker.Add("cache:weather", store.Ping)
+	}
+	checker.Add("api_keys:"+metrics.ProviderWeatherAPI, weatherService.KeyPool().Check)
+	if warmer != nil {
+		checker.Add("warmup", health.Ready(warmer.Ready, "cache warmup in progress"))
+	}
+
+	// Spans das consultas de CEP e clima (filhos do span da requisição)
+	weatherHandler := handlers.NewWeatherHandlerWithGeocoder(
+		tracing.NewTracedCEPService(handlerCEPService), tracing.NewTracedWeatherService(weatherService), geocoder)
 
 	// Configura o router Gin
-	router := gin.Default()
+	// O log de acesso do Gin é substituído pelo estruturado, com o X-Request-ID de cada requisição
+	router := gin.New()
+	// O IP do cliente (log de acesso e limites por IP) só vem do X-Forwarded-For de TRUSTED_PROXIES
+	if err := handlers.TrustProxies(router, cfg.TrustedProxies, cfg.TrustedPlatform); err != nil {
+		fatal("Erro ao configurar proxies confiáveis", err)
+	}
+	router.Use(gin.Recovery())
+	router.Use(logging.RequestID())
+	router.Use(logging.AccessLog(logger))
+	router.Use(appMetrics.Middleware())
+	router.Use(tracing.Middleware())
+
+	// Adiciona middleware de CORS para permitir requisições das origens em CORS_ALLOWED_ORIGINS
+	router.Use(handlers.CORS(func() []string { return reloader.Current().CORSOrigins }))
 
-	// Adiciona middleware de CORS para permitir requisições de diferentes origens
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	// Autenticação dos clientes (API_KEY_AUTH e/ou JWT_*): chaves emitidas em /admin/keys e guardadas
+	// apenas como hash em API_KEYS_FILE, ou tokens JWT da plataforma; /health, as sondas e /metrics
+	// continuam abertos
+	keyStore, err := auth.NewKeyStore(cfg.Auth.KeysFile)
+	if err != nil {
+		fatal("Erro ao carregar chaves de clientes", err)
+	}
+	clientAuth := auth.Chain{keyStore}
+	if jwtCfg := cfg.Auth.JWT; jwtCfg.Enabled() {
+		// As chaves do JWKS ficam em cache por JWT_JWKS_CACHE_TTL; a prontidão espera a primeira busca
+		var jwks *auth.JWKS
+		if jwtCfg.JWKSURL != "" {
+			jwks = auth.NewJWKS(&http.Client{Timeout: 10 * time.Second}, jwtCfg.JWKSURL, jwtCfg.JWKSCacheTTL)
+			checker.Add("jwks", jwks.Check)
+		}
+		clientAuth = append(clientAuth, auth.NewJWTAuthenticator(jwtCfg, jwks))
+		slog.Info("Autenticação por JWT ativada", "issuer", jwtCfg.Issuer, "audience", jwtCfg.Audience, "jwks", jwtCfg.JWKSURL)
+	}
+	var apiMiddleware []gin.HandlerFunc
+	if cfg.Auth.Enabled() {
+		apiMiddleware = append(apiMiddleware, auth.Require(clientAuth, auth.ScopeTemperature))
+		slog.Info("Autenticação de clientes ativada", "keys", keyStore.Len(), "file", cfg.Auth.KeysFile)
+	}
+
+	// Limites por cliente (RATE_LIMIT_*), depois da autenticação para identificar a credencial; sem
+	// autenticação o cliente é o IP. O middleware fica sempre instalado para que a recarga possa ativá-los
+	limitStore, err := ratelimit.Open(cfg.RateLimit)
+	if err != nil {
+		fatal("Erro ao abrir o estado dos limites de requisições", err)
+	}
+	if redisStore, ok := limitStore.(*ratelimit.Redis); ok {
+		checker.Add("rate_limit", redisStore.Ping)
+	}
+	limiter := ratelimit.New(limitStore, cfg.RateLimit)
+	apiMiddleware = append(apiMiddleware, limiter.Middleware())
+	if cfg.RateLimit.Enabled() {
+		slog.Info("Limites de requisições ativados", "rps", cfg.RateLimit.Rate, "burst", cfg.RateLimit.Burst,
+			"daily_quota", cfg.RateLimit.DailyQuota, "monthly_quota", cfg.RateLimit.MonthlyQuota, "backend", cfg.RateLimit.Backend)
+	}
+
+	// Define as rotas (sem versão = alias da v1)
+	handlers.RegisterRoutes(router, weatherHandler, apiMiddleware...)
+	handlers.RegisterHealthRoutes(router, handlers.NewHealthHandler(checker))
+	router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	// Administração do cache, da configuração e das chaves de clientes (desativada sem ADMIN_TOKEN e
+	// sem autenticação de clientes), liberada pelo ADMIN_TOKEN ou por credencial com o escopo admin
+	adminEnabled := cfg.AdminToken != "" || cfg.Auth.Enabled()
+	if adminEnabled {
+		adminAuth := clientAuth
+		if cfg.AdminToken != "" {
+			adminAuth = append(auth.Chain{auth.NewStaticToken(cfg.AdminToken, auth.ScopeAdmin)}, adminAuth...)
 		}
+		adminGuard := auth.Require(adminAuth, auth.ScopeAdmin)
+		adminHandler := handlers.NewCacheAdminHandler(onlineCEPService.CacheStore(), weatherService.CacheStore())
+		handlers.RegisterAdminRoutes(router, adminHandler, adminGuard)
+		handlers.RegisterConfigRoutes(router, handlers.NewConfigHandler(func() models.ConfigResponse { return reloader.Current().Response() }), adminGuard)
+		handlers.RegisterAPIKeyRoutes(router, handlers.NewAPIKeyHandler(keyStore), adminGuard)
+	}
 
-		c.Next()
+	// A configuração recarregada chega aos serviços em execução: chaves da WeatherAPI, TTL dos caches e
+	// limites por cliente (cada troca é atômica; as requisições em andamento terminam com os valores anteriores)
+	reloader.OnReload(func(cfg *config.Config) {
+		limiter.SetConfig(cfg.RateLimit)
+		geocoder.Set(selectGeocoder(cfg))
+		if offlineCEPService != nil {
+			offlineCEPService.SetFallback(offlineFallback(cfg))
+		}
+		weatherService.KeyPool().SetKeys(cfg.WeatherKeys())
+		weatherService.KeyPool().SetConfig(cfg.WeatherKeyPool)
+		if store := onlineCEPService.CacheStore(); store != nil {
+			_ = store.SetTTL(cfg.CEPCache.TTL)
+		}
+		if store := nominatimGeocoder.CacheStore(); store != nil {
+			_ = store.SetTTL(cfg.CEPCache.TTL)
+		}
+		if store := weatherService.CacheStore(); store != nil {
+			_ = store.SetTTL(cfg.WeatherCache.TTL)
+		}
 	})
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	go reloader.Watch(ctx, cfg.WatchInterval, reload)
+
+	// Inicia o servidor
+	endpoints := []string{
+		"GET /health - Health check",
+		"GET /livez - Sonda de vida",
+		"GET /readyz - Sonda de prontidão (503 com o detalhamento quando não está pronta)",
+		"GET /metrics - Métricas no formato do Prometheus",
+		"GET /temperature/:cep - Consulta temperatura por CEP (alias de /v1)",
+		"GET /temperature/city/:uf/:city - Consulta temperatura por cidade/UF (alias de /v1)",
+		"GET /temperature/coords?lat=&lon= - Consulta temperatura por coordenadas (alias de /v1)",
+		"GET /temperature/ibge/:code - Consulta temperatura por código de município do IBGE (alias de /v1)",
+		"GET /comfort/:cep - Índices de conforto térmico por CEP (alias de /v1)",
+		"GET /v2/temperature/:cep - Temperatura com localização, observação e unidades",
+	}
+	if adminEnabled {
+		endpoints = append(endpoints,
+			"GET|DELETE /admin/cache/... - Administração do cache (escopo admin)",
+			"GET /admin/config - Configuração efetiva sem segredos (escopo admin)",
+			"GET|POST|DELETE /admin/keys/... - Gestão das chaves de clientes (escopo admin)")
+	}
+	slog.Info("Servidor iniciando", "port", cfg.Port, "endpoints", endpoints)
 
-	// Define as rotas
-	router.GET("/health", weatherHandler.HealthCheck)
-	router.GET("/temperature/:cep", weatherHandler.GetTemperatureByCEP)
+	// Servidor com encerramento gracioso (SHUTDOWN_*): a prontidão falha durante a drenagem,
+	// as requisições restantes são canceladas no fim do prazo e as rotinas abaixo rodam em ordem inversa
+	srv := server.New(":"+strconv.Itoa(cfg.Port), router, cfg.Shutdown)
+	checker.Add("shutdown", health.Ready(func() bool { return !srv.Draining() }, "server is shutting down"))
+	srv.OnShutdown("tracing", shutdownTracing)
+	closeCache := func(name string, backend cache.Cache) {
+		if backend != nil {
+			srv.OnShutdown("cache:"+name, func(context.Context) error { return backend.Close() })
+		}
+	}
+	closeCache("cep", cepCache)
+	closeCache("weather", weatherCache)
+	srv.OnShutdown("rate_limit", func(context.Context) error { return limitStore.Close() })
+	if warmer != nil {
+		srv.OnShutdown("warmup-stats", func(context.Context) error { return warmer.SaveStats() })
+	}
 
-	// Define a porta do servidor
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	if err := srv.ListenAndServe(ctx); err != nil {
+		fatal("Erro no servidor", err)
 	}
+}
 
-	// Inicia o servidor
-	log.Printf("Servidor iniciando na porta %s", port)
-	log.Printf("Endpoints disponíveis:")
-	log.Printf("  GET /health - Health check")
-	log.Printf("  GET /temperature/:cep - Consulta temperatura por CEP")
-	
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Erro ao iniciar servidor: %v", err)
-	}
-} 
\ No newline at end of file
+// fatal registra o erro e encerra o processo
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// instrumentClient envolve o client HTTP de um provedor com logs, métricas e tracing
+func instrumentClient(logger *slog.Logger, appMetrics *metrics.Metrics, provider string, client services.HTTPClientInterface) services.HTTPClientInterface {
+	return tracing.InstrumentClient(provider, logging.InstrumentClient(logger, provider, appMetrics.InstrumentClient(provider, client)))
+}
+
+// probeURLs são as URLs consultadas pelas verificações de prontidão de cada provedor
+// Qualquer resposta abaixo de 500 indica provedor acessível (a WeatherAPI é consultada sem chave,
+// portanto responde 401 sem consumir a cota)
+var probeURLs = map[string]string{
+	metrics.ProviderViaCEP:     "https://viacep.com.br/ws/01001000/json/",
+	metrics.ProviderWeatherAPI: "https://api.weatherapi.com/v1/current.json",
+	metrics.ProviderNominatim:  "https://nominatim.openstreetmap.org/status",
+}
+
+// newReadinessChecker monta as verificações de configuração e, para cada provedor em uso,
+// a de acessibilidade e a do circuit breaker
+// As chamadas de verificação usam um client próprio (fora das métricas e do circuit breaker) e
+// são feitas no máximo uma vez por READINESS_PROBE_INTERVAL
+func newReadinessChecker(reloader *config.Reloader, breakers []*breaker.Client) *health.Checker {
+	cfg := reloader.Current()
+	checker := health.NewChecker(cfg.Readiness.Timeout)
+	checker.Add("config", reloader.Check)
+
+	// O Nominatim exige User-Agent identificado; o mesmo client serve para os demais provedores
+	probeClient := services.NewNominatimHTTPClient()
+	probeClient.Timeout = cfg.Readiness.Timeout
+	for _, b := range breakers {
+		provider := b.Provider()
+		// Provedores desativados na recarga (REVERSE_GEOCODER, CEP_OFFLINE_FALLBACK) não afetam a prontidão
+		inUse := func() bool { return providerInUse(reloader.Current(), provider) }
+		checker.Add("upstream:"+provider, whenInUse(inUse,
+			health.Cached(health.HTTPCheck(probeClient, probeURLs[provider]), cfg.Readiness.ProbeInterval)))
+		checker.Add("circuit_breaker:"+provider, whenInUse(inUse, b.Check))
+	}
+	return checker
+}
+
+// providerInUse informa se a configuração atual consulta o provedor
+func providerInUse(cfg *config.Config, provider string) bool {
+	switch provider {
+	case metrics.ProviderViaCEP:
+		return cfg.CEPDatabase == "" || cfg.CEPOfflineFallback
+	case metrics.ProviderNominatim:
+		return cfg.ReverseGeocoder == config.GeocoderNominatim
+	}
+	return true
+}
+
+// whenInUse só executa a verificação enquanto o provedor estiver em uso
+func whenInUse(inUse func() bool, check health.CheckFunc) health.CheckFunc {
+	return func(ctx context.Context) error {
+		if !inUse() {
+			return nil
+		}
+		return check(ctx)
+	}
+}
+
+// openCache abre o backend de cache do serviço descrito pelas configurações <prefix>_CACHE_*
+func openCache(prefix string, cfg cache.Config) cache.Cache {
+	backend, err := cache.Open(cfg)
+	if err != nil {
+		fatal("Erro ao abrir cache "+string(cfg.Backend), err)
+	}
+	if backend == nil {
+		slog.Info("Cache desativado", "cache", prefix)
+	} else {
+		slog.Info("Cache configurado", "cache", prefix, "backend", cfg.Backend, "ttl", cfg.TTL)
+	}
+	return backend
+}
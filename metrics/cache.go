@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"weather-cep-api/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheStatsSource é implementado pelos caches tipados (cache.Store)
+type CacheStatsSource interface {
+	Stats() (cache.Stats, error)
+}
+
+// cacheCollector lê as estatísticas dos caches a cada coleta
+type cacheCollector struct {
+	mu      sync.RWMutex
+	sources map[string]CacheStatsSource
+
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+	hitRatio *prometheus.Desc
+	entries  *prometheus.Desc
+	errors   *prometheus.Desc
+}
+
+// newCacheCollector cria o coletor sem caches registrados
+func newCacheCollector() *cacheCollector {
+	labels := []string{"cache"}
+	return &cacheCollector{
+		sources:  make(map[string]CacheStatsSource),
+		hits:     prometheus.NewDesc(namespace+"_cache_hits_total", "Leituras atendidas pelo cache.", labels, nil),
+		misses:   prometheus.NewDesc(namespace+"_cache_misses_total", "Leituras não encontradas no cache.", labels, nil),
+		hitRatio: prometheus.NewDesc(namespace+"_cache_hit_ratio", "Proporção de leituras atendidas pelo cache desde a inicialização.", labels, nil),
+		entries:  prometheus.NewDesc(namespace+"_cache_entries", "Entradas válidas no cache.", labels, nil),
+		errors:   prometheus.NewDesc(namespace+"_cache_errors_total", "Erros de acesso ao backend do cache.", labels, nil),
+	}
+}
+
+// RegisterCache passa a expor as estatísticas de um cache
+func (m *Metrics) RegisterCache(name string, source CacheStatsSource) {
+	m.caches.mu.Lock()
+	defer m.caches.mu.Unlock()
+	m.caches.sources[name] = source
+}
+
+// Describe envia as descrições das métricas de cache
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.hitRatio
+	ch <- c.entries
+	ch <- c.errors
+}
+
+// Collect lê as estatísticas de cada cache registrado
+// Se a contagem de entradas falhar (ex: Redis indisponível), os contadores continuam sendo expostos
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.sources))
+	sources := make(map[string]CacheStatsSource, len(c.sources))
+	for name, source := range c.sources {
+		names = append(names, name)
+		sources[name] = source
+	}
+	c.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		stats, err := sources[name].Stats()
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), name)
+		ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, stats.HitRatio, name)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(stats.Errors), name)
+		if err != nil {
+			slog.Warn("Erro ao coletar estatísticas do cache", "cache", name, "error", err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries), name)
+	}
+}
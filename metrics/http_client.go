@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"weather-cep-api/services"
+)
+
+// Nomes dos provedores usados nos rótulos das métricas de chamadas externas
+const (
+	ProviderViaCEP     = "viacep"
+	ProviderWeatherAPI = "weatherapi"
+	ProviderNominatim  = "nominatim"
+)
+
+// InstrumentedClient é o decorator de HTTPClientInterface que mede as chamadas a um provedor
+type InstrumentedClient struct {
+	provider string
+	inner    services.HTTPClientInterface
+	metrics  *Metrics
+}
+
+// InstrumentClient envolve o client HTTP de um provedor com as métricas de latência e erros
+func (m *Metrics) InstrumentClient(provider string, inner services.HTTPClientInterface) *InstrumentedClient {
+	return &InstrumentedClient{
+		provider: provider,
+		inner:    inner,
+		metrics:  m,
+	}
+}
+
+// Do executa a requisição e registra a latência, o status e as falhas
+func (c *InstrumentedClient) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.inner.Do(req)
+	c.metrics.upstreamDuration.WithLabelValues(c.provider).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		c.metrics.upstreamRequests.WithLabelValues(c.provider, "error").Inc()
+		c.metrics.upstreamErrors.WithLabelValues(c.provider, "network").Inc()
+		return resp, err
+	}
+
+	c.metrics.upstreamRequests.WithLabelValues(c.provider, strconv.Itoa(resp.StatusCode)).Inc()
+	switch {
+	case resp.StatusCode >= 500:
+		c.metrics.upstreamErrors.WithLabelValues(c.provider, "http_5xx").Inc()
+	case resp.StatusCode >= 400:
+		c.metrics.upstreamErrors.WithLabelValues(c.provider, "http_4xx").Inc()
+	}
+	return resp, nil
+}
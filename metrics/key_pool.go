@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"weather-cep-api/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KeyPoolStatsSource é implementado pelo pool de chaves dos provedores (services.KeyPool)
+type KeyPoolStatsSource interface {
+	Stats() []services.KeyStats
+}
+
+// keyOutcomes são os resultados sempre expostos, mesmo antes da primeira chamada com a chave
+var keyOutcomes = []services.KeyOutcome{services.KeyOK, services.KeyFailed, services.KeyRejected, services.KeyQuotaExceeded}
+
+// keyPoolCollector lê as estatísticas das chaves a cada coleta
+// As chaves são identificadas pelo início do SHA-256 (rótulo key_id), nunca pelo valor
+type keyPoolCollector struct {
+	mu      sync.RWMutex
+	sources map[string]KeyPoolStatsSource
+
+	requests     *prometheus.Desc
+	monthlyUsage *prometheus.Desc
+	available    *prometheus.Desc
+}
+
+// newKeyPoolCollector cria o coletor sem pools registrados
+func newKeyPoolCollector() *keyPoolCollector {
+	labels := []string{"provider", "key_id"}
+	return &keyPoolCollector{
+		sources:      make(map[string]KeyPoolStatsSource),
+		requests:     prometheus.NewDesc(namespace+"_api_key_requests_total", "Chamadas feitas com cada chave, por resultado (ok, error, rejected, quota_exceeded).", append(labels, "outcome"), nil),
+		monthlyUsage: prometheus.NewDesc(namespace+"_api_key_monthly_usage", "Chamadas feitas com cada chave no mês corrente (UTC).", labels, nil),
+		available:    prometheus.NewDesc(namespace+"_api_key_available", "1 quando a chave pode ser usada, 0 em quarentena ou sem cota.", labels, nil),
+	}
+}
+
+// RegisterKeyPool passa a expor o uso das chaves de um provedor
+func (m *Metrics) RegisterKeyPool(provider string, source KeyPoolStatsSource) {
+	m.keyPools.mu.Lock()
+	defer m.keyPools.mu.Unlock()
+	m.keyPools.sources[provider] = source
+}
+
+// Describe envia as descrições das métricas das chaves
+func (c *keyPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.monthlyUsage
+	ch <- c.available
+}
+
+// Collect lê as estatísticas de cada pool registrado
+func (c *keyPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	providers := make([]string, 0, len(c.sources))
+	sources := make(map[string]KeyPoolStatsSource, len(c.sources))
+	for provider, source := range c.sources {
+		providers = append(providers, provider)
+		sources[provider] = source
+	}
+	c.mu.RUnlock()
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		for _, key := range sources[provider].Stats() {
+			for _, outcome := range keyOutcomes {
+				ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(key.Requests[outcome]), provider, key.ID, string(outcome))
+			}
+			ch <- prometheus.MustNewConstMetric(c.monthlyUsage, prometheus.GaugeValue, float64(key.MonthlyUsage), provider, key.ID)
+			available := 0.0
+			if key.Available {
+				available = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.available, prometheus.GaugeValue, available, provider, key.ID)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-cep-api/cache"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHTTPClient é um mock do HTTPClientInterface
+type MockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+// scrape retorna o texto exposto em /metrics
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New()
+	router := gin.New()
+	router.Use(m.Middleware())
+
+	var inFlight float64
+	router.GET("/temperature/:cep", func(c *gin.Context) {
+		inFlight = testutil.ToFloat64(m.inFlight)
+		if c.Param("cep") == "00000000" {
+			c.String(http.StatusUnprocessableEntity, "invalid zipcode")
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	for _, path := range []string{"/temperature/01310100", "/temperature/20040020", "/temperature/00000000", "/unknown"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	}
+
+	// As requisições são agrupadas pelo padrão da rota, não pelo CEP
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/temperature/:cep", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/temperature/:cep", "422")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", unmatchedRoute, "404")))
+	assert.Equal(t, 1.0, inFlight)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight))
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `weather_cep_http_request_duration_seconds_count{method="GET",route="/temperature/:cep",status="200"} 2`)
+	assert.NotContains(t, body, "01310100")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestInstrumentedClient_Do(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       *http.Response
+		err            error
+		expectedStatus string
+		expectedReason string
+	}{
+		{
+			name:           "Success",
+			response:       &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))},
+			expectedStatus: "200",
+		},
+		{
+			name:           "Client error",
+			response:       &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(""))},
+			expectedStatus: "403",
+			expectedReason: "http_4xx",
+		},
+		{
+			name:           "Server error",
+			response:       &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader(""))},
+			expectedStatus: "502",
+			expectedReason: "http_5xx",
+		},
+		{
+			name:           "Network error",
+			err:            errors.New("connection refused"),
+			expectedStatus: "error",
+			expectedReason: "network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			mockClient := new(MockHTTPClient)
+			mockClient.On("Do", "https://api.weatherapi.com/v1/current.json?key=secret").Return(tt.response, tt.err)
+			client := m.InstrumentClient(ProviderWeatherAPI, mockClient)
+
+			resp, err := client.Do(httptest.NewRequest("GET", "https://api.weatherapi.com/v1/current.json?key=secret", nil))
+
+			assert.Equal(t, tt.response, resp)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, 1.0, testutil.ToFloat64(m.upstreamRequests.WithLabelValues(ProviderWeatherAPI, tt.expectedStatus)))
+			if tt.expectedReason != "" {
+				assert.Equal(t, 1.0, testutil.ToFloat64(m.upstreamErrors.WithLabelValues(ProviderWeatherAPI, tt.expectedReason)))
+			} else {
+				assert.Equal(t, 0, testutil.CollectAndCount(m.upstreamErrors))
+			}
+
+			body := scrape(t, m)
+			assert.Contains(t, body, `weather_cep_upstream_request_duration_seconds_count{provider="weatherapi"} 1`)
+			// A URL (com a chave da API) nunca vira rótulo
+			assert.NotContains(t, body, "secret")
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// failingStats simula um cache cujo backend está indisponível
+type failingStats struct{}
+
+func (failingStats) Stats() (cache.Stats, error) {
+	return cache.Stats{Hits: 3, Misses: 1, HitRatio: 0.75, Errors: 2}, errors.New("redis unavailable")
+}
+
+func TestMetrics_RegisterCache(t *testing.T) {
+	m := New()
+	store := cache.NewStore[models.LocationInfo](cache.NewMemory(), "cep:v1:", time.Hour)
+	require.NoError(t, store.Set("01310100", &models.LocationInfo{City: "São Paulo", State: "SP"}))
+	_, _, _ = store.Get("01310100")
+	_, _, _ = store.Get("01310100")
+	_, _, _ = store.Get("01310100")
+	_, _, _ = store.Get("20040020")
+
+	m.RegisterCache("cep", store)
+	m.RegisterCache("weather", failingStats{})
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `weather_cep_cache_hits_total{cache="cep"} 3`)
+	assert.Contains(t, body, `weather_cep_cache_misses_total{cache="cep"} 1`)
+	assert.Contains(t, body, `weather_cep_cache_hit_ratio{cache="cep"} 0.75`)
+	assert.Contains(t, body, `weather_cep_cache_entries{cache="cep"} 1`)
+
+	// Sem a contagem de entradas, os contadores continuam expostos
+	assert.Contains(t, body, `weather_cep_cache_hits_total{cache="weather"} 3`)
+	assert.Contains(t, body, `weather_cep_cache_errors_total{cache="weather"} 2`)
+	assert.NotContains(t, body, `weather_cep_cache_entries{cache="weather"}`)
+}
+
+func TestMetrics_RegisterKeyPool(t *testing.T) {
+	m := New()
+	pool := services.NewKeyPool([]string{"primary-key", "backup-key"}, services.DefaultKeyPoolConfig())
+	key, err := pool.Acquire(nil)
+	require.NoError(t, err)
+	pool.Report(key, services.KeyOK)
+	key, err = pool.Acquire(nil)
+	require.NoError(t, err)
+	pool.Report(key, services.KeyRejected)
+
+	m.RegisterKeyPool(ProviderWeatherAPI, pool)
+
+	stats := pool.Stats()
+	body := scrape(t, m)
+	assert.Contains(t, body, `weather_cep_api_key_requests_total{key_id="`+stats[0].ID+`",outcome="ok",provider="weatherapi"} 1`)
+	assert.Contains(t, body, `weather_cep_api_key_requests_total{key_id="`+stats[1].ID+`",outcome="rejected",provider="weatherapi"} 1`)
+	assert.Contains(t, body, `weather_cep_api_key_requests_total{key_id="`+stats[1].ID+`",outcome="quota_exceeded",provider="weatherapi"} 0`)
+	assert.Contains(t, body, `weather_cep_api_key_monthly_usage{key_id="`+stats[0].ID+`",provider="weatherapi"} 1`)
+	assert.Contains(t, body, `weather_cep_api_key_available{key_id="`+stats[0].ID+`",provider="weatherapi"} 1`)
+	assert.Contains(t, body, `weather_cep_api_key_available{key_id="`+stats[1].ID+`",provider="weatherapi"} 0`)
+	// As chaves nunca aparecem nas métricas
+	assert.NotContains(t, body, "primary-key")
+	assert.NotContains(t, body, "backup-key")
+}
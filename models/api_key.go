@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// APIKey descreve uma chave de cliente emitida (sem o valor da chave)
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKeyRequest é o corpo da emissão de uma chave
+type APIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// APIKeyCreatedResponse traz a chave emitida; o valor (Key) não é exibido novamente
+type APIKeyCreatedResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
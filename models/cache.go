@@ -0,0 +1,40 @@
+package models
+
+// CacheStats descreve o estado de um cache na administração
+type CacheStats struct {
+	Enabled   bool    `json:"enabled"`
+	Namespace string  `json:"namespace,omitempty"`
+	TTL       string  `json:"ttl,omitempty"`
+	Entries   int     `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	HitRatio  float64 `json:"hit_ratio"`
+	Writes    int64   `json:"writes"`
+	Deletes   int64   `json:"deletes"`
+	Errors    int64   `json:"errors"`
+	// Error informa a falha ao contar as entradas (ex: Redis indisponível)
+	Error string `json:"error,omitempty"`
+}
+
+// CacheStatsResponse reúne as estatísticas dos caches de CEP e clima
+type CacheStatsResponse struct {
+	CEP     CacheStats `json:"cep"`
+	Weather CacheStats `json:"weather"`
+}
+
+// CacheEntryResponse representa uma entrada consultada na administração do cache
+type CacheEntryResponse struct {
+	Cache string      `json:"cache"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// CachePurgeResponse informa quantas entradas foram removidas de cada cache
+type CachePurgeResponse struct {
+	Removed map[string]int `json:"removed"`
+}
+
+// CacheTTLRequest é o corpo da alteração de TTL (duração Go, ex: "10m")
+type CacheTTLRequest struct {
+	TTL string `json:"ttl"`
+}
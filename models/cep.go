@@ -20,4 +20,7 @@ type LocationInfo struct {
 	City  string `json:"city"`
 	State string `json:"state"`
 	CEP   string `json:"cep"`
+	IBGE  string `json:"ibge,omitempty"`
+	// UFMismatch indica que a UF informada pelo provedor não corresponde à faixa do CEP
+	UFMismatch bool `json:"uf_mismatch,omitempty"`
 } 
\ No newline at end of file
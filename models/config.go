@@ -0,0 +1,16 @@
+package models
+
+// ConfigSetting descreve o valor efetivo de uma configuração e de onde ele veio
+type ConfigSetting struct {
+	Key string `json:"key"`
+	// Value vem com os segredos removidos (chaves, tokens e senhas de URLs)
+	Value string `json:"value"`
+	// Source é default, file, .env, secret-file, vault, encrypted-file, env ou flag
+	Source string `json:"source"`
+}
+
+// ConfigResponse representa a configuração efetiva exibida na administração
+type ConfigResponse struct {
+	File     string          `json:"file,omitempty"`
+	Settings []ConfigSetting `json:"settings"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// CoordinatesTemperatureResponse representa a temperatura consultada por coordenadas
+// Location só é preenchido quando a geocodificação reversa encontra a localidade
+type CoordinatesTemperatureResponse struct {
+	TemperatureResponse
+	Location *LocationInfo `json:"location,omitempty"`
+}
+
+// NominatimReverseResponse representa a resposta de geocodificação reversa do Nominatim (OpenStreetMap)
+type NominatimReverseResponse struct {
+	Address struct {
+		City         string `json:"city"`
+		Town         string `json:"town"`
+		Village      string `json:"village"`
+		Municipality string `json:"municipality"`
+		State        string `json:"state"`
+		StateCode    string `json:"ISO3166-2-lvl4"`
+		Postcode     string `json:"postcode"`
+		CountryCode  string `json:"country_code"`
+	} `json:"address"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// HealthCheckResult descreve o resultado de uma verificação de prontidão
+type HealthCheckResult struct {
+	Status string `json:"status"`
+	// Error informa o motivo da falha (ex: "WEATHER_API_KEY is not set")
+	Error string `json:"error,omitempty"`
+	// CheckedAt é quando a verificação rodou (verificações de provedores são reaproveitadas por um intervalo)
+	CheckedAt string `json:"checked_at"`
+	Duration  string `json:"duration"`
+}
+
+// ReadinessResponse representa a resposta de /readyz com o detalhamento de cada verificação
+type ReadinessResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+// LivenessResponse representa a resposta de /livez
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
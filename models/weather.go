@@ -1,18 +1,51 @@
 package models
 
+import "time"
+
 // WeatherAPIResponse representa a resposta da API WeatherAPI
 type WeatherAPIResponse struct {
 	Location struct {
 		Name    string `json:"name"`
 		Region  string `json:"region"`
 		Country string `json:"country"`
+		TzID    string `json:"tz_id"`
 	} `json:"location"`
 	Current struct {
-		TempC float64 `json:"temp_c"`
-		TempF float64 `json:"temp_f"`
+		LastUpdatedEpoch int64   `json:"last_updated_epoch"`
+		TempC            float64 `json:"temp_c"`
+		TempF            float64 `json:"temp_f"`
+		Humidity         float64 `json:"humidity"`
+		WindKph          float64 `json:"wind_kph"`
 	} `json:"current"`
 }
 
+// WeatherAPIError representa o corpo das respostas de erro da WeatherAPI
+type WeatherAPIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// WeatherConditions representa as condições atuais retornadas pelo provedor de clima
+type WeatherConditions struct {
+	TempC       float64   `json:"temp_C"`
+	Humidity    float64   `json:"humidity"`
+	WindKph     float64   `json:"wind_kph"`
+	ObservedAt  time.Time `json:"observed_at"`
+	TimeZone    string    `json:"timezone,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	Provider    string    `json:"provider"`
+	CacheStatus string    `json:"cache"`
+}
+
+// Status de cache reportados junto com as condições de clima
+const (
+	CacheStatusHit    = "hit"
+	CacheStatusMiss   = "miss"
+	CacheStatusBypass = "bypass"
+)
+
 // TemperatureResponse representa a resposta final da API
 type TemperatureResponse struct {
 	TempC float64 `json:"temp_C"`
@@ -20,6 +53,50 @@ type TemperatureResponse struct {
 	TempK float64 `json:"temp_K"`
 }
 
+// ScaledTemperatureResponse representa a resposta com escalas escolhidas pelo cliente (?scales=)
+type ScaledTemperatureResponse struct {
+	Mode         string             `json:"mode"`
+	Temperatures map[string]float64 `json:"temperatures"`
+}
+
+// UnitsInfo descreve as unidades usadas numa resposta da v2
+type UnitsInfo struct {
+	Mode   string            `json:"mode"`
+	Scales map[string]string `json:"scales"`
+}
+
+// TemperatureResponseV2 representa a resposta de temperatura da API v2
+type TemperatureResponseV2 struct {
+	Location     LocationInfo       `json:"location"`
+	Temperatures map[string]float64 `json:"temperatures"`
+	Units        UnitsInfo          `json:"units"`
+	ObservedAt   *time.Time         `json:"observed_at,omitempty"`
+	Freshness    FreshnessInfo      `json:"freshness"`
+	Provider     string             `json:"provider"`
+	Cache        string             `json:"cache"`
+}
+
+// FreshnessInfo descreve quando a leitura foi feita e quão recente ela é
+type FreshnessInfo struct {
+	ObservedAtLocal string `json:"observed_at_local,omitempty"`
+	TimeZone        string `json:"timezone,omitempty"`
+	AgeSeconds      int64  `json:"age_seconds"`
+	Cached          bool   `json:"cached"`
+}
+
+// ComfortResponse representa os índices de conforto térmico de uma localidade
+type ComfortResponse struct {
+	TempC      float64 `json:"temp_C"`
+	Humidity   float64 `json:"humidity"`
+	WindKph    float64 `json:"wind_kph"`
+	HeatIndexC float64 `json:"heat_index_C"`
+	WindChillC float64 `json:"wind_chill_C"`
+	DewPointC  float64 `json:"dew_point_C"`
+	Humidex    float64 `json:"humidex"`
+	WBGTC      float64 `json:"wbgt_C"`
+	Risk       string  `json:"risk"`
+}
+
 // ErrorResponse representa uma resposta de erro
 type ErrorResponse struct {
 	Message string `json:"message"`
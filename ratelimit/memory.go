@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval é o intervalo mínimo entre as limpezas dos clientes inativos
+const memorySweepInterval = time.Minute
+
+// clientState é o estado de um cliente no store em memória
+type clientState struct {
+	tokens  float64
+	updated time.Time
+	day     string
+	daily   int64
+	month   string
+	monthly int64
+}
+
+// Memory guarda o estado dos clientes no processo (cada réplica limita de forma independente)
+type Memory struct {
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastSweep time.Time
+}
+
+// NewMemory cria o store em memória
+func NewMemory() *Memory {
+	return &Memory{clients: make(map[string]*clientState)}
+}
+
+// Take consome um token do balde e uma unidade de cada cota do cliente, se houver
+func (m *Memory) Take(_ context.Context, client string, cfg Config, now time.Time) (Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= memorySweepInterval {
+		m.sweep(cfg, now)
+	}
+
+	burst := float64(cfg.burst())
+	state, ok := m.clients[client]
+	if !ok {
+		state = &clientState{tokens: burst, updated: now}
+		m.clients[client] = state
+	}
+	if elapsed := now.Sub(state.updated).Seconds(); elapsed > 0 {
+		state.tokens = math.Min(burst, state.tokens+elapsed*cfg.Rate)
+	}
+	state.tokens = math.Min(burst, state.tokens)
+	state.updated = now
+	if day := dayKey(now); state.day != day {
+		state.day, state.daily = day, 0
+	}
+	if month := monthKey(now); state.month != month {
+		state.month, state.monthly = month, 0
+	}
+
+	usage := Usage{Reason: decide(cfg, state.tokens, state.daily, state.monthly)}
+	if usage.Reason == "" {
+		if cfg.Rate > 0 {
+			state.tokens--
+		}
+		// Sem cota os contadores não são mantidos (uma cota ativada na recarga começa do zero)
+		if cfg.DailyQuota > 0 {
+			state.daily++
+		}
+		if cfg.MonthlyQuota > 0 {
+			state.monthly++
+		}
+	}
+	usage.Tokens, usage.Daily, usage.Monthly = state.tokens, state.daily, state.monthly
+	return usage, nil
+}
+
+// Close não tem recursos a liberar
+func (m *Memory) Close() error {
+	return nil
+}
+
+// sweep remove os clientes com o balde cheio e sem requisições contadas no dia e no mês
+// (chamado com o lock)
+func (m *Memory) sweep(cfg Config, now time.Time) {
+	m.lastSweep = now
+	burst := float64(cfg.burst())
+	day, month := dayKey(now), monthKey(now)
+	for client, state := range m.clients {
+		full := cfg.Rate <= 0 || state.tokens+now.Sub(state.updated).Seconds()*cfg.Rate >= burst
+		counted := (state.day == day && state.daily > 0) || (state.month == month && state.monthly > 0)
+		if full && !counted {
+			delete(m.clients, client)
+		}
+	}
+}
+
+// decide retorna o motivo da recusa ("" quando há token no balde e saldo nas cotas)
+func decide(cfg Config, tokens float64, daily, monthly int64) string {
+	switch {
+	case cfg.Rate > 0 && tokens < 1:
+		return ReasonRate
+	case cfg.DailyQuota > 0 && daily >= cfg.DailyQuota:
+		return ReasonDailyQuota
+	case cfg.MonthlyQuota > 0 && monthly >= cfg.MonthlyQuota:
+		return ReasonMonthlyQuota
+	}
+	return ""
+}
+
+// dayKey e monthKey identificam o dia e o mês (UTC) das cotas
+func dayKey(now time.Time) string {
+	return now.UTC().Format("20060102")
+}
+
+func monthKey(now time.Time) string {
+	return now.UTC().Format("200601")
+}
@@ -0,0 +1,333 @@
+// Package ratelimit limita as consultas de cada cliente para que nenhum deles esgote a cota dos
+// provedores
+//
+// Cada cliente (a chave ou o token autenticado; sem autenticação, o IP) tem um balde de tokens
+// (RATE_LIMIT_RPS por segundo, até RATE_LIMIT_BURST acumulados) e, opcionalmente, cotas diária e
+// mensal (dias e meses em UTC). As respostas trazem os cabeçalhos RateLimit-* e, quando o limite é
+// atingido, 429 com Retry-After. O estado fica em memória ou no Redis, compartilhado entre réplicas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"weather-cep-api/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Backend identifica onde fica o estado dos limites
+type Backend string
+
+const (
+	BackendMemory Backend = "memory"
+	BackendRedis  Backend = "redis"
+)
+
+// Motivos da recusa de uma requisição
+const (
+	ReasonRate         = "rate"
+	ReasonDailyQuota   = "daily_quota"
+	ReasonMonthlyQuota = "monthly_quota"
+)
+
+// Config descreve os limites de cada cliente
+type Config struct {
+	// Rate é a taxa de reposição do balde, em requisições por segundo (0 desativa o balde)
+	Rate float64
+	// Burst é a capacidade do balde (0 usa a taxa arredondada para cima)
+	Burst int
+	// DailyQuota e MonthlyQuota limitam as requisições por dia e por mês (0 sem limite)
+	DailyQuota   int64
+	MonthlyQuota int64
+	Backend      Backend
+	RedisURL     string
+}
+
+// DefaultConfig retorna a configuração padrão: sem limites, estado em memória
+func DefaultConfig() Config {
+	return Config{Backend: BackendMemory}
+}
+
+// Enabled informa se algum limite está ativo
+func (c Config) Enabled() bool {
+	return c.Rate > 0 || c.DailyQuota > 0 || c.MonthlyQuota > 0
+}
+
+// burst retorna a capacidade efetiva do balde
+func (c Config) burst() int {
+	if c.Burst > 0 {
+		return c.Burst
+	}
+	return max(1, int(math.Ceil(c.Rate)))
+}
+
+// ConfigFrom lê RATE_LIMIT_RPS, RATE_LIMIT_BURST, RATE_LIMIT_DAILY_QUOTA, RATE_LIMIT_MONTHLY_QUOTA,
+// RATE_LIMIT_BACKEND (memory ou redis) e RATE_LIMIT_REDIS_URL (padrão: REDIS_URL)
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	cfg.RedisURL = getenv("REDIS_URL")
+
+	if raw := strings.TrimSpace(getenv("RATE_LIMIT_RPS")); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return cfg, fmt.Errorf("invalid RATE_LIMIT_RPS: %q", raw)
+		}
+		cfg.Rate = rate
+	}
+	if raw := strings.TrimSpace(getenv("RATE_LIMIT_BURST")); raw != "" {
+		burst, err := strconv.Atoi(raw)
+		if err != nil || burst < 0 {
+			return cfg, fmt.Errorf("invalid RATE_LIMIT_BURST: %q", raw)
+		}
+		cfg.Burst = burst
+	}
+	for name, target := range map[string]*int64{
+		"RATE_LIMIT_DAILY_QUOTA":   &cfg.DailyQuota,
+		"RATE_LIMIT_MONTHLY_QUOTA": &cfg.MonthlyQuota,
+	} {
+		raw := strings.TrimSpace(getenv(name))
+		if raw == "" {
+			continue
+		}
+		quota, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || quota < 0 {
+			return cfg, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		*target = quota
+	}
+	if raw := strings.TrimSpace(getenv("RATE_LIMIT_REDIS_URL")); raw != "" {
+		cfg.RedisURL = raw
+	}
+	if raw := strings.TrimSpace(getenv("RATE_LIMIT_BACKEND")); raw != "" {
+		cfg.Backend = Backend(strings.ToLower(raw))
+	}
+
+	switch cfg.Backend {
+	case BackendMemory:
+	case BackendRedis:
+		if cfg.RedisURL == "" {
+			return cfg, fmt.Errorf("RATE_LIMIT_BACKEND=redis requires RATE_LIMIT_REDIS_URL or REDIS_URL")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid RATE_LIMIT_BACKEND: %q (use memory or redis)", cfg.Backend)
+	}
+	return cfg, nil
+}
+
+// Usage é o estado do cliente depois de uma requisição, devolvido pelo Store
+type Usage struct {
+	// Reason é o motivo da recusa ("" quando a requisição foi aceita e consumida)
+	Reason string
+	// Tokens restantes no balde
+	Tokens float64
+	// Daily e Monthly são as requisições aceitas no dia e no mês
+	Daily   int64
+	Monthly int64
+}
+
+// Store guarda o estado dos clientes
+// Take decide e consome de forma atômica: uma requisição recusada não consome o balde nem as cotas
+type Store interface {
+	Take(ctx context.Context, client string, cfg Config, now time.Time) (Usage, error)
+	Close() error
+}
+
+// Open cria o store descrito pela configuração
+func Open(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case BackendRedis:
+		return NewRedis(cfg.RedisURL)
+	case BackendMemory, "":
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("invalid rate limit backend: %q", cfg.Backend)
+}
+
+// Result é a decisão sobre uma requisição, com os valores dos cabeçalhos RateLimit-*
+// Limit, Remaining e Reset descrevem o limite mais próximo de se esgotar (ou o que recusou a requisição)
+type Result struct {
+	Allowed    bool
+	Reason     string
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter aplica os limites aos clientes
+type Limiter struct {
+	store Store
+	cfg   atomic.Pointer[Config]
+	now   func() time.Time
+}
+
+// New cria o limitador sobre o store
+func New(store Store, cfg Config) *Limiter {
+	return NewWithClock(store, cfg, time.Now)
+}
+
+// NewWithClock cria o limitador com o relógio informado (usado nos testes)
+func NewWithClock(store Store, cfg Config, now func() time.Time) *Limiter {
+	l := &Limiter{store: store, now: now}
+	l.cfg.Store(&cfg)
+	return l
+}
+
+// SetConfig troca os limites (recarga da configuração); o estado dos clientes é mantido
+func (l *Limiter) SetConfig(cfg Config) {
+	l.cfg.Store(&cfg)
+}
+
+// Config retorna os limites em vigor
+func (l *Limiter) Config() Config {
+	return *l.cfg.Load()
+}
+
+// Allow registra uma requisição do cliente e informa se ela pode seguir
+func (l *Limiter) Allow(ctx context.Context, client string) (Result, error) {
+	cfg := l.Config()
+	now := l.now()
+	usage, err := l.store.Take(ctx, client, cfg, now)
+	if err != nil {
+		return Result{Allowed: true}, err
+	}
+	return cfg.result(usage, now), nil
+}
+
+// Middleware aplica os limites às rotas; deve vir depois da autenticação para identificar o cliente
+// Falhas do store não bloqueiam as consultas (a requisição segue e o erro é registrado)
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := l.Config()
+		if !cfg.Enabled() {
+			c.Next()
+			return
+		}
+
+		client := ClientKey(c)
+		result, err := l.Allow(c.Request.Context(), client)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Erro ao verificar limite de requisições", "client", client, "error", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Policy", cfg.policy())
+		header.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		header.Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		header.Set("RateLimit-Reset", strconv.FormatInt(seconds(result.Reset), 10))
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.FormatInt(max(1, seconds(result.RetryAfter)), 10))
+			slog.DebugContext(c.Request.Context(), "Limite de requisições atingido", "client", client, "reason", result.Reason)
+			c.String(http.StatusTooManyRequests, strings.ReplaceAll(result.Reason, "_", " ")+" limit exceeded")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ClientKey identifica o cliente: a credencial autenticada ou, sem autenticação, o IP
+func ClientKey(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		return principal.Method + ":" + principal.ID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// window é um dos limites aplicados, no formato dos cabeçalhos
+type window struct {
+	reason    string
+	limit     int64
+	remaining int64
+	reset     time.Duration
+}
+
+// result converte o estado do cliente na decisão e nos valores dos cabeçalhos
+func (c Config) result(usage Usage, now time.Time) Result {
+	var windows []window
+	if c.Rate > 0 {
+		burst := c.burst()
+		windows = append(windows, window{
+			reason:    ReasonRate,
+			limit:     int64(burst),
+			remaining: int64(math.Floor(usage.Tokens)),
+			reset:     time.Duration((float64(burst) - usage.Tokens) / c.Rate * float64(time.Second)),
+		})
+	}
+	if c.DailyQuota > 0 {
+		windows = append(windows, window{ReasonDailyQuota, c.DailyQuota, max(0, c.DailyQuota-usage.Daily), nextDay(now).Sub(now)})
+	}
+	if c.MonthlyQuota > 0 {
+		windows = append(windows, window{ReasonMonthlyQuota, c.MonthlyQuota, max(0, c.MonthlyQuota-usage.Monthly), nextMonth(now).Sub(now)})
+	}
+
+	if len(windows) == 0 {
+		return Result{Allowed: true}
+	}
+	reported := windows[0]
+	for _, w := range windows[1:] {
+		if w.remaining < reported.remaining {
+			reported = w
+		}
+	}
+	result := Result{Allowed: usage.Reason == "", Reason: usage.Reason}
+	for _, w := range windows {
+		if w.reason == usage.Reason {
+			reported = w
+		}
+	}
+	result.Limit, result.Remaining, result.Reset = reported.limit, reported.remaining, reported.reset
+
+	switch usage.Reason {
+	case ReasonRate:
+		result.RetryAfter = time.Duration((1 - usage.Tokens) / c.Rate * float64(time.Second))
+	case ReasonDailyQuota, ReasonMonthlyQuota:
+		result.RetryAfter = reported.reset
+	}
+	return result
+}
+
+// policy descreve os limites no formato de RateLimit-Policy (ex: 10;w=1;burst=20, 1000;w=86400)
+func (c Config) policy() string {
+	var policies []string
+	if c.Rate > 0 {
+		limit, window := math.Round(c.Rate), 1.0
+		if c.Rate < 1 {
+			limit, window = 1, math.Round(1/c.Rate)
+		}
+		policies = append(policies, fmt.Sprintf("%d;w=%d;burst=%d", int64(limit), int64(window), c.burst()))
+	}
+	if c.DailyQuota > 0 {
+		policies = append(policies, fmt.Sprintf("%d;w=86400", c.DailyQuota))
+	}
+	if c.MonthlyQuota > 0 {
+		policies = append(policies, fmt.Sprintf("%d;w=2592000", c.MonthlyQuota))
+	}
+	return strings.Join(policies, ", ")
+}
+
+// seconds arredonda a duração para cima, em segundos
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// nextDay retorna o início do dia seguinte (UTC)
+func nextDay(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+// nextMonth retorna o início do mês seguinte (UTC)
+func nextMonth(now time.Time) time.Time {
+	y, m, _ := now.UTC().Date()
+	return time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,378 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-cep-api/auth"
+	"weather-cep-api/handlers"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stores lista os stores testados com o mesmo contrato
+var stores = map[string]func(t *testing.T) Store{
+	"memory": func(t *testing.T) Store {
+		return NewMemory()
+	},
+	"redis": func(t *testing.T) Store {
+		server := miniredis.RunT(t)
+		store, err := NewRedis("redis://" + server.Addr() + "/0")
+		require.NoError(t, err)
+		return store
+	},
+}
+
+// testClock é o relógio controlado dos testes
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestClock(now time.Time) *testClock {
+	return &testClock{now: now}
+}
+
+// allow registra uma requisição do cliente no limitador
+func allow(t *testing.T, l *Limiter, client string) Result {
+	t.Helper()
+	result, err := l.Allow(context.Background(), client)
+	require.NoError(t, err)
+	return result
+}
+
+func TestStores_TokenBucket(t *testing.T) {
+	for name, factory := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := factory(t)
+			defer store.Close()
+			clock := newTestClock(time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC))
+			limiter := NewWithClock(store, Config{Rate: 2, Burst: 3}, clock.Now)
+
+			// A rajada esgota o balde
+			for i := 2; i >= 0; i-- {
+				result := allow(t, limiter, "ip:10.0.0.1")
+				assert.True(t, result.Allowed)
+				assert.Equal(t, int64(3), result.Limit)
+				assert.Equal(t, int64(i), result.Remaining)
+			}
+			result := allow(t, limiter, "ip:10.0.0.1")
+			assert.False(t, result.Allowed)
+			assert.Equal(t, ReasonRate, result.Reason)
+			assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+			assert.Equal(t, 1500*time.Millisecond, result.Reset)
+
+			// Os outros clientes têm o próprio balde
+			assert.True(t, allow(t, limiter, "ip:10.0.0.2").Allowed)
+
+			// O balde repõe RATE tokens por segundo
+			clock.Advance(500 * time.Millisecond)
+			assert.True(t, allow(t, limiter, "ip:10.0.0.1").Allowed)
+			assert.False(t, allow(t, limiter, "ip:10.0.0.1").Allowed)
+
+			// E não passa da capacidade
+			clock.Advance(time.Hour)
+			assert.Equal(t, int64(2), allow(t, limiter, "ip:10.0.0.1").Remaining)
+		})
+	}
+}
+
+func TestStores_Quotas(t *testing.T) {
+	for name, factory := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := factory(t)
+			defer store.Close()
+			clock := newTestClock(time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC))
+			limiter := NewWithClock(store, Config{DailyQuota: 2, MonthlyQuota: 3}, clock.Now)
+
+			result := allow(t, limiter, "api_key:abc")
+			assert.True(t, result.Allowed)
+			assert.Equal(t, int64(2), result.Limit)
+			assert.Equal(t, int64(1), result.Remaining)
+			assert.Equal(t, time.Hour, result.Reset)
+			assert.True(t, allow(t, limiter, "api_key:abc").Allowed)
+
+			result = allow(t, limiter, "api_key:abc")
+			assert.False(t, result.Allowed)
+			assert.Equal(t, ReasonDailyQuota, result.Reason)
+			assert.Equal(t, int64(0), result.Remaining)
+			assert.Equal(t, time.Hour, result.RetryAfter)
+
+			// A cota diária reinicia à meia-noite UTC, junto com a mensal na virada do mês
+			clock.Advance(time.Hour)
+			for i := 0; i < 2; i++ {
+				assert.True(t, allow(t, limiter, "api_key:abc").Allowed)
+			}
+			assert.Equal(t, ReasonDailyQuota, allow(t, limiter, "api_key:abc").Reason)
+
+			// No dia seguinte vale a cota mensal; as recusas anteriores não foram contadas
+			clock.Advance(24 * time.Hour)
+			assert.True(t, allow(t, limiter, "api_key:abc").Allowed)
+			result = allow(t, limiter, "api_key:abc")
+			assert.False(t, result.Allowed)
+			assert.Equal(t, ReasonMonthlyQuota, result.Reason)
+			assert.Equal(t, int64(3), result.Limit)
+			assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC).Sub(clock.now), result.RetryAfter)
+		})
+	}
+}
+
+func TestStores_RejectedRequestsDoNotConsume(t *testing.T) {
+	for name, factory := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := factory(t)
+			defer store.Close()
+			clock := newTestClock(time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC))
+			limiter := NewWithClock(store, Config{Rate: 1, Burst: 1, DailyQuota: 2}, clock.Now)
+
+			assert.True(t, allow(t, limiter, "ip:10.0.0.1").Allowed)
+			for i := 0; i < 5; i++ {
+				assert.Equal(t, ReasonRate, allow(t, limiter, "ip:10.0.0.1").Reason)
+			}
+
+			clock.Advance(time.Second)
+			result := allow(t, limiter, "ip:10.0.0.1")
+			assert.True(t, result.Allowed)
+			assert.Equal(t, int64(0), result.Remaining)
+
+			clock.Advance(time.Second)
+			result = allow(t, limiter, "ip:10.0.0.1")
+			assert.Equal(t, ReasonDailyQuota, result.Reason)
+
+			// Recusada pela cota, a requisição não gasta o token do balde
+			limiter.SetConfig(Config{Rate: 1, Burst: 1})
+			assert.True(t, allow(t, limiter, "ip:10.0.0.1").Allowed)
+		})
+	}
+}
+
+func TestMemory_SweepsIdleClients(t *testing.T) {
+	store := NewMemory()
+	clock := newTestClock(time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC))
+	limiter := NewWithClock(store, Config{Rate: 1, Burst: 1}, clock.Now)
+
+	for _, client := range []string{"ip:10.0.0.1", "ip:10.0.0.2"} {
+		allow(t, limiter, client)
+	}
+	assert.Len(t, store.clients, 2)
+
+	clock.Advance(2 * memorySweepInterval)
+	allow(t, limiter, "ip:10.0.0.3")
+	assert.Len(t, store.clients, 1)
+}
+
+func TestConfig_Policy(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{"rate", Config{Rate: 10, Burst: 20}, "10;w=1;burst=20"},
+		{"slow rate", Config{Rate: 0.5}, "1;w=2;burst=1"},
+		{"quotas", Config{Rate: 2, DailyQuota: 1000, MonthlyQuota: 20000}, "2;w=1;burst=2, 1000;w=86400, 20000;w=2592000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cfg.policy())
+		})
+	}
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := newTestClock(time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC))
+	limiter := NewWithClock(NewMemory(), Config{Rate: 1, Burst: 2, DailyQuota: 100}, clock.Now)
+
+	router := gin.New()
+	router.GET("/temperature/:cep", limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1;w=1;burst=2, 100;w=86400", w.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	request()
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "rate limit exceeded", w.Body.String())
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Sem limites (ex: desativados na recarga) as requisições passam sem cabeçalhos
+	limiter.SetConfig(DefaultConfig())
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestLimiter_Middleware_QuotaExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := newTestClock(time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC))
+	limiter := NewWithClock(NewMemory(), Config{DailyQuota: 1}, clock.Now)
+
+	router := gin.New()
+	router.GET("/temperature/:cep", limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+		if expected == http.StatusTooManyRequests {
+			assert.Equal(t, "daily quota limit exceeded", w.Body.String())
+			assert.Equal(t, "21600", w.Header().Get("Retry-After"))
+			assert.Equal(t, "21600", w.Header().Get("RateLimit-Reset"))
+		}
+	}
+}
+
+func TestLimiter_Middleware_KeysByPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := New(NewMemory(), Config{Rate: 1, Burst: 1})
+	authenticator := auth.Chain{auth.NewStaticToken("secret", auth.ScopeTemperature)}
+
+	router := gin.New()
+	router.GET("/temperature/:cep", auth.Require(authenticator, auth.ScopeTemperature), limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, ClientKey(c))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin_token:admin-token", w.Body.String())
+
+	// Outro IP com a mesma credencial usa o mesmo balde
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.RemoteAddr = "10.0.0.9:1234"
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestLimiter_Middleware_IgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := New(NewMemory(), Config{Rate: 1, Burst: 1})
+
+	router := gin.New()
+	require.NoError(t, handlers.TrustProxies(router, nil, ""))
+	router.GET("/temperature/:cep", limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, ClientKey(c))
+	})
+
+	// Um X-Forwarded-For diferente a cada requisição não gera um balde novo
+	for i, expected := range []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i+1))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+		if expected == http.StatusOK {
+			assert.Equal(t, "ip:203.0.113.7", w.Body.String())
+		}
+	}
+}
+
+// failingStore simula um store indisponível
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Config, time.Time) (Usage, error) {
+	return Usage{}, errors.New("connection refused")
+}
+func (failingStore) Close() error { return nil }
+
+func TestLimiter_Middleware_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := New(failingStore{}, Config{Rate: 1})
+
+	router := gin.New()
+	router.GET("/temperature/:cep", limiter.Middleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/temperature/01001000", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestConfigFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{name: "defaults", env: map[string]string{}, expected: DefaultConfig()},
+		{
+			name: "limits",
+			env: map[string]string{
+				"RATE_LIMIT_RPS": "2.5", "RATE_LIMIT_BURST": "10",
+				"RATE_LIMIT_DAILY_QUOTA": "1000", "RATE_LIMIT_MONTHLY_QUOTA": "20000",
+			},
+			expected: Config{Rate: 2.5, Burst: 10, DailyQuota: 1000, MonthlyQuota: 20000, Backend: BackendMemory},
+		},
+		{
+			name:     "redis from REDIS_URL",
+			env:      map[string]string{"RATE_LIMIT_BACKEND": "Redis", "REDIS_URL": "redis://localhost:6379/0"},
+			expected: Config{Backend: BackendRedis, RedisURL: "redis://localhost:6379/0"},
+		},
+		{
+			name: "redis with own URL",
+			env: map[string]string{
+				"RATE_LIMIT_BACKEND": "redis", "REDIS_URL": "redis://localhost:6379/0", "RATE_LIMIT_REDIS_URL": "redis://limits:6379/1",
+			},
+			expected: Config{Backend: BackendRedis, RedisURL: "redis://limits:6379/1"},
+		},
+		{name: "redis without URL", env: map[string]string{"RATE_LIMIT_BACKEND": "redis"}, wantErr: true},
+		{name: "invalid backend", env: map[string]string{"RATE_LIMIT_BACKEND": "bolt"}, wantErr: true},
+		{name: "negative rate", env: map[string]string{"RATE_LIMIT_RPS": "-1"}, wantErr: true},
+		{name: "invalid burst", env: map[string]string{"RATE_LIMIT_BURST": "many"}, wantErr: true},
+		{name: "invalid quota", env: map[string]string{"RATE_LIMIT_MONTHLY_QUOTA": "-5"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ConfigFrom(func(key string) string { return tt.env[key] })
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout limita cada verificação no Redis para que uma instância lenta não trave as requisições
+const redisTimeout = 500 * time.Millisecond
+
+// takeScript decide e consome de forma atômica no Redis, com a mesma regra do store em memória
+// KEYS: balde (hash tokens/ts), contador do dia, contador do mês
+// ARGV: taxa, capacidade, agora (segundos), cota diária, cota mensal, TTL do dia, TTL do mês
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local daily = tonumber(ARGV[4])
+local monthly = tonumber(ARGV[5])
+
+local tokens = burst
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+if state[1] then
+  tokens = math.min(burst, tonumber(state[1]) + math.max(0, now - tonumber(state[2])) * rate)
+end
+local day = tonumber(redis.call('GET', KEYS[2]) or '0')
+local month = tonumber(redis.call('GET', KEYS[3]) or '0')
+
+local reason = ''
+if rate > 0 and tokens < 1 then
+  reason = 'rate'
+elseif daily > 0 and day >= daily then
+  reason = 'daily_quota'
+elseif monthly > 0 and month >= monthly then
+  reason = 'monthly_quota'
+end
+
+if reason == '' then
+  if rate > 0 then
+    tokens = tokens - 1
+  end
+  if daily > 0 then
+    day = redis.call('INCR', KEYS[2])
+    redis.call('EXPIRE', KEYS[2], ARGV[6])
+  end
+  if monthly > 0 then
+    month = redis.call('INCR', KEYS[3])
+    redis.call('EXPIRE', KEYS[3], ARGV[7])
+  end
+end
+if rate > 0 then
+  redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+  redis.call('EXPIRE', KEYS[1], tostring(math.ceil(burst / rate) + 1))
+end
+return {reason, tostring(tokens), day, month}
+`)
+
+// Redis guarda o estado dos clientes no Redis, compartilhado entre as réplicas
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis conecta ao Redis a partir de uma URL (ex: redis://:senha@localhost:6379/0)
+func NewRedis(rawURL string) (*Redis, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to redis: %w", err)
+	}
+	return NewRedisWithClient(client), nil
+}
+
+// NewRedisWithClient cria o store com um client já configurado
+func NewRedisWithClient(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+// Take executa a decisão no Redis
+// As chaves do cliente usam hash tag ({cliente}) para ficar no mesmo slot de um Redis Cluster
+func (r *Redis) Take(ctx context.Context, client string, cfg Config, now time.Time) (Usage, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	prefix := "ratelimit:{" + client + "}:"
+	keys := []string{prefix + "bucket", prefix + "d:" + dayKey(now), prefix + "m:" + monthKey(now)}
+	// Os contadores expiram um pouco depois do fim do dia e do mês
+	dayTTL := seconds(nextDay(now).Sub(now)) + 60
+	monthTTL := seconds(nextMonth(now).Sub(now)) + 60
+	nowSeconds := float64(now.UnixMicro()) / 1e6
+
+	raw, err := takeScript.Run(ctx, r.client, keys,
+		cfg.Rate, cfg.burst(), strconv.FormatFloat(nowSeconds, 'f', 6, 64),
+		cfg.DailyQuota, cfg.MonthlyQuota, dayTTL, monthTTL).Slice()
+	if err != nil {
+		return Usage{}, fmt.Errorf("error checking rate limit: %w", err)
+	}
+	if len(raw) != 4 {
+		return Usage{}, fmt.Errorf("error checking rate limit: unexpected reply %v", raw)
+	}
+
+	usage := Usage{}
+	usage.Reason, _ = raw[0].(string)
+	tokens, _ := raw[1].(string)
+	usage.Tokens, err = strconv.ParseFloat(tokens, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("error checking rate limit: invalid tokens %q", tokens)
+	}
+	usage.Daily, _ = raw[2].(int64)
+	usage.Monthly, _ = raw[3].(int64)
+	return usage, nil
+}
+
+// Ping verifica a conexão com o Redis (usado na prontidão)
+func (r *Redis) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	return r.client.Ping(ctx).Err()
+}
+
+// Close encerra a conexão com o Redis
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// encryptedPrefix identifica o formato do arquivo criptografado
+const encryptedPrefix = "weather-cep-api-secrets:v1:"
+
+// KeySize é o tamanho da chave AES-256 do arquivo criptografado
+const KeySize = 32
+
+// GenerateKey cria uma chave aleatória, em base64, para SECRETS_KEY
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("error generating key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodifica a chave em base64 e confere o tamanho
+func ParseKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("key is empty")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("key is not valid base64")
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must have %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// Encrypt criptografa o conteúdo (linhas NOME=valor) com AES-256-GCM e o formata para gravação
+func Encrypt(key, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + "\n", nil
+}
+
+// Decrypt recupera o conteúdo gravado por Encrypt; falha com chave errada ou arquivo alterado
+func Decrypt(key []byte, data string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(data), encryptedPrefix)
+	if !ok {
+		return nil, errors.New("unrecognized encrypted secrets format")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("encrypted secrets are not valid base64")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted secrets are truncated")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("error decrypting secrets: wrong key or corrupted file")
+	}
+	return plaintext, nil
+}
+
+// newGCM cria a cifra AES-256-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptedFileProvider lê os segredos de um arquivo local criptografado por Encrypt
+type EncryptedFileProvider struct {
+	path string
+	key  []byte
+}
+
+// NewEncryptedFileProvider cria o provedor para o arquivo e a chave informados
+func NewEncryptedFileProvider(path string, key []byte) *EncryptedFileProvider {
+	return &EncryptedFileProvider{path: path, key: key}
+}
+
+// Name identifica o provedor na origem das configurações
+func (p *EncryptedFileProvider) Name() string {
+	return "encrypted-file"
+}
+
+// Fetch descriptografa o arquivo e retorna os segredos pedidos
+func (p *EncryptedFileProvider) Fetch(_ context.Context, keys []string) (map[string]string, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secrets file: %w", err)
+	}
+	plaintext, err := Decrypt(p.key, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading secrets file %s: %w", p.path, err)
+	}
+	values, err := godotenv.Unmarshal(string(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing secrets file %s: %w", p.path, err)
+	}
+	return pick(values, keys), nil
+}
@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileSuffix é o sufixo das variáveis que apontam para o arquivo com o segredo (ex: WEATHER_API_KEY_FILE)
+const FileSuffix = "_FILE"
+
+// FileRefProvider lê cada segredo do arquivo indicado pela variável <NOME>_FILE
+// É o formato dos secrets do Docker (/run/secrets/...) e dos volumes de secrets do Kubernetes
+type FileRefProvider struct {
+	lookup func(key string) (string, bool)
+}
+
+// NewFileRefProvider cria o provedor; lookup consulta as variáveis <NOME>_FILE
+func NewFileRefProvider(lookup func(key string) (string, bool)) *FileRefProvider {
+	return &FileRefProvider{lookup: lookup}
+}
+
+// Name identifica o provedor na origem das configurações
+func (p *FileRefProvider) Name() string {
+	return "secret-file"
+}
+
+// Fetch lê os arquivos das variáveis <NOME>_FILE definidas; a quebra de linha final é descartada
+func (p *FileRefProvider) Fetch(_ context.Context, keys []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, key := range keys {
+		path, ok := p.lookup(key + FileSuffix)
+		if !ok || path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s%s: %w", key, FileSuffix, err)
+		}
+		if value := strings.TrimRight(string(data), "\r\n"); value != "" {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
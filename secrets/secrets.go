@@ -0,0 +1,111 @@
+// Package secrets busca os valores das configurações secretas (chaves e tokens) fora das variáveis
+// de ambiente: arquivos indicados por variáveis <NOME>_FILE (secrets do Docker e do Kubernetes),
+// um servidor compatível com o HashiCorp Vault (KV v1 e v2) e um arquivo local criptografado
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider fornece os valores das configurações secretas
+type Provider interface {
+	// Name identifica o provedor na origem das configurações (ex: vault)
+	Name() string
+	// Fetch retorna os segredos encontrados entre os nomes pedidos (ex: WEATHER_API_KEY)
+	Fetch(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// HTTPClientInterface define o contrato do client HTTP usado pelo provedor do Vault
+type HTTPClientInterface interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config configura os provedores de segredos
+type Config struct {
+	// VaultAddr é o endereço do Vault (ex: https://vault:8200); vazio desativa o provedor
+	VaultAddr string
+	// VaultToken é o token de acesso ao Vault
+	VaultToken string
+	// VaultPath é o caminho do segredo na API (ex: secret/data/weather-cep-api para KV v2)
+	VaultPath string
+	// VaultNamespace é o namespace do Vault Enterprise (opcional)
+	VaultNamespace string
+	// File é o arquivo criptografado gerado por cmd/secrets-encrypt; vazio desativa o provedor
+	File string
+	// Key é a chave AES-256 do arquivo, em base64
+	Key string
+	// RefreshInterval é o intervalo de releitura dos segredos (0 desativa)
+	RefreshInterval time.Duration
+}
+
+// DefaultConfig retorna a configuração padrão: provedores desativados e releitura a cada 5 minutos
+func DefaultConfig() Config {
+	return Config{RefreshInterval: 5 * time.Minute}
+}
+
+// ConfigFrom lê a configuração de VAULT_ADDR, VAULT_TOKEN, VAULT_SECRET_PATH, VAULT_NAMESPACE,
+// SECRETS_FILE, SECRETS_KEY e SECRETS_REFRESH_INTERVAL a partir da função informada
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	cfg.VaultAddr = strings.TrimSuffix(strings.TrimSpace(getenv("VAULT_ADDR")), "/")
+	cfg.VaultToken = strings.TrimSpace(getenv("VAULT_TOKEN"))
+	cfg.VaultPath = strings.Trim(strings.TrimSpace(getenv("VAULT_SECRET_PATH")), "/")
+	cfg.VaultNamespace = strings.TrimSpace(getenv("VAULT_NAMESPACE"))
+	cfg.File = strings.TrimSpace(getenv("SECRETS_FILE"))
+	cfg.Key = strings.TrimSpace(getenv("SECRETS_KEY"))
+
+	if cfg.VaultAddr != "" && (cfg.VaultToken == "" || cfg.VaultPath == "") {
+		return cfg, fmt.Errorf("VAULT_ADDR requires VAULT_TOKEN and VAULT_SECRET_PATH")
+	}
+	if cfg.File != "" {
+		if _, err := ParseKey(cfg.Key); err != nil {
+			return cfg, fmt.Errorf("invalid SECRETS_KEY: %w", err)
+		}
+	}
+	if raw := strings.TrimSpace(getenv("SECRETS_REFRESH_INTERVAL")); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval < 0 {
+			return cfg, fmt.Errorf("invalid SECRETS_REFRESH_INTERVAL: %q", raw)
+		}
+		cfg.RefreshInterval = interval
+	}
+	return cfg, nil
+}
+
+// Providers cria os provedores configurados, na ordem de precedência: Vault e arquivo criptografado
+func (c Config) Providers(client HTTPClientInterface) []Provider {
+	var providers []Provider
+	if c.VaultAddr != "" {
+		providers = append(providers, NewVaultProvider(client, c.VaultAddr, c.VaultToken, c.VaultPath, c.VaultNamespace))
+	}
+	if c.File != "" {
+		// A chave já foi validada por ConfigFrom
+		key, _ := ParseKey(c.Key)
+		providers = append(providers, NewEncryptedFileProvider(c.File, key))
+	}
+	return providers
+}
+
+// normalizeKey converte o nome de um segredo no nome da configuração (weather-api-key -> WEATHER_API_KEY)
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(strings.TrimSpace(key)))
+}
+
+// pick filtra os segredos pedidos, aceitando nomes em minúsculas ou com hífens
+func pick(values map[string]string, keys []string) map[string]string {
+	normalized := make(map[string]string, len(values))
+	for key, value := range values {
+		normalized[normalizeKey(key)] = value
+	}
+	result := make(map[string]string)
+	for _, key := range keys {
+		if value := normalized[key]; value != "" {
+			result[key] = value
+		}
+	}
+	return result
+}
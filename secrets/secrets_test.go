@@ -0,0 +1,182 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVaultToken = "s.test-token"
+
+// newFakeVault cria um servidor que responde como o Vault para o segredo em /v1/<path>
+func newFakeVault(t *testing.T, path, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Vault-Token") != testVaultToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/"+path {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	keys := []string{"WEATHER_API_KEY", "WEATHER_API_KEYS", "REDIS_URL"}
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		token    string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:     "KV v2",
+			path:     "secret/data/weather-cep-api",
+			body:     `{"data":{"data":{"weather_api_key":"from-vault","weather-api-keys":"a,b","unrelated":"x"},"metadata":{"version":3}}}`,
+			token:    testVaultToken,
+			expected: map[string]string{"WEATHER_API_KEY": "from-vault", "WEATHER_API_KEYS": "a,b"},
+		},
+		{
+			name:     "KV v1",
+			path:     "kv/weather-cep-api",
+			body:     `{"data":{"WEATHER_API_KEY":"from-vault-v1","REDIS_URL":"redis://:pw@redis:6379"}}`,
+			token:    testVaultToken,
+			expected: map[string]string{"WEATHER_API_KEY": "from-vault-v1", "REDIS_URL": "redis://:pw@redis:6379"},
+		},
+		{
+			name:  "Permission denied",
+			path:  "secret/data/weather-cep-api",
+			token: "wrong",
+			err:   "status 403 permission denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeVault(t, tt.path, tt.body)
+			provider := NewVaultProvider(server.Client(), server.URL+"/", tt.token, "/"+tt.path, "")
+			assert.Equal(t, "vault", provider.Name())
+
+			values, err := provider.Fetch(context.Background(), keys)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+func TestEncryptedFileProvider_Fetch(t *testing.T) {
+	encodedKey, err := GenerateKey()
+	require.NoError(t, err)
+	key, err := ParseKey(encodedKey)
+	require.NoError(t, err)
+
+	encrypted, err := Encrypt(key, []byte("# segredos de produção\nWEATHER_API_KEY=from-file\nADMIN_TOKEN=\"admin token\"\n"))
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "from-file")
+
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	require.NoError(t, os.WriteFile(path, []byte(encrypted), 0o600))
+
+	values, err := NewEncryptedFileProvider(path, key).Fetch(context.Background(), []string{"WEATHER_API_KEY", "ADMIN_TOKEN", "REDIS_URL"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"WEATHER_API_KEY": "from-file", "ADMIN_TOKEN": "admin token"}, values)
+
+	// Chave errada ou arquivo alterado não são aceitos
+	otherKey, err := GenerateKey()
+	require.NoError(t, err)
+	wrongKey, _ := ParseKey(otherKey)
+	_, err = NewEncryptedFileProvider(path, wrongKey).Fetch(context.Background(), []string{"WEATHER_API_KEY"})
+	assert.ErrorContains(t, err, "wrong key or corrupted file")
+
+	_, err = Decrypt(key, "WEATHER_API_KEY=plaintext")
+	assert.ErrorContains(t, err, "unrecognized encrypted secrets format")
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("")
+	assert.Error(t, err)
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+	_, err = ParseKey("c2hvcnQ=")
+	assert.EqualError(t, err, "key must have 32 bytes, got 5")
+}
+
+func TestFileRefProvider_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "weather_api_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("from-docker-secret\n"), 0o600))
+
+	vars := map[string]string{
+		"WEATHER_API_KEY_FILE": keyFile,
+		"ADMIN_TOKEN_FILE":     filepath.Join(dir, "missing"),
+	}
+	provider := NewFileRefProvider(func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	})
+
+	values, err := provider.Fetch(context.Background(), []string{"WEATHER_API_KEY", "REDIS_URL"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"WEATHER_API_KEY": "from-docker-secret"}, values)
+
+	// Um arquivo indicado e inexistente é erro de configuração
+	_, err = provider.Fetch(context.Background(), []string{"ADMIN_TOKEN"})
+	assert.ErrorContains(t, err, "error reading ADMIN_TOKEN_FILE")
+}
+
+func TestConfigFrom(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	cfg, err := ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+	assert.Empty(t, cfg.Providers(http.DefaultClient))
+
+	env["VAULT_ADDR"] = "http://vault:8200/"
+	_, err = ConfigFrom(getenv)
+	assert.EqualError(t, err, "VAULT_ADDR requires VAULT_TOKEN and VAULT_SECRET_PATH")
+
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	env["VAULT_TOKEN"] = testVaultToken
+	env["VAULT_SECRET_PATH"] = "secret/data/weather-cep-api"
+	env["SECRETS_FILE"] = "secrets.enc"
+	env["SECRETS_KEY"] = key
+	env["SECRETS_REFRESH_INTERVAL"] = "1m"
+	cfg, err = ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "http://vault:8200", cfg.VaultAddr)
+	assert.Equal(t, time.Minute, cfg.RefreshInterval)
+
+	providers := cfg.Providers(http.DefaultClient)
+	require.Len(t, providers, 2)
+	assert.Equal(t, "vault", providers[0].Name())
+	assert.Equal(t, "encrypted-file", providers[1].Name())
+
+	env["SECRETS_KEY"] = "short"
+	_, err = ConfigFrom(getenv)
+	assert.ErrorContains(t, err, "invalid SECRETS_KEY")
+}
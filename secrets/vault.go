@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider lê os segredos de um servidor compatível com a API HTTP do HashiCorp Vault
+// Aceita os engines KV v1 (secret/<caminho>) e KV v2 (secret/data/<caminho>); os campos do
+// segredo são os nomes das configurações, em maiúsculas ou minúsculas (ex: weather_api_key)
+type VaultProvider struct {
+	client    HTTPClientInterface
+	addr      string
+	token     string
+	path      string
+	namespace string
+}
+
+// NewVaultProvider cria o provedor para o segredo em addr/v1/path
+func NewVaultProvider(client HTTPClientInterface, addr, token, path, namespace string) *VaultProvider {
+	return &VaultProvider{
+		client:    client,
+		addr:      strings.TrimSuffix(addr, "/"),
+		token:     token,
+		path:      strings.Trim(path, "/"),
+		namespace: namespace,
+	}
+}
+
+// Name identifica o provedor na origem das configurações
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// vaultResponse é a resposta da leitura de um segredo (KV v1: data; KV v2: data.data)
+type vaultResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []string                   `json:"errors"`
+}
+
+// Fetch lê o segredo e retorna os campos pedidos
+func (p *VaultProvider) Fetch(ctx context.Context, keys []string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vault secret %s: %w", p.path, err)
+	}
+	defer resp.Body.Close()
+
+	var body vaultResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading vault secret %s: status %d %s", p.path, resp.StatusCode, strings.Join(body.Errors, "; "))
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("error decoding vault secret %s: %w", p.path, decodeErr)
+	}
+
+	fields := body.Data
+	// No KV v2 os campos ficam em data.data, ao lado de data.metadata
+	if nested, ok := body.Data["data"]; ok {
+		if _, versioned := body.Data["metadata"]; versioned {
+			fields = nil
+			if err := json.Unmarshal(nested, &fields); err != nil {
+				return nil, fmt.Errorf("error decoding vault secret %s: %w", p.path, err)
+			}
+		}
+	}
+
+	values := make(map[string]string, len(fields))
+	for key, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// Campos que não são texto (ex: números) são usados como aparecem no JSON
+			value = string(raw)
+		}
+		values[key] = value
+	}
+	return pick(values, keys), nil
+}
@@ -0,0 +1,176 @@
+// Package server controla o ciclo de vida do servidor HTTP da API
+//
+// Ao receber SIGTERM/SIGINT o servidor passa a reprovar a prontidão e continua atendendo
+// durante o período de drenagem (tempo para o balanceador parar de enviar tráfego).
+// Em seguida para de aceitar conexões e espera as requisições em andamento até o prazo;
+// vencido o prazo, o contexto das requisições é cancelado, o que interrompe as chamadas
+// aos provedores. Por fim executa as rotinas de encerramento (traces, estatísticas, caches).
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config descreve o encerramento do servidor
+type Config struct {
+	// DrainPeriod é quanto tempo o servidor continua atendendo com a prontidão reprovada
+	DrainPeriod time.Duration
+	// ShutdownTimeout é o prazo para as requisições em andamento terminarem
+	ShutdownTimeout time.Duration
+	// CleanupTimeout é o prazo das rotinas de encerramento (flush de traces, caches...)
+	CleanupTimeout time.Duration
+}
+
+// DefaultConfig retorna a configuração padrão, que cabe nos 10s que o Cloud Run concede após o SIGTERM
+func DefaultConfig() Config {
+	return Config{
+		DrainPeriod:     2 * time.Second,
+		ShutdownTimeout: 5 * time.Second,
+		CleanupTimeout:  2 * time.Second,
+	}
+}
+
+// ConfigFrom lê SHUTDOWN_DRAIN_PERIOD, SHUTDOWN_TIMEOUT e SHUTDOWN_CLEANUP_TIMEOUT
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	for name, target := range map[string]*time.Duration{
+		"SHUTDOWN_DRAIN_PERIOD":    &cfg.DrainPeriod,
+		"SHUTDOWN_TIMEOUT":         &cfg.ShutdownTimeout,
+		"SHUTDOWN_CLEANUP_TIMEOUT": &cfg.CleanupTimeout,
+	} {
+		raw := strings.TrimSpace(getenv(name))
+		if raw == "" {
+			continue
+		}
+		value, err := time.ParseDuration(raw)
+		if err != nil || value < 0 {
+			return cfg, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		*target = value
+	}
+	return cfg, nil
+}
+
+// cleanup é uma rotina executada no encerramento
+type cleanup struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Server é o servidor HTTP com encerramento gracioso
+type Server struct {
+	httpServer *http.Server
+	cfg        Config
+	cleanups   []cleanup
+	draining   atomic.Bool
+
+	// requestsCtx é o contexto base das requisições; cancelado quando o prazo de encerramento vence
+	requestsCtx    context.Context
+	cancelRequests context.CancelFunc
+}
+
+// New cria o servidor para o endereço e handler informados
+func New(addr string, handler http.Handler, cfg Config) *Server {
+	s := &Server{cfg: cfg}
+	s.requestsCtx, s.cancelRequests = context.WithCancel(context.Background())
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return s.requestsCtx },
+	}
+	return s
+}
+
+// OnShutdown registra uma rotina de encerramento; as rotinas rodam na ordem inversa do registro
+func (s *Server) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.cleanups = append(s.cleanups, cleanup{name: name, fn: fn})
+}
+
+// Draining indica se o servidor está encerrando (a prontidão deve falhar)
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// ListenAndServe atende no endereço configurado até ctx ser cancelado (ex: por um sinal) e então encerra
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", s.httpServer.Addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve atende no listener até ctx ser cancelado e então executa o encerramento gracioso
+// Retorna o erro do servidor (se ele parar sozinho) ou o resultado do encerramento
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// O servidor parou sem pedido de encerramento: ainda assim libera os recursos
+		s.cancelRequests()
+		return errors.Join(fmt.Errorf("server stopped: %w", err), s.runCleanups())
+	case <-ctx.Done():
+	}
+
+	return s.shutdown(serveErr)
+}
+
+// shutdown drena, encerra o servidor HTTP e executa as rotinas de encerramento
+func (s *Server) shutdown(serveErr <-chan error) error {
+	slog.Info("Encerramento iniciado; drenando conexões", "drain_period", s.cfg.DrainPeriod, "timeout", s.cfg.ShutdownTimeout)
+	s.draining.Store(true)
+	time.Sleep(s.cfg.DrainPeriod)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	var result error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		// Prazo vencido: cancela as requisições restantes (e as chamadas aos provedores) e fecha as conexões
+		slog.Warn("Prazo de encerramento vencido; cancelando requisições em andamento", "error", err)
+		s.cancelRequests()
+		if err := s.httpServer.Close(); err != nil {
+			slog.Warn("Erro ao fechar conexões", "error", err)
+		}
+		result = fmt.Errorf("graceful shutdown incomplete: %w", err)
+	}
+	s.cancelRequests()
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		result = errors.Join(result, err)
+	}
+
+	if err := s.runCleanups(); err != nil {
+		result = errors.Join(result, err)
+	}
+	slog.Info("Servidor encerrado")
+	return result
+}
+
+// runCleanups executa as rotinas de encerramento em ordem inversa, todas dentro do prazo de limpeza
+func (s *Server) runCleanups() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.CleanupTimeout)
+	defer cancel()
+
+	var result error
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		c := s.cleanups[i]
+		if err := c.fn(ctx); err != nil {
+			slog.Warn("Erro no encerramento", "step", c.name, "error", err)
+			result = errors.Join(result, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+	return result
+}
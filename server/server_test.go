@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer atende no loopback e devolve a URL base, a função que dispara o encerramento e o resultado de Serve
+func startServer(t *testing.T, s *Server) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, listener) }()
+	t.Cleanup(cancel)
+	return "http://" + listener.Addr().String(), cancel, done
+}
+
+func get(url string) (int, string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), err
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+	mux.HandleFunc("/fast", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	s := New("", mux, Config{DrainPeriod: 200 * time.Millisecond, ShutdownTimeout: 5 * time.Second, CleanupTimeout: time.Second})
+	var order []string
+	s.OnShutdown("tracing", func(context.Context) error { order = append(order, "tracing"); return nil })
+	s.OnShutdown("cache", func(context.Context) error { order = append(order, "cache"); return errors.New("disk full") })
+	url, shutdown, done := startServer(t, s)
+
+	slow := make(chan string, 1)
+	go func() {
+		_, body, _ := get(url + "/slow")
+		slow <- body
+	}()
+	<-started
+
+	assert.False(t, s.Draining())
+	shutdown()
+	require.Eventually(t, s.Draining, time.Second, 5*time.Millisecond)
+
+	// Durante a drenagem novas requisições continuam sendo atendidas
+	status, body, err := get(url + "/fast")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body)
+
+	// A requisição em andamento termina normalmente
+	close(release)
+	assert.Equal(t, "done", <-slow)
+
+	err = <-done
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache: disk full")
+	assert.Equal(t, []string{"cache", "tracing"}, order, "cleanups run in reverse order")
+
+	_, _, err = get(url + "/fast")
+	assert.Error(t, err, "server no longer accepts connections")
+}
+
+func TestServer_Shutdown_CancelsRequestsAfterDeadline(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stuck", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		// Simula uma chamada a um provedor que só termina quando o contexto é cancelado
+		<-r.Context().Done()
+		cancelled <- r.Context().Err()
+	})
+
+	s := New("", mux, Config{ShutdownTimeout: 50 * time.Millisecond, CleanupTimeout: time.Second})
+	cleaned := false
+	s.OnShutdown("stats", func(context.Context) error { cleaned = true; return nil })
+	url, shutdown, done := startServer(t, s)
+
+	go get(url + "/stuck")
+	<-started
+	shutdown()
+
+	err := <-done
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "graceful shutdown incomplete")
+	assert.ErrorIs(t, <-cancelled, context.Canceled)
+	assert.True(t, cleaned)
+}
+
+func TestConfigFrom(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	cfg, err := ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+
+	env["SHUTDOWN_DRAIN_PERIOD"] = "0s"
+	env["SHUTDOWN_TIMEOUT"] = "25s"
+	cfg, err = ConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, Config{DrainPeriod: 0, ShutdownTimeout: 25 * time.Second, CleanupTimeout: 2 * time.Second}, cfg)
+
+	env["SHUTDOWN_TIMEOUT"] = "soon"
+	_, err = ConfigFrom(getenv)
+	assert.Error(t, err)
+}
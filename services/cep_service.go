@@ -1,21 +1,32 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
+	"weather-cep-api/cache"
 	"weather-cep-api/models"
 	"weather-cep-api/utils"
 )
 
 // CEPServiceInterface define o contrato para serviços de CEP
 type CEPServiceInterface interface {
-	GetLocationByCEP(cep string) (*models.LocationInfo, error)
+	GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error)
 }
 
+// DefaultCEPCacheTTL é o tempo padrão que a localização de um CEP fica em cache
+const DefaultCEPCacheTTL = 24 * time.Hour
+
+// cepCacheNamespace prefixa as chaves de localização no backend de cache
+const cepCacheNamespace = "cep:v1:"
+
 // CEPService implementa o serviço de consulta de CEP
 type CEPService struct {
 	httpClient HTTPClientInterface
+	cache      *cache.Store[models.LocationInfo]
 }
 
 // NewCEPService cria uma nova instância do serviço de CEP
@@ -32,21 +43,57 @@ func NewCEPServiceWithClient(client HTTPClientInterface) *CEPService {
 	}
 }
 
+// NewCEPServiceWithCache cria uma nova instância com o backend de cache informado (backend nil ou ttl <= 0 desativa o cache)
+func NewCEPServiceWithCache(client HTTPClientInterface, backend cache.Cache, ttl time.Duration) *CEPService {
+	service := NewCEPServiceWithClient(client)
+	if backend != nil && ttl > 0 {
+		service.cache = cache.NewStore[models.LocationInfo](backend, cepCacheNamespace, ttl)
+	}
+	return service
+}
+
+// CacheStore retorna o cache de localização por CEP (nil quando desativado)
+func (s *CEPService) CacheStore() *cache.Store[models.LocationInfo] {
+	return s.cache
+}
+
+// CEPCacheKey normaliza o CEP para uso como chave do cache de localização
+func CEPCacheKey(cep string) string {
+	return utils.NormalizeCEP(cep)
+}
+
 // GetLocationByCEP consulta informações de localização por CEP usando ViaCEP
-func (s *CEPService) GetLocationByCEP(cep string) (*models.LocationInfo, error) {
+func (s *CEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
 	// Valida formato do CEP
 	if !utils.IsValidCEP(cep) {
 		return nil, fmt.Errorf("invalid zipcode")
 	}
 
+	// Rejeita localmente CEPs fora das faixas de UF dos Correios (ex: 00000-000)
+	prefixUF, ok := utils.UFForCEP(cep)
+	if !ok {
+		return nil, fmt.Errorf("invalid zipcode")
+	}
+
 	// Normaliza o CEP (remove hífen)
 	normalizedCEP := utils.NormalizeCEP(cep)
 
+	if s.cache != nil {
+		cached, ok, err := s.cache.Get(CEPCacheKey(cep))
+		if err != nil {
+			// Falha no cache não impede a consulta à ViaCEP
+			slog.WarnContext(ctx, "Erro ao ler cache de CEP", "error", err)
+		}
+		if ok {
+			return cached, nil
+		}
+	}
+
 	// Constrói URL da ViaCEP
 	url := fmt.Sprintf("https://viacep.com.br/ws/%s/json/", normalizedCEP)
 
 	// Faz a requisição HTTP
-	resp, err := s.httpClient.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching CEP data: %w", err)
 	}
@@ -78,6 +125,22 @@ func (s *CEPService) GetLocationByCEP(cep string) (*models.LocationInfo, error)
 		City:  viaCEPResp.Localidade,
 		State: viaCEPResp.UF,
 		CEP:   utils.FormatCEP(normalizedCEP),
+		IBGE:  viaCEPResp.IBGE,
+	}
+
+	// Confere a UF informada pela ViaCEP com a faixa do CEP (infere pela faixa se vier vazia)
+	if locationInfo.State == "" {
+		locationInfo.State = prefixUF
+	} else if !utils.IsUFConsistentWithCEP(normalizedCEP, locationInfo.State) {
+		slog.WarnContext(ctx, "UF informada pela ViaCEP difere da faixa do CEP",
+			"uf", locationInfo.State, "cep", locationInfo.CEP, "expected_uf", prefixUF)
+		locationInfo.UFMismatch = true
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(CEPCacheKey(cep), locationInfo); err != nil {
+			slog.WarnContext(ctx, "Erro ao gravar cache de CEP", "error", err)
+		}
 	}
 
 	return locationInfo, nil
@@ -1,11 +1,14 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
+	"weather-cep-api/cache"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,8 +20,8 @@ type MockHTTPClient struct {
 	mock.Mock
 }
 
-func (m *MockHTTPClient) Get(url string) (*http.Response, error) {
-	args := m.Called(url)
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -50,13 +53,13 @@ func TestCEPService_GetLocationByCEP_Success(t *testing.T) {
 
 	// Configura mock do HTTP client
 	mockClient := new(MockHTTPClient)
-	mockClient.On("Get", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil)
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewCEPServiceWithClient(mockClient)
 
 	// Executa o teste
-	result, err := service.GetLocationByCEP("01310-100")
+	result, err := service.GetLocationByCEP(context.Background(), "01310-100")
 
 	// Assertions
 	require.NoError(t, err)
@@ -64,6 +67,7 @@ func TestCEPService_GetLocationByCEP_Success(t *testing.T) {
 	assert.Equal(t, "São Paulo", result.City)
 	assert.Equal(t, "SP", result.State)
 	assert.Equal(t, "01310-100", result.CEP)
+	assert.Equal(t, "3550308", result.IBGE)
 
 	// Verifica se o mock foi chamado corretamente
 	mockClient.AssertExpectations(t)
@@ -96,7 +100,7 @@ func TestCEPService_GetLocationByCEP_InvalidCEP(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := service.GetLocationByCEP(tt.cep)
+			result, err := service.GetLocationByCEP(context.Background(), tt.cep)
 
 			assert.Error(t, err)
 			assert.Nil(t, result)
@@ -121,13 +125,13 @@ func TestCEPService_GetLocationByCEP_NotFound(t *testing.T) {
 
 	// Configura mock do HTTP client
 	mockClient := new(MockHTTPClient)
-	mockClient.On("Get", "https://viacep.com.br/ws/99999999/json/").Return(resp, nil)
+	mockClient.On("Do", "https://viacep.com.br/ws/99999999/json/").Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewCEPServiceWithClient(mockClient)
 
 	// Executa o teste
-	result, err := service.GetLocationByCEP("99999-999")
+	result, err := service.GetLocationByCEP(context.Background(), "99999-999")
 
 	// Assertions
 	assert.Error(t, err)
@@ -147,13 +151,13 @@ func TestCEPService_GetLocationByCEP_ServerError(t *testing.T) {
 	}
 
 	mockClient := new(MockHTTPClient)
-	mockClient.On("Get", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil)
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewCEPServiceWithClient(mockClient)
 
 	// Executa o teste
-	result, err := service.GetLocationByCEP("01310-100")
+	result, err := service.GetLocationByCEP(context.Background(), "01310-100")
 
 	// Assertions
 	assert.Error(t, err)
@@ -167,13 +171,13 @@ func TestCEPService_GetLocationByCEP_ServerError(t *testing.T) {
 func TestCEPService_GetLocationByCEP_HTTPError(t *testing.T) {
 	// Configura mock do HTTP client para retornar erro de conexão
 	mockClient := new(MockHTTPClient)
-	mockClient.On("Get", "https://viacep.com.br/ws/01310100/json/").Return(nil, errors.New("connection error"))
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(nil, errors.New("connection error"))
 
 	// Cria service com HTTP client mockado
 	service := NewCEPServiceWithClient(mockClient)
 
 	// Executa o teste
-	result, err := service.GetLocationByCEP("01310-100")
+	result, err := service.GetLocationByCEP(context.Background(), "01310-100")
 
 	// Assertions
 	assert.Error(t, err)
@@ -182,4 +186,116 @@ func TestCEPService_GetLocationByCEP_HTTPError(t *testing.T) {
 
 	// Verifica se o mock foi chamado corretamente
 	mockClient.AssertExpectations(t)
-} 
\ No newline at end of file
+}
+
+func TestCEPService_GetLocationByCEP_OutOfRange(t *testing.T) {
+	// CEPs fora das faixas dos Correios são rejeitados sem consultar a ViaCEP
+	mockClient := new(MockHTTPClient)
+	service := NewCEPServiceWithClient(mockClient)
+
+	for _, cep := range []string{"00000000", "00999-999"} {
+		result, err := service.GetLocationByCEP(context.Background(), cep)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "invalid zipcode")
+	}
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestCEPService_GetLocationByCEP_UFMismatch(t *testing.T) {
+	// ViaCEP informa uma UF diferente da faixa do CEP (SP)
+	mockResponse := `{"cep": "01310-100", "localidade": "São Paulo", "uf": "RJ"}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil)
+
+	service := NewCEPServiceWithClient(mockClient)
+
+	result, err := service.GetLocationByCEP(context.Background(), "01310100")
+
+	require.NoError(t, err)
+	assert.Equal(t, "RJ", result.State)
+	assert.True(t, result.UFMismatch)
+}
+
+func TestCEPService_GetLocationByCEP_InfersUF(t *testing.T) {
+	// Sem UF na resposta, a UF é inferida pela faixa do CEP
+	mockResponse := `{"cep": "70040-010", "localidade": "Brasília"}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://viacep.com.br/ws/70040010/json/").Return(resp, nil)
+
+	service := NewCEPServiceWithClient(mockClient)
+
+	result, err := service.GetLocationByCEP(context.Background(), "70040-010")
+
+	require.NoError(t, err)
+	assert.Equal(t, "DF", result.State)
+	assert.False(t, result.UFMismatch)
+}
+
+func TestCEPService_GetLocationByCEP_Cache(t *testing.T) {
+	mockResponse := `{"cep": "01310-100", "localidade": "São Paulo", "uf": "SP", "ibge": "3550308"}`
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	// A ViaCEP é consultada uma única vez
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(resp, nil).Once()
+
+	service := NewCEPServiceWithCache(mockClient, cache.NewMemory(), time.Hour)
+
+	first, err := service.GetLocationByCEP(context.Background(), "01310-100")
+	require.NoError(t, err)
+
+	// Segunda consulta (sem hífen) vem do cache
+	second, err := service.GetLocationByCEP(context.Background(), "01310100")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "3550308", second.IBGE)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCEPService_GetLocationByCEP_CacheSkipsNotFound(t *testing.T) {
+	newResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"erro": "true"}`)),
+			Header:     make(http.Header),
+		}
+	}
+
+	// CEP inexistente não é guardado: as duas consultas vão à ViaCEP
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://viacep.com.br/ws/99999999/json/").Return(newResponse(), nil).Once()
+	mockClient.On("Do", "https://viacep.com.br/ws/99999999/json/").Return(newResponse(), nil).Once()
+
+	backend := cache.NewMemory()
+	service := NewCEPServiceWithCache(mockClient, backend, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.GetLocationByCEP(context.Background(), "99999-999")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can not find zipcode")
+	}
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"weather-cep-api/cache"
+	"weather-cep-api/ibge"
+	"weather-cep-api/models"
+	"weather-cep-api/utils"
+)
+
+// ReverseGeocoderInterface define o contrato para geocodificação reversa (coordenadas -> localidade)
+type ReverseGeocoderInterface interface {
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*models.LocationInfo, error)
+}
+
+// nominatimUserAgent identifica a aplicação, como exige a política de uso do Nominatim
+const nominatimUserAgent = "weather-cep-api (+https://github.com/danielencestari/lab01)"
+
+// nominatimInterval é o intervalo mínimo entre as consultas ao Nominatim (a política de uso permite 1 por segundo)
+const nominatimInterval = time.Second
+
+// nominatimMaxWait é a maior espera por uma vaga no limite; acima dela a consulta é recusada, pois a
+// localização é opcional e não deve atrasar a resposta de temperatura
+const nominatimMaxWait = time.Second
+
+// geocoderCacheNamespace prefixa as chaves de geocodificação reversa no backend de cache
+const geocoderCacheNamespace = "geo:v1:"
+
+// ErrGeocoderRateLimited indica que a consulta foi recusada pelo limite de consultas ao Nominatim
+var ErrGeocoderRateLimited = errors.New("reverse geocoding rate limit exceeded")
+
+// NominatimGeocoder implementa a geocodificação reversa usando o Nominatim (OpenStreetMap)
+// As localidades encontradas ficam em cache e as consultas respeitam o limite de 1 por segundo
+type NominatimGeocoder struct {
+	httpClient HTTPClientInterface
+	cache      *cache.Store[models.LocationInfo]
+	throttle   *throttle
+}
+
+// NewNominatimGeocoder cria uma nova instância do geocodificador Nominatim
+func NewNominatimGeocoder() *NominatimGeocoder {
+	return NewNominatimGeocoderWithClient(NewNominatimHTTPClient())
+}
+
+// NewNominatimHTTPClient cria o HTTP client com o User-Agent exigido pela política de uso do Nominatim
+func NewNominatimHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &userAgentTransport{userAgent: nominatimUserAgent, base: http.DefaultTransport},
+	}
+}
+
+// NewNominatimGeocoderWithClient cria uma nova instância com HTTP client customizado (para testes)
+func NewNominatimGeocoderWithClient(client HTTPClientInterface) *NominatimGeocoder {
+	return NewNominatimGeocoderWithCache(client, nil, 0)
+}
+
+// NewNominatimGeocoderWithCache cria uma nova instância com o backend de cache informado
+// (backend nil ou ttl <= 0 desativa o cache)
+func NewNominatimGeocoderWithCache(client HTTPClientInterface, backend cache.Cache, ttl time.Duration) *NominatimGeocoder {
+	geocoder := &NominatimGeocoder{
+		httpClient: client,
+		throttle:   newThrottle(nominatimInterval, nominatimMaxWait),
+	}
+	if backend != nil && ttl > 0 {
+		geocoder.cache = cache.NewStore[models.LocationInfo](backend, geocoderCacheNamespace, ttl)
+	}
+	return geocoder
+}
+
+// CacheStore retorna o cache de geocodificação reversa (nil quando desativado)
+func (g *NominatimGeocoder) CacheStore() *cache.Store[models.LocationInfo] {
+	return g.cache
+}
+
+// GeocoderCacheKey arredonda as coordenadas para 4 casas decimais (cerca de 11 m), de modo que
+// pontos vizinhos compartilhem a mesma entrada do cache
+func GeocoderCacheKey(lat, lon float64) string {
+	return fmt.Sprintf("%.4f,%.4f", lat, lon)
+}
+
+// ReverseGeocode consulta a cidade, UF e CEP mais próximos das coordenadas
+func (g *NominatimGeocoder) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.LocationInfo, error) {
+	key := GeocoderCacheKey(lat, lon)
+	if g.cache != nil {
+		cached, ok, err := g.cache.Get(key)
+		if err != nil {
+			// Falha no cache não impede a consulta ao Nominatim
+			slog.WarnContext(ctx, "Erro ao ler cache de geocodificação", "error", err)
+		} else if ok {
+			return cached, nil
+		}
+	}
+
+	location, err := g.fetch(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if g.cache != nil {
+		if err := g.cache.Set(key, location); err != nil {
+			slog.WarnContext(ctx, "Erro ao gravar cache de geocodificação", "error", err)
+		}
+	}
+	return location, nil
+}
+
+// fetch consulta o Nominatim, respeitando o limite de consultas
+func (g *NominatimGeocoder) fetch(ctx context.Context, lat, lon float64) (*models.LocationInfo, error) {
+	if err := g.throttle.wait(ctx); err != nil {
+		return nil, err
+	}
+	reverseURL := fmt.Sprintf("https://nominatim.openstreetmap.org/reverse?format=jsonv2&addressdetails=1&zoom=18&lat=%.6f&lon=%.6f", lat, lon)
+
+	// Faz a requisição HTTP
+	resp, err := getWithContext(ctx, g.httpClient, reverseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reverse geocoding data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Verifica status da resposta
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching reverse geocoding data: status %d", resp.StatusCode)
+	}
+
+	// Decodifica a resposta JSON
+	var nominatimResp models.NominatimReverseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&nominatimResp); err != nil {
+		return nil, fmt.Errorf("error decoding reverse geocoding response: %w", err)
+	}
+
+	address := nominatimResp.Address
+	if nominatimResp.Error != "" || address.CountryCode != "br" {
+		return nil, fmt.Errorf("can not find location")
+	}
+
+	city := firstNonEmpty(address.City, address.Town, address.Village, address.Municipality)
+	if city == "" {
+		return nil, fmt.Errorf("can not find location")
+	}
+
+	// O código ISO vem no formato "BR-SP"
+	location := &models.LocationInfo{
+		City:  city,
+		State: strings.TrimPrefix(address.StateCode, "BR-"),
+	}
+	if utils.IsValidCEP(address.Postcode) {
+		location.CEP = utils.FormatCEP(address.Postcode)
+	}
+
+	return location, nil
+}
+
+// throttle espaça as consultas em pelo menos interval, compartilhado por todas as requisições
+type throttle struct {
+	interval time.Duration
+	maxWait  time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newThrottle cria o limite com o intervalo e a maior espera por uma vaga
+func newThrottle(interval, maxWait time.Duration) *throttle {
+	return &throttle{interval: interval, maxWait: maxWait}
+}
+
+// wait reserva a próxima vaga e espera por ela; recusa com ErrGeocoderRateLimited quando a vaga
+// está além de maxWait e retorna o erro do contexto se a requisição for cancelada
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.next
+	if slot.Before(now) {
+		slot = now
+	}
+	delay := slot.Sub(now)
+	if delay > t.maxWait {
+		t.mu.Unlock()
+		return ErrGeocoderRateLimited
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// KnownPlace representa uma localidade conhecida com suas coordenadas
+type KnownPlace struct {
+	Location models.LocationInfo
+	Lat      float64
+	Lon      float64
+}
+
+// LocalReverseGeocoder resolve coordenadas para a localidade conhecida mais próxima, sem rede
+// Útil em testes e ambientes sem acesso ao Nominatim
+type LocalReverseGeocoder struct {
+	places        []KnownPlace
+	maxDistanceKm float64
+}
+
+// NewLocalReverseGeocoder cria um geocodificador local (maxDistanceKm <= 0 aceita qualquer distância)
+func NewLocalReverseGeocoder(places []KnownPlace, maxDistanceKm float64) *LocalReverseGeocoder {
+	return &LocalReverseGeocoder{
+		places:        places,
+		maxDistanceKm: maxDistanceKm,
+	}
+}
+
+// NewIBGEReverseGeocoder cria um geocodificador local a partir da tabela de municípios do IBGE
+// Resolve cidade, UF e código IBGE (o CEP não consta na tabela)
+func NewIBGEReverseGeocoder(table *ibge.Table, maxDistanceKm float64) *LocalReverseGeocoder {
+	municipalities := table.All()
+	places := make([]KnownPlace, 0, len(municipalities))
+	for _, m := range municipalities {
+		places = append(places, KnownPlace{
+			Location: models.LocationInfo{City: m.Name, State: m.UF, IBGE: m.Code},
+			Lat:      m.Latitude,
+			Lon:      m.Longitude,
+		})
+	}
+	return NewLocalReverseGeocoder(places, maxDistanceKm)
+}
+
+// ReverseGeocode retorna a localidade conhecida mais próxima das coordenadas
+func (g *LocalReverseGeocoder) ReverseGeocode(_ context.Context, lat, lon float64) (*models.LocationInfo, error) {
+	var nearest *KnownPlace
+	nearestDistance := 0.0
+
+	for i := range g.places {
+		distance := utils.HaversineKm(lat, lon, g.places[i].Lat, g.places[i].Lon)
+		if nearest == nil || distance < nearestDistance {
+			nearest = &g.places[i]
+			nearestDistance = distance
+		}
+	}
+
+	if nearest == nil || (g.maxDistanceKm > 0 && nearestDistance > g.maxDistanceKm) {
+		return nil, fmt.Errorf("can not find location")
+	}
+
+	location := nearest.Location
+	return &location, nil
+}
+
+// userAgentTransport adiciona o cabeçalho User-Agent em todas as requisições
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(clone)
+}
+
+// firstNonEmpty retorna o primeiro valor não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
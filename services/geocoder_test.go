@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"weather-cep-api/cache"
+	"weather-cep-api/ibge"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const nominatimTestURL = "https://nominatim.openstreetmap.org/reverse?format=jsonv2&addressdetails=1&zoom=18&lat=-23.561300&lon=-46.656500"
+
+func TestNominatimGeocoder_ReverseGeocode_Success(t *testing.T) {
+	// Mock da resposta do Nominatim
+	mockResponse := `{
+		"address": {
+			"road": "Avenida Paulista",
+			"city": "São Paulo",
+			"state": "São Paulo",
+			"ISO3166-2-lvl4": "BR-SP",
+			"postcode": "01310-100",
+			"country_code": "br"
+		}
+	}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", nominatimTestURL).Return(resp, nil)
+
+	geocoder := NewNominatimGeocoderWithClient(mockClient)
+
+	// Executa o teste
+	result, err := geocoder.ReverseGeocode(context.Background(), -23.5613, -46.6565)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.City)
+	assert.Equal(t, "SP", result.State)
+	assert.Equal(t, "01310-100", result.CEP)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestNominatimGeocoder_ReverseGeocode_TownWithoutPostcode(t *testing.T) {
+	mockResponse := `{"address": {"town": "Paraty", "ISO3166-2-lvl4": "BR-RJ", "country_code": "br"}}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", nominatimTestURL).Return(resp, nil)
+
+	result, err := NewNominatimGeocoderWithClient(mockClient).ReverseGeocode(context.Background(), -23.5613, -46.6565)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Paraty", result.City)
+	assert.Equal(t, "RJ", result.State)
+	assert.Empty(t, result.CEP)
+}
+
+func TestNominatimGeocoder_ReverseGeocode_OutsideBrazil(t *testing.T) {
+	mockResponse := `{"address": {"city": "Buenos Aires", "country_code": "ar"}}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", nominatimTestURL).Return(resp, nil)
+
+	result, err := NewNominatimGeocoderWithClient(mockClient).ReverseGeocode(context.Background(), -23.5613, -46.6565)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "can not find location")
+}
+
+func TestNominatimGeocoder_ReverseGeocode_HTTPError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", nominatimTestURL).Return(nil, errors.New("connection error"))
+
+	result, err := NewNominatimGeocoderWithClient(mockClient).ReverseGeocode(context.Background(), -23.5613, -46.6565)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "error fetching reverse geocoding data")
+}
+
+func TestNominatimGeocoder_ReverseGeocode_Cache(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"address": {"town": "Paraty", "ISO3166-2-lvl4": "BR-RJ", "country_code": "br"}}`)),
+		Header:     make(http.Header),
+	}
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", nominatimTestURL).Return(resp, nil).Once()
+	geocoder := NewNominatimGeocoderWithCache(mockClient, cache.NewMemory(), time.Hour)
+
+	first, err := geocoder.ReverseGeocode(context.Background(), -23.5613, -46.6565)
+	require.NoError(t, err)
+
+	// Pontos a menos de 5 m usam a entrada do cache, sem consultar o Nominatim nem esperar o limite
+	second, err := geocoder.ReverseGeocode(context.Background(), -23.56131, -46.65651)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestNominatimGeocoder_ReverseGeocode_RateLimited(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", mock.Anything).Return(nil, errors.New("connection refused"))
+	geocoder := NewNominatimGeocoderWithClient(mockClient)
+
+	// A primeira consulta ocupa a vaga do segundo; a seguinte esperaria além do limite e é recusada
+	geocoder.throttle = newThrottle(time.Hour, time.Second)
+	_, err := geocoder.ReverseGeocode(context.Background(), -23.5613, -46.6565)
+	assert.ErrorContains(t, err, "error fetching reverse geocoding data")
+	_, err = geocoder.ReverseGeocode(context.Background(), -22.9, -43.2)
+	assert.ErrorIs(t, err, ErrGeocoderRateLimited)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestThrottle_Wait(t *testing.T) {
+	limit := newThrottle(50*time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, limit.wait(ctx))
+	require.NoError(t, limit.wait(ctx))
+	require.NoError(t, limit.wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// A requisição cancelada desiste de esperar pela vaga
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, limit.wait(canceled), context.Canceled)
+}
+
+func TestLocalReverseGeocoder_ReverseGeocode(t *testing.T) {
+	geocoder := NewLocalReverseGeocoder([]KnownPlace{
+		{Location: models.LocationInfo{City: "São Paulo", State: "SP"}, Lat: -23.5505, Lon: -46.6333},
+		{Location: models.LocationInfo{City: "Campinas", State: "SP"}, Lat: -22.9056, Lon: -47.0608},
+	}, 30)
+
+	// Ponto próximo de Campinas
+	result, err := geocoder.ReverseGeocode(context.Background(), -22.95, -47.1)
+	require.NoError(t, err)
+	assert.Equal(t, "Campinas", result.City)
+
+	// Ponto distante de todas as localidades conhecidas
+	result, err = geocoder.ReverseGeocode(context.Background(), -3.1, -60.02)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestSwitchableGeocoder_ReverseGeocode(t *testing.T) {
+	saoPaulo := NewLocalReverseGeocoder([]KnownPlace{
+		{Location: models.LocationInfo{City: "São Paulo", State: "SP"}, Lat: -23.5505, Lon: -46.6333},
+	}, 30)
+	campinas := NewLocalReverseGeocoder([]KnownPlace{
+		{Location: models.LocationInfo{City: "Campinas", State: "SP"}, Lat: -22.9056, Lon: -47.0608},
+	}, 100)
+	geocoder := NewSwitchableGeocoder(saoPaulo)
+
+	result, err := geocoder.ReverseGeocode(context.Background(), -23.55, -46.63)
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.City)
+
+	// As próximas consultas usam o provedor trocado na recarga
+	geocoder.Set(campinas)
+	result, err = geocoder.ReverseGeocode(context.Background(), -23.55, -46.63)
+	require.NoError(t, err)
+	assert.Equal(t, "Campinas", result.City)
+
+	geocoder.Set(nil)
+	assert.Nil(t, geocoder.Current())
+	_, err = geocoder.ReverseGeocode(context.Background(), -23.55, -46.63)
+	assert.ErrorIs(t, err, ErrGeocoderDisabled)
+}
+
+func TestWeatherService_GetConditionsByCoordinates_Success(t *testing.T) {
+	mockResponse := `{"location": {"name": "Sao Paulo", "tz_id": "America/Sao_Paulo"}, "current": {"temp_c": 24.0}}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=-23.5613%2C-46.6565&aqi=no"
+	mockClient.On("Do", expectedURL).Return(resp, nil)
+
+	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
+
+	result, err := service.GetConditionsByCoordinates(context.Background(), -23.56131, -46.65649)
+
+	require.NoError(t, err)
+	assert.Equal(t, 24.0, result.TempC)
+	assert.Equal(t, "America/Sao_Paulo", result.TimeZone)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIBGEReverseGeocoder_ReverseGeocode(t *testing.T) {
+	geocoder := NewIBGEReverseGeocoder(ibge.Default(), 50)
+
+	// Avenida Paulista resolve para o município de São Paulo com o código do IBGE
+	result, err := geocoder.ReverseGeocode(context.Background(), -23.5613, -46.6565)
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.City)
+	assert.Equal(t, "SP", result.State)
+	assert.Equal(t, "3550308", result.IBGE)
+}
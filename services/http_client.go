@@ -1,8 +1,20 @@
 package services
 
-import "net/http"
- 
+import (
+	"context"
+	"net/http"
+)
+
 // HTTPClientInterface define o contrato para HTTP clients
 type HTTPClientInterface interface {
-	Get(url string) (*http.Response, error)
-} 
\ No newline at end of file
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// getWithContext faz um GET vinculado ao contexto da requisição (cancelamento e propagação do trace)
+func getWithContext(ctx context.Context, client HTTPClientInterface, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Estratégias de escolha da chave da WeatherAPI (WEATHER_API_KEY_STRATEGY)
+const (
+	// KeySelectionRoundRobin alterna entre as chaves disponíveis
+	KeySelectionRoundRobin = "round-robin"
+	// KeySelectionQuotaAware usa a chave disponível com menos chamadas no mês
+	KeySelectionQuotaAware = "quota-aware"
+)
+
+// ErrNoAPIKeyAvailable indica que todas as chaves da WeatherAPI estão em quarentena ou sem cota
+var ErrNoAPIKeyAvailable = errors.New("no weather API key available")
+
+// NoAPIKeyError é o ErrNoAPIKeyAvailable devolvido por Acquire, com o tempo até a próxima chave
+// voltar ao pool (fim da quarentena ou virada do mês)
+type NoAPIKeyError struct {
+	RetryAfter time.Duration
+}
+
+func (e *NoAPIKeyError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", ErrNoAPIKeyAvailable, e.RetryAfter)
+}
+
+func (e *NoAPIKeyError) Unwrap() error {
+	return ErrNoAPIKeyAvailable
+}
+
+// KeyOutcome classifica o resultado de uma chamada feita com uma chave
+type KeyOutcome string
+
+const (
+	// KeyOK indica que a chave foi aceita (inclusive em erros da consulta, ex: cidade não encontrada)
+	KeyOK KeyOutcome = "ok"
+	// KeyFailed indica falha sem relação com a chave (rede ou 5xx)
+	KeyFailed KeyOutcome = "error"
+	// KeyRejected indica chave inválida, desativada ou sem acesso (401/403)
+	KeyRejected KeyOutcome = "rejected"
+	// KeyQuotaExceeded indica cota mensal da chave esgotada
+	KeyQuotaExceeded KeyOutcome = "quota_exceeded"
+)
+
+// KeyPoolConfig configura a escolha e a quarentena das chaves
+type KeyPoolConfig struct {
+	// Strategy é KeySelectionRoundRobin ou KeySelectionQuotaAware
+	Strategy string
+	// MonthlyQuota é o limite de chamadas por chave no mês (0 sem limite); a chave que o atinge
+	// fica fora do pool até o mês seguinte
+	MonthlyQuota int64
+	// Quarantine é o tempo fora do pool de uma chave recusada (401/403)
+	Quarantine time.Duration
+}
+
+// DefaultKeyPoolConfig retorna a configuração padrão: round-robin, sem limite mensal e quarentena de 1h
+func DefaultKeyPoolConfig() KeyPoolConfig {
+	return KeyPoolConfig{Strategy: KeySelectionRoundRobin, Quarantine: time.Hour}
+}
+
+// KeyPoolConfigFrom lê a configuração de WEATHER_API_KEY_STRATEGY, WEATHER_API_KEY_QUOTA e
+// WEATHER_API_KEY_QUARANTINE a partir da função informada
+func KeyPoolConfigFrom(getenv func(string) string) (KeyPoolConfig, error) {
+	cfg := DefaultKeyPoolConfig()
+
+	if raw := strings.TrimSpace(getenv("WEATHER_API_KEY_STRATEGY")); raw != "" {
+		switch raw {
+		case KeySelectionRoundRobin, KeySelectionQuotaAware:
+			cfg.Strategy = raw
+		default:
+			return cfg, fmt.Errorf("invalid WEATHER_API_KEY_STRATEGY: %q (use round-robin or quota-aware)", raw)
+		}
+	}
+	if raw := strings.TrimSpace(getenv("WEATHER_API_KEY_QUOTA")); raw != "" {
+		quota, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || quota < 0 {
+			return cfg, fmt.Errorf("invalid WEATHER_API_KEY_QUOTA: %q", raw)
+		}
+		cfg.MonthlyQuota = quota
+	}
+	if raw := strings.TrimSpace(getenv("WEATHER_API_KEY_QUARANTINE")); raw != "" {
+		quarantine, err := time.ParseDuration(raw)
+		if err != nil || quarantine <= 0 {
+			return cfg, fmt.Errorf("invalid WEATHER_API_KEY_QUARANTINE: %q", raw)
+		}
+		cfg.Quarantine = quarantine
+	}
+	return cfg, nil
+}
+
+// KeyStats são as estatísticas de uma chave (identificada pelo início do SHA-256, nunca pela chave)
+type KeyStats struct {
+	ID string `json:"id"`
+	// Available indica se a chave pode ser escolhida agora
+	Available bool `json:"available"`
+	// QuarantinedUntil é o fim da quarentena (zero quando a chave não está em quarentena)
+	QuarantinedUntil time.Time `json:"quarantined_until,omitempty"`
+	// MonthlyUsage são as chamadas feitas com a chave no mês corrente (UTC)
+	MonthlyUsage int64 `json:"monthly_usage"`
+	// Requests são as chamadas desde a inicialização, por resultado
+	Requests map[KeyOutcome]int64 `json:"requests"`
+}
+
+// pooledKey é o estado de uma chave no pool
+type pooledKey struct {
+	key              string
+	id               string
+	quarantinedUntil time.Time
+	monthlyUsage     int64
+	requests         map[KeyOutcome]int64
+}
+
+// KeyPool distribui as chamadas à WeatherAPI entre várias chaves
+// Chaves recusadas (401/403) ficam em quarentena por KeyPoolConfig.Quarantine e chaves sem cota,
+// até o início do mês seguinte; o uso de cada chave é contado por mês para a escolha quota-aware
+type KeyPool struct {
+	mu     sync.Mutex
+	cfg    KeyPoolConfig
+	keys   []*pooledKey
+	next   int
+	period string
+	now    func() time.Time
+}
+
+// NewKeyPool cria o pool com as chaves informadas (vazias e repetidas são ignoradas)
+func NewKeyPool(keys []string, cfg KeyPoolConfig) *KeyPool {
+	return NewKeyPoolWithClock(keys, cfg, time.Now)
+}
+
+// NewKeyPoolWithClock cria o pool com relógio customizado (para testes)
+func NewKeyPoolWithClock(keys []string, cfg KeyPoolConfig, now func() time.Time) *KeyPool {
+	p := &KeyPool{cfg: cfg, now: now}
+	p.SetKeys(keys)
+	return p
+}
+
+// SetKeys troca as chaves do pool (rotação); as chaves mantidas preservam quarentena e contadores
+func (p *KeyPool) SetKeys(keys []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	existing := make(map[string]*pooledKey, len(p.keys))
+	for _, k := range p.keys {
+		existing[k.key] = k
+	}
+	var updated []*pooledKey
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if k, ok := existing[key]; ok {
+			updated = append(updated, k)
+			continue
+		}
+		updated = append(updated, &pooledKey{key: key, id: keyID(key), requests: make(map[KeyOutcome]int64)})
+	}
+	p.keys = updated
+	p.next = 0
+}
+
+// SetConfig troca a estratégia, a cota mensal e a quarentena (as quarentenas em curso são mantidas)
+func (p *KeyPool) SetConfig(cfg KeyPoolConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+}
+
+// Len retorna a quantidade de chaves no pool
+func (p *KeyPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// Acquire escolhe a chave da próxima chamada, ignorando as já tentadas pela requisição
+// A chamada é contada no uso mensal da chave escolhida
+func (p *KeyPool) Acquire(tried map[string]bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return "", errors.New("weather API key not configured")
+	}
+	now := p.now()
+	p.resetPeriod(now)
+
+	chosen := -1
+	for i := 0; i < len(p.keys); i++ {
+		index := (p.next + i) % len(p.keys)
+		k := p.keys[index]
+		if tried[k.key] || !p.available(k, now) {
+			continue
+		}
+		if chosen < 0 || (p.cfg.Strategy == KeySelectionQuotaAware && k.monthlyUsage < p.keys[chosen].monthlyUsage) {
+			chosen = index
+		}
+		if p.cfg.Strategy != KeySelectionQuotaAware {
+			break
+		}
+	}
+	if chosen < 0 {
+		return "", &NoAPIKeyError{RetryAfter: p.retryAfter(now)}
+	}
+
+	p.next = (chosen + 1) % len(p.keys)
+	p.keys[chosen].monthlyUsage++
+	return p.keys[chosen].key, nil
+}
+
+// Report registra o resultado da chamada feita com a chave e põe em quarentena as recusadas ou sem cota
+func (p *KeyPool) Report(key string, outcome KeyOutcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := p.find(key)
+	if k == nil {
+		// Chave removida do pool enquanto a chamada estava em andamento
+		return
+	}
+	k.requests[outcome]++
+
+	now := p.now()
+	switch outcome {
+	case KeyRejected:
+		k.quarantinedUntil = now.Add(p.cfg.Quarantine)
+	case KeyQuotaExceeded:
+		k.quarantinedUntil = nextMonth(now)
+	default:
+		return
+	}
+	slog.Warn("Chave da WeatherAPI em quarentena", "key_id", k.id, "reason", string(outcome),
+		"until", k.quarantinedUntil.Format(time.RFC3339))
+}
+
+// Check falha quando há chaves configuradas, mas nenhuma pode ser usada (verificação de prontidão)
+func (p *KeyPool) Check(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return nil
+	}
+	now := p.now()
+	p.resetPeriod(now)
+	for _, k := range p.keys {
+		if p.available(k, now) {
+			return nil
+		}
+	}
+	return fmt.Errorf("all %d weather API keys are quarantined or out of quota", len(p.keys))
+}
+
+// Stats retorna as estatísticas de cada chave, na ordem do pool
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.resetPeriod(now)
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{
+			ID:           k.id,
+			Available:    p.available(k, now),
+			MonthlyUsage: k.monthlyUsage,
+			Requests:     make(map[KeyOutcome]int64, len(k.requests)),
+		}
+		if now.Before(k.quarantinedUntil) {
+			s.QuarantinedUntil = k.quarantinedUntil
+		}
+		for outcome, count := range k.requests {
+			s.Requests[outcome] = count
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// retryAfter retorna o tempo até a primeira chave indisponível voltar ao pool (chamado com o lock)
+func (p *KeyPool) retryAfter(now time.Time) time.Duration {
+	var wait time.Duration
+	for _, k := range p.keys {
+		if p.available(k, now) {
+			// Disponível, mas já tentada pela requisição
+			return 0
+		}
+		until := nextMonth(now)
+		if now.Before(k.quarantinedUntil) && (p.cfg.MonthlyQuota <= 0 || k.monthlyUsage < p.cfg.MonthlyQuota) {
+			until = k.quarantinedUntil
+		}
+		if d := until.Sub(now); wait == 0 || d < wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+// available informa se a chave está fora da quarentena e dentro da cota mensal
+func (p *KeyPool) available(k *pooledKey, now time.Time) bool {
+	if now.Before(k.quarantinedUntil) {
+		return false
+	}
+	return p.cfg.MonthlyQuota <= 0 || k.monthlyUsage < p.cfg.MonthlyQuota
+}
+
+// resetPeriod zera o uso mensal das chaves na virada do mês (UTC)
+func (p *KeyPool) resetPeriod(now time.Time) {
+	period := now.UTC().Format("2006-01")
+	if period == p.period {
+		return
+	}
+	p.period = period
+	for _, k := range p.keys {
+		k.monthlyUsage = 0
+	}
+}
+
+// find retorna a chave do pool (nil quando não está mais no pool)
+func (p *KeyPool) find(key string) *pooledKey {
+	for _, k := range p.keys {
+		if k.key == key {
+			return k
+		}
+	}
+	return nil
+}
+
+// keyID identifica a chave em logs e métricas sem revelá-la
+func keyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// nextMonth retorna o início do mês seguinte (UTC), quando a cota da WeatherAPI é renovada
+func nextMonth(now time.Time) time.Time {
+	year, month, _ := now.UTC().Date()
+	return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+}
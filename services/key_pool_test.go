@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquireN escolhe n chaves seguidas e devolve a sequência
+func acquireN(t *testing.T, pool *KeyPool, n int) []string {
+	t.Helper()
+	var keys []string
+	for i := 0; i < n; i++ {
+		key, err := pool.Acquire(nil)
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestKeyPool_Acquire_RoundRobin(t *testing.T) {
+	pool := NewKeyPool([]string{"a", " b ", "", "a", "c"}, DefaultKeyPoolConfig())
+	assert.Equal(t, 3, pool.Len())
+	assert.Equal(t, []string{"a", "b", "c", "a"}, acquireN(t, pool, 4))
+
+	// Chaves já tentadas pela requisição são ignoradas
+	key, err := pool.Acquire(map[string]bool{"b": true, "c": true})
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+
+	_, err = pool.Acquire(map[string]bool{"a": true, "b": true, "c": true})
+	assert.ErrorIs(t, err, ErrNoAPIKeyAvailable)
+
+	_, err = NewKeyPool(nil, DefaultKeyPoolConfig()).Acquire(nil)
+	assert.EqualError(t, err, "weather API key not configured")
+}
+
+func TestKeyPool_Acquire_QuotaAware(t *testing.T) {
+	now := time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC)
+	pool := NewKeyPoolWithClock([]string{"a", "b"}, KeyPoolConfig{Strategy: KeySelectionQuotaAware, MonthlyQuota: 2, Quarantine: time.Hour},
+		func() time.Time { return now })
+
+	// A chave adicionada depois tem menos uso no mês e é preferida até alcançar as demais
+	acquireN(t, pool, 4)
+	pool.SetKeys([]string{"a", "b", "c"})
+	assert.Equal(t, []string{"c", "c"}, acquireN(t, pool, 2))
+
+	// Todas atingiram a cota mensal, renovada em uma hora
+	_, err := pool.Acquire(nil)
+	var noKey *NoAPIKeyError
+	require.ErrorAs(t, err, &noKey)
+	assert.Equal(t, time.Hour, noKey.RetryAfter)
+	assert.Error(t, pool.Check(context.Background()))
+
+	// A cota é renovada na virada do mês
+	now = now.Add(2 * time.Hour)
+	assert.NoError(t, pool.Check(context.Background()))
+	for _, stats := range pool.Stats() {
+		assert.Zero(t, stats.MonthlyUsage)
+		assert.True(t, stats.Available)
+	}
+}
+
+func TestKeyPool_Report_Quarantine(t *testing.T) {
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	pool := NewKeyPoolWithClock([]string{"a", "b", "c"}, KeyPoolConfig{Strategy: KeySelectionRoundRobin, Quarantine: 10 * time.Minute},
+		func() time.Time { return now })
+
+	pool.Report("a", KeyRejected)
+	pool.Report("b", KeyQuotaExceeded)
+	pool.Report("c", KeyFailed)
+	pool.Report("removed", KeyRejected)
+	assert.Equal(t, []string{"c", "c"}, acquireN(t, pool, 2))
+
+	stats := pool.Stats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, keyID("a"), stats[0].ID)
+	assert.Equal(t, now.Add(10*time.Minute), stats[0].QuarantinedUntil)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), stats[1].QuarantinedUntil)
+	assert.True(t, stats[2].Available)
+	assert.Equal(t, int64(1), stats[2].Requests[KeyFailed])
+	assert.Equal(t, int64(2), stats[2].MonthlyUsage)
+
+	// A quarentena por recusa termina depois do prazo; a de cota, só no mês seguinte
+	now = now.Add(11 * time.Minute)
+	assert.Equal(t, []string{"a", "c", "a"}, acquireN(t, pool, 3))
+
+	// A chave mantida na rotação preserva a quarentena; a removida sai do pool
+	pool.SetKeys([]string{"b", "d"})
+	assert.Equal(t, []string{"d", "d"}, acquireN(t, pool, 2))
+	pool.Report("d", KeyRejected)
+	assert.EqualError(t, pool.Check(context.Background()), "all 2 weather API keys are quarantined or out of quota")
+
+	// O erro informa quando a primeira chave volta ao pool (fim da quarentena de "d")
+	_, err := pool.Acquire(nil)
+	var noKey *NoAPIKeyError
+	require.ErrorAs(t, err, &noKey)
+	assert.ErrorIs(t, err, ErrNoAPIKeyAvailable)
+	assert.Equal(t, 10*time.Minute, noKey.RetryAfter)
+}
+
+func TestKeyPoolConfigFrom(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	cfg, err := KeyPoolConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKeyPoolConfig(), cfg)
+
+	env["WEATHER_API_KEY_STRATEGY"] = "quota-aware"
+	env["WEATHER_API_KEY_QUOTA"] = "1000000"
+	env["WEATHER_API_KEY_QUARANTINE"] = "15m"
+	cfg, err = KeyPoolConfigFrom(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, KeyPoolConfig{Strategy: KeySelectionQuotaAware, MonthlyQuota: 1000000, Quarantine: 15 * time.Minute}, cfg)
+
+	env["WEATHER_API_KEY_STRATEGY"] = "random"
+	_, err = KeyPoolConfigFrom(getenv)
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"weather-cep-api/cepstore"
+	"weather-cep-api/models"
+	"weather-cep-api/utils"
+)
+
+// OfflineCEPService implementa CEPServiceInterface usando o banco local de CEPs (sem acesso à ViaCEP)
+// Quando o CEP não está no banco a consulta é repassada ao fallback, se configurado
+type OfflineCEPService struct {
+	store    *cepstore.Store
+	fallback atomic.Pointer[cepServiceRef]
+}
+
+// cepServiceRef guarda o fallback para que a troca seja um único ponteiro atômico
+type cepServiceRef struct {
+	service CEPServiceInterface
+}
+
+// NewOfflineCEPService cria o serviço offline; fallback pode ser nil em ambientes sem rede
+func NewOfflineCEPService(store *cepstore.Store, fallback CEPServiceInterface) *OfflineCEPService {
+	s := &OfflineCEPService{store: store}
+	s.SetFallback(fallback)
+	return s
+}
+
+// SetFallback troca o serviço consultado quando o CEP não está no banco (recarga de CEP_OFFLINE_FALLBACK)
+// nil desativa o fallback
+func (s *OfflineCEPService) SetFallback(fallback CEPServiceInterface) {
+	s.fallback.Store(&cepServiceRef{service: fallback})
+}
+
+// GetLocationByCEP consulta informações de localização por CEP no banco local
+func (s *OfflineCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	// Mesmas validações do serviço online
+	if !utils.IsValidCEP(cep) {
+		return nil, fmt.Errorf("invalid zipcode")
+	}
+	prefixUF, ok := utils.UFForCEP(cep)
+	if !ok {
+		return nil, fmt.Errorf("invalid zipcode")
+	}
+
+	record, found := s.store.Get(cep)
+	if !found {
+		if fallback := s.fallback.Load().service; fallback != nil {
+			return fallback.GetLocationByCEP(ctx, cep)
+		}
+		return nil, fmt.Errorf("can not find zipcode")
+	}
+
+	locationInfo := &models.LocationInfo{
+		City:  record.City,
+		State: record.UF,
+		CEP:   utils.FormatCEP(record.CEP),
+		IBGE:  record.IBGE,
+	}
+	if !utils.IsUFConsistentWithCEP(record.CEP, record.UF) {
+		slog.WarnContext(ctx, "UF do banco local difere da faixa do CEP",
+			"uf", record.UF, "cep", locationInfo.CEP, "expected_uf", prefixUF)
+		locationInfo.UFMismatch = true
+	}
+
+	return locationInfo, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"weather-cep-api/cepstore"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock do serviço de CEP usado como fallback
+type MockFallbackCEPService struct {
+	mock.Mock
+}
+
+func (m *MockFallbackCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LocationInfo), args.Error(1)
+}
+
+// newTestCEPStore monta um banco local em memória com os registros informados
+func newTestCEPStore(t *testing.T, records ...cepstore.Record) *cepstore.Store {
+	t.Helper()
+	builder := cepstore.NewBuilder()
+	for _, record := range records {
+		require.NoError(t, builder.Add(record))
+	}
+	var buf bytes.Buffer
+	_, err := builder.WriteTo(&buf)
+	require.NoError(t, err)
+	store, err := cepstore.Load(&buf)
+	require.NoError(t, err)
+	return store
+}
+
+func TestOfflineCEPService_GetLocationByCEP_Success(t *testing.T) {
+	store := newTestCEPStore(t, cepstore.Record{CEP: "01310100", City: "São Paulo", UF: "SP", IBGE: "3550308"})
+	fallback := new(MockFallbackCEPService)
+	service := NewOfflineCEPService(store, fallback)
+
+	result, err := service.GetLocationByCEP(context.Background(), "01310-100")
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100", IBGE: "3550308"}, result)
+	// Encontrado no banco local: a ViaCEP não é consultada
+	fallback.AssertNotCalled(t, "GetLocationByCEP", mock.Anything, mock.Anything)
+}
+
+func TestOfflineCEPService_GetLocationByCEP_Fallback(t *testing.T) {
+	store := newTestCEPStore(t, cepstore.Record{CEP: "01310100", City: "São Paulo", UF: "SP"})
+	fallback := new(MockFallbackCEPService)
+	expected := &models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"}
+	fallback.On("GetLocationByCEP", mock.Anything, "20040-020").Return(expected, nil)
+	service := NewOfflineCEPService(store, fallback)
+
+	result, err := service.GetLocationByCEP(context.Background(), "20040-020")
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	fallback.AssertExpectations(t)
+}
+
+func TestOfflineCEPService_GetLocationByCEP_FallbackError(t *testing.T) {
+	store := newTestCEPStore(t)
+	fallback := new(MockFallbackCEPService)
+	fallback.On("GetLocationByCEP", mock.Anything, "20040020").Return(nil, errors.New("error fetching CEP data: network unreachable"))
+	service := NewOfflineCEPService(store, fallback)
+
+	_, err := service.GetLocationByCEP(context.Background(), "20040020")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error fetching CEP data")
+}
+
+func TestOfflineCEPService_GetLocationByCEP_NotFoundWithoutFallback(t *testing.T) {
+	service := NewOfflineCEPService(newTestCEPStore(t), nil)
+
+	_, err := service.GetLocationByCEP(context.Background(), "20040020")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can not find zipcode")
+}
+
+func TestOfflineCEPService_SetFallback(t *testing.T) {
+	fallback := new(MockFallbackCEPService)
+	expected := &models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"}
+	fallback.On("GetLocationByCEP", mock.Anything, "20040020").Return(expected, nil)
+	service := NewOfflineCEPService(newTestCEPStore(t), nil)
+
+	// O fallback é ativado e desativado sem recriar o serviço (recarga de CEP_OFFLINE_FALLBACK)
+	service.SetFallback(fallback)
+	result, err := service.GetLocationByCEP(context.Background(), "20040020")
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	service.SetFallback(nil)
+	_, err = service.GetLocationByCEP(context.Background(), "20040020")
+	assert.EqualError(t, err, "can not find zipcode")
+	fallback.AssertNumberOfCalls(t, "GetLocationByCEP", 1)
+}
+
+func TestOfflineCEPService_GetLocationByCEP_Invalid(t *testing.T) {
+	fallback := new(MockFallbackCEPService)
+	service := NewOfflineCEPService(newTestCEPStore(t), fallback)
+
+	for _, cep := range []string{"1234", "abcdefgh", "00000-000"} {
+		_, err := service.GetLocationByCEP(context.Background(), cep)
+		require.Error(t, err, cep)
+		assert.Contains(t, err.Error(), "invalid zipcode")
+	}
+	fallback.AssertNotCalled(t, "GetLocationByCEP", mock.Anything, mock.Anything)
+}
+
+func TestOfflineCEPService_GetLocationByCEP_UFMismatch(t *testing.T) {
+	// Registro com UF divergente da faixa dos Correios (01xxx-xxx é SP)
+	store := newTestCEPStore(t, cepstore.Record{CEP: "01310100", City: "São Paulo", UF: "RJ"})
+	service := NewOfflineCEPService(store, nil)
+
+	result, err := service.GetLocationByCEP(context.Background(), "01310100")
+
+	require.NoError(t, err)
+	assert.Equal(t, "RJ", result.State)
+	assert.True(t, result.UFMismatch)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"weather-cep-api/models"
+)
+
+// ErrGeocoderDisabled indica que a geocodificação reversa está desativada (REVERSE_GEOCODER=none)
+var ErrGeocoderDisabled = errors.New("reverse geocoder disabled")
+
+// SwitchableGeocoder repassa as consultas ao geocodificador atual, que pode ser trocado em tempo de
+// execução (recarga de REVERSE_GEOCODER) sem recriar o handler
+type SwitchableGeocoder struct {
+	current atomic.Pointer[geocoderRef]
+}
+
+// geocoderRef guarda a interface para que a troca seja um único ponteiro atômico
+type geocoderRef struct {
+	geocoder ReverseGeocoderInterface
+}
+
+// NewSwitchableGeocoder cria o geocodificador com o provedor inicial (nil desativa a geocodificação)
+func NewSwitchableGeocoder(geocoder ReverseGeocoderInterface) *SwitchableGeocoder {
+	s := &SwitchableGeocoder{}
+	s.Set(geocoder)
+	return s
+}
+
+// Set troca o provedor usado pelas próximas consultas (nil desativa a geocodificação)
+func (s *SwitchableGeocoder) Set(geocoder ReverseGeocoderInterface) {
+	s.current.Store(&geocoderRef{geocoder: geocoder})
+}
+
+// Current retorna o provedor em uso (nil quando desativada)
+func (s *SwitchableGeocoder) Current() ReverseGeocoderInterface {
+	return s.current.Load().geocoder
+}
+
+// ReverseGeocode consulta o provedor em uso
+func (s *SwitchableGeocoder) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.LocationInfo, error) {
+	geocoder := s.Current()
+	if geocoder == nil {
+		return nil, ErrGeocoderDisabled
+	}
+	return geocoder.ReverseGeocode(ctx, lat, lon)
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultWeatherCacheTTL é o tempo padrão que as condições ficam em cache
+const DefaultWeatherCacheTTL = 5 * time.Minute
+
+// weatherCacheNamespace prefixa as chaves das condições de clima no backend de cache
+const weatherCacheNamespace = "weather:v1:"
+
+// WeatherCacheKey normaliza cidade/estado para uso como chave do cache de clima
+func WeatherCacheKey(city, state string) string {
+	return strings.ToLower(strings.TrimSpace(city)) + "|" + strings.ToUpper(strings.TrimSpace(state))
+}
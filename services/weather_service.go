@@ -1,84 +1,252 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"os"
+	"time"
+	"weather-cep-api/cache"
 	"weather-cep-api/models"
 	"weather-cep-api/utils"
 )
 
+// WeatherProviderName identifica o provedor de clima nas respostas
+const WeatherProviderName = "weatherapi"
+
 // WeatherServiceInterface define o contrato para serviços de clima
 type WeatherServiceInterface interface {
-	GetTemperatureByCity(city, state string) (*models.TemperatureResponse, error)
+	GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error)
+	GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error)
+	GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error)
+	GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error)
 }
 
 // WeatherService implementa o serviço de consulta de clima
 type WeatherService struct {
 	httpClient HTTPClientInterface
-	apiKey     string
+	keys       *KeyPool
+	cache      *cache.Store[models.WeatherConditions]
+	now        func() time.Time
 }
 
-// NewWeatherService cria uma nova instância do serviço de clima
-func NewWeatherService() *WeatherService {
-	return &WeatherService{
-		httpClient: &http.Client{},
-		apiKey:     os.Getenv("WEATHER_API_KEY"),
-	}
+// NewWeatherService cria uma nova instância do serviço de clima com a chave da WeatherAPI e o cache padrão em memória
+// A chave e o cache configuráveis vêm do pacote config (ver NewWeatherServiceWithBackend)
+func NewWeatherService(apiKey string) *WeatherService {
+	return NewWeatherServiceWithCache(&http.Client{}, apiKey, DefaultWeatherCacheTTL)
 }
 
 // NewWeatherServiceWithClient cria uma nova instância com HTTP client customizado (para testes)
 func NewWeatherServiceWithClient(client HTTPClientInterface, apiKey string) *WeatherService {
 	return &WeatherService{
 		httpClient: client,
-		apiKey:     apiKey,
+		keys:       NewKeyPool([]string{apiKey}, DefaultKeyPoolConfig()),
+		now:        time.Now,
+	}
+}
+
+// KeyPool retorna o pool de chaves da WeatherAPI (para adicionar chaves, rotacioná-las e expor o uso)
+func (s *WeatherService) KeyPool() *KeyPool {
+	return s.keys
+}
+
+// NewWeatherServiceWithCache cria uma nova instância com cache em memória (ttl <= 0 desativa o cache)
+func NewWeatherServiceWithCache(client HTTPClientInterface, apiKey string, ttl time.Duration) *WeatherService {
+	service := NewWeatherServiceWithClient(client, apiKey)
+	// O backend usa o relógio do serviço para que a expiração acompanhe service.now
+	backend := cache.NewMemoryWithClock(func() time.Time { return service.now() })
+	return service.withCache(backend, ttl)
+}
+
+// NewWeatherServiceWithBackend cria uma nova instância com o backend de cache informado (memória, bolt ou redis)
+// backend nil ou ttl <= 0 desativa o cache
+func NewWeatherServiceWithBackend(client HTTPClientInterface, apiKey string, backend cache.Cache, ttl time.Duration) *WeatherService {
+	return NewWeatherServiceWithClient(client, apiKey).withCache(backend, ttl)
+}
+
+// withCache associa o backend de cache ao serviço
+func (s *WeatherService) withCache(backend cache.Cache, ttl time.Duration) *WeatherService {
+	if backend != nil && ttl > 0 {
+		s.cache = cache.NewStore[models.WeatherConditions](backend, weatherCacheNamespace, ttl)
 	}
+	return s
+}
+
+// CacheStore retorna o cache das condições de clima (nil quando desativado)
+func (s *WeatherService) CacheStore() *cache.Store[models.WeatherConditions] {
+	return s.cache
 }
 
 // GetTemperatureByCity consulta a temperatura atual de uma cidade usando WeatherAPI
-func (s *WeatherService) GetTemperatureByCity(city, state string) (*models.TemperatureResponse, error) {
-	// Verifica se a API key está configurada
-	if s.apiKey == "" {
-		return nil, fmt.Errorf("weather API key not configured")
+func (s *WeatherService) GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error) {
+	conditions, err := s.GetConditionsByCity(ctx, city, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Converte temperaturas para todas as escalas
+	tempC, tempF, tempK := utils.ConvertTemperatures(conditions.TempC)
+
+	// Cria resposta final
+	response := &models.TemperatureResponse{
+		TempC: tempC,
+		TempF: tempF,
+		TempK: tempK,
 	}
 
+	return response, nil
+}
+
+// GetConditionsByCity consulta temperatura, umidade e vento atuais de uma cidade usando WeatherAPI
+func (s *WeatherService) GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error) {
+	return s.GetFreshConditionsByCity(ctx, city, state, 0)
+}
+
+// GetFreshConditionsByCity consulta as condições atuais exigindo que a observação não seja mais
+// antiga que maxAge; se a entrada em cache for mais antiga, o cache é ignorado (maxAge <= 0 aceita qualquer idade)
+func (s *WeatherService) GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error) {
 	// Constrói a query de localização (cidade, estado, Brasil)
-	location := fmt.Sprintf("%s, %s, Brazil", city, state)
-	encodedLocation := url.QueryEscape(location)
+	query := fmt.Sprintf("%s, %s, Brazil", city, state)
+	return s.getConditions(ctx, WeatherCacheKey(city, state), query, maxAge)
+}
+
+// GetConditionsByCoordinates consulta as condições atuais para latitude/longitude usando WeatherAPI
+func (s *WeatherService) GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error) {
+	// Coordenadas com 4 casas decimais (~11 m) para que consultas próximas compartilhem o cache
+	query := fmt.Sprintf("%.4f,%.4f", lat, lon)
+	return s.getConditions(ctx, "coords|"+query, query, 0)
+}
+
+// getConditions consulta o cache e, se necessário, a WeatherAPI para a query informada
+func (s *WeatherService) getConditions(ctx context.Context, key, query string, maxAge time.Duration) (*models.WeatherConditions, error) {
+	status := models.CacheStatusMiss
+
+	if s.cache != nil {
+		cached, ok, err := s.cache.Get(key)
+		if err != nil {
+			// Falha no cache não impede a consulta ao provedor
+			slog.WarnContext(ctx, "Erro ao ler cache de clima", "error", err)
+		}
+		if ok {
+			if maxAge <= 0 || conditionsAge(cached, s.now()) <= maxAge {
+				cached.CacheStatus = models.CacheStatusHit
+				return cached, nil
+			}
+			status = models.CacheStatusBypass
+		}
+	}
+
+	weatherResp, err := s.fetchCurrent(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := &models.WeatherConditions{
+		TempC:     weatherResp.Current.TempC,
+		Humidity:  weatherResp.Current.Humidity,
+		WindKph:   weatherResp.Current.WindKph,
+		TimeZone:  weatherResp.Location.TzID,
+		Provider:  WeatherProviderName,
+		FetchedAt: s.now().UTC(),
+	}
+	if weatherResp.Current.LastUpdatedEpoch > 0 {
+		conditions.ObservedAt = time.Unix(weatherResp.Current.LastUpdatedEpoch, 0).UTC()
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(key, conditions); err != nil {
+			slog.WarnContext(ctx, "Erro ao gravar cache de clima", "error", err)
+		}
+	}
+
+	conditions.CacheStatus = status
+	return conditions, nil
+}
+
+// conditionsAge retorna a idade da observação (ou da consulta, se o provedor não informou o horário)
+func conditionsAge(conditions *models.WeatherConditions, now time.Time) time.Duration {
+	reference := conditions.ObservedAt
+	if reference.IsZero() {
+		reference = conditions.FetchedAt
+	}
+	return now.Sub(reference)
+}
+
+// fetchCurrent consulta as condições atuais da WeatherAPI para a query (cidade ou "lat,lon")
+// Se a chave for recusada ou estiver sem cota, a consulta é repetida com as demais chaves do pool
+func (s *WeatherService) fetchCurrent(ctx context.Context, query string) (*models.WeatherAPIResponse, error) {
+	tried := make(map[string]bool)
+	var lastErr error
+	for {
+		// Escolhe a chave (falha quando nenhuma está configurada ou disponível)
+		apiKey, err := s.keys.Acquire(tried)
+		if err != nil {
+			if lastErr != nil {
+				// Mantém o erro do pool (ErrNoAPIKeyAvailable vira 503) com a causa da última recusa
+				return nil, fmt.Errorf("%w: %v", err, lastErr)
+			}
+			return nil, err
+		}
+		tried[apiKey] = true
+
+		weatherResp, outcome, err := s.fetchCurrentWithKey(ctx, apiKey, query)
+		s.keys.Report(apiKey, outcome)
+		if outcome != KeyRejected && outcome != KeyQuotaExceeded {
+			return weatherResp, err
+		}
+		lastErr = err
+	}
+}
+
+// fetchCurrentWithKey faz a consulta com a chave informada e classifica a resposta para o pool
+func (s *WeatherService) fetchCurrentWithKey(ctx context.Context, apiKey, query string) (*models.WeatherAPIResponse, KeyOutcome, error) {
+	encodedLocation := url.QueryEscape(query)
 
 	// Constrói URL da WeatherAPI
-	weatherURL := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s&aqi=no", 
-		s.apiKey, encodedLocation)
+	weatherURL := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s&aqi=no",
+		apiKey, encodedLocation)
 
 	// Faz a requisição HTTP
-	resp, err := s.httpClient.Get(weatherURL)
+	resp, err := getWithContext(ctx, s.httpClient, weatherURL)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching weather data: %w", err)
+		return nil, KeyFailed, fmt.Errorf("error fetching weather data: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Verifica status da resposta
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error fetching weather data: status %d", resp.StatusCode)
+		return nil, keyOutcome(resp), fmt.Errorf("error fetching weather data: status %d", resp.StatusCode)
 	}
 
 	// Decodifica a resposta JSON
 	var weatherResp models.WeatherAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&weatherResp); err != nil {
-		return nil, fmt.Errorf("error decoding weather response: %w", err)
+		return nil, KeyOK, fmt.Errorf("error decoding weather response: %w", err)
 	}
 
-	// Converte temperaturas para todas as escalas
-	tempC, tempF, tempK := utils.ConvertTemperatures(weatherResp.Current.TempC)
+	return &weatherResp, KeyOK, nil
+}
 
-	// Cria resposta final
-	response := &models.TemperatureResponse{
-		TempC: tempC,
-		TempF: tempF,
-		TempK: tempK,
-	}
+// Códigos de erro da WeatherAPI relacionados à chave (https://www.weatherapi.com/docs/#intro-error-codes)
+const weatherAPIQuotaExceeded = 2007
 
-	return response, nil
-} 
\ No newline at end of file
+// keyOutcome classifica uma resposta de erro da WeatherAPI: 401 e 403 recusam a chave
+// (403 com código 2007 indica cota mensal esgotada) e 5xx não tem relação com a chave
+func keyOutcome(resp *http.Response) KeyOutcome {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return KeyRejected
+	case resp.StatusCode == http.StatusForbidden:
+		var body models.WeatherAPIError
+		if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error.Code == weatherAPIQuotaExceeded {
+			return KeyQuotaExceeded
+		}
+		return KeyRejected
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return KeyFailed
+	default:
+		return KeyOK
+	}
+}
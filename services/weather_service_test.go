@@ -1,12 +1,15 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
+	"weather-cep-api/cache"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,13 +40,13 @@ func TestWeatherService_GetTemperatureByCity_Success(t *testing.T) {
 	// Configura mock do HTTP client
 	mockClient := new(MockHTTPClient)
 	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
-	mockClient.On("Get", expectedURL).Return(resp, nil)
+	mockClient.On("Do", expectedURL).Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
 
 	// Executa o teste
-	result, err := service.GetTemperatureByCity("São Paulo", "SP")
+	result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 	// Assertions
 	require.NoError(t, err)
@@ -61,7 +64,7 @@ func TestWeatherService_GetTemperatureByCity_NoAPIKey(t *testing.T) {
 	service := NewWeatherServiceWithClient(nil, "")
 
 	// Executa o teste
-	result, err := service.GetTemperatureByCity("São Paulo", "SP")
+	result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 	// Assertions
 	assert.Error(t, err)
@@ -69,6 +72,64 @@ func TestWeatherService_GetTemperatureByCity_NoAPIKey(t *testing.T) {
 	assert.Contains(t, err.Error(), "weather API key not configured")
 }
 
+func TestWeatherService_KeyPool_SetKeys(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://api.weatherapi.com/v1/current.json?key=rotated-key&q=Curitiba%2C+PR%2C+Brazil&aqi=no").
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"current": {"temp_c": 18.0}}`)),
+		}, nil)
+
+	// A chave vazia na inicialização pode ser definida depois, sem recriar o serviço
+	service := NewWeatherServiceWithClient(mockClient, "")
+	_, err := service.GetTemperatureByCity(context.Background(), "Curitiba", "PR")
+	require.Error(t, err)
+
+	service.KeyPool().SetKeys([]string{"rotated-key"})
+	result, err := service.GetTemperatureByCity(context.Background(), "Curitiba", "PR")
+	require.NoError(t, err)
+	assert.Equal(t, 18.0, result.TempC)
+	mockClient.AssertExpectations(t)
+}
+
+func TestWeatherService_GetConditionsByCity_KeyFailover(t *testing.T) {
+	const query = "&q=Recife%2C+PE%2C+Brazil&aqi=no"
+	errorResponse := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://api.weatherapi.com/v1/current.json?key=exhausted"+query).
+		Return(errorResponse(http.StatusForbidden, `{"error":{"code":2007,"message":"API key has exceeded calls per month quota."}}`), nil).Once()
+	mockClient.On("Do", "https://api.weatherapi.com/v1/current.json?key=invalid"+query).
+		Return(errorResponse(http.StatusUnauthorized, `{"error":{"code":2006,"message":"API key is invalid."}}`), nil).Once()
+	mockClient.On("Do", "https://api.weatherapi.com/v1/current.json?key=valid"+query).
+		Return(errorResponse(http.StatusOK, `{"current": {"temp_c": 29.0}}`), nil).Once()
+
+	service := NewWeatherServiceWithClient(mockClient, "")
+	service.KeyPool().SetKeys([]string{"exhausted", "invalid", "valid"})
+
+	// As chaves recusadas são trocadas pela próxima do pool na mesma consulta
+	conditions, err := service.GetConditionsByCity(context.Background(), "Recife", "PE")
+	require.NoError(t, err)
+	assert.Equal(t, 29.0, conditions.TempC)
+	mockClient.AssertExpectations(t)
+
+	stats := service.KeyPool().Stats()
+	require.Len(t, stats, 3)
+	assert.False(t, stats[0].Available)
+	assert.Equal(t, int64(1), stats[0].Requests[KeyQuotaExceeded])
+	assert.False(t, stats[1].Available)
+	assert.Equal(t, int64(1), stats[1].Requests[KeyRejected])
+	assert.True(t, stats[2].Available)
+	assert.Equal(t, int64(1), stats[2].Requests[KeyOK])
+
+	// Com todas as chaves em quarentena a consulta falha sem chamar a WeatherAPI
+	service.KeyPool().Report("valid", KeyRejected)
+	_, err = service.GetConditionsByCity(context.Background(), "Manaus", "AM")
+	assert.ErrorIs(t, err, ErrNoAPIKeyAvailable)
+}
+
 func TestWeatherService_GetTemperatureByCity_APIError(t *testing.T) {
 	// Cria resposta HTTP mock de erro 401
 	resp := &http.Response{
@@ -80,13 +141,13 @@ func TestWeatherService_GetTemperatureByCity_APIError(t *testing.T) {
 	// Configura mock do HTTP client
 	mockClient := new(MockHTTPClient)
 	expectedURL := "https://api.weatherapi.com/v1/current.json?key=invalid-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
-	mockClient.On("Get", expectedURL).Return(resp, nil)
+	mockClient.On("Do", expectedURL).Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewWeatherServiceWithClient(mockClient, "invalid-key")
 
 	// Executa o teste
-	result, err := service.GetTemperatureByCity("São Paulo", "SP")
+	result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 	// Assertions
 	assert.Error(t, err)
@@ -112,13 +173,13 @@ func TestWeatherService_GetTemperatureByCity_InvalidJSON(t *testing.T) {
 	// Configura mock do HTTP client
 	mockClient := new(MockHTTPClient)
 	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
-	mockClient.On("Get", expectedURL).Return(resp, nil)
+	mockClient.On("Do", expectedURL).Return(resp, nil)
 
 	// Cria service com HTTP client mockado
 	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
 
 	// Executa o teste
-	result, err := service.GetTemperatureByCity("São Paulo", "SP")
+	result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 	// Assertions
 	assert.Error(t, err)
@@ -133,13 +194,13 @@ func TestWeatherService_GetTemperatureByCity_HTTPError(t *testing.T) {
 	// Configura mock do HTTP client para retornar erro de conexão
 	mockClient := new(MockHTTPClient)
 	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
-	mockClient.On("Get", expectedURL).Return(nil, errors.New("connection error"))
+	mockClient.On("Do", expectedURL).Return(nil, errors.New("connection error"))
 
 	// Cria service com HTTP client mockado
 	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
 
 	// Executa o teste
-	result, err := service.GetTemperatureByCity("São Paulo", "SP")
+	result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 	// Assertions
 	assert.Error(t, err)
@@ -203,13 +264,13 @@ func TestWeatherService_GetTemperatureByCity_DifferentTemperatures(t *testing.T)
 			// Configura mock do HTTP client
 			mockClient := new(MockHTTPClient)
 			expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
-			mockClient.On("Get", expectedURL).Return(resp, nil)
+			mockClient.On("Do", expectedURL).Return(resp, nil)
 
 			// Cria service com HTTP client mockado
 			service := NewWeatherServiceWithClient(mockClient, "test-api-key")
 
 			// Executa o teste
-			result, err := service.GetTemperatureByCity("São Paulo", "SP")
+			result, err := service.GetTemperatureByCity(context.Background(), "São Paulo", "SP")
 
 			// Assertions
 			require.NoError(t, err)
@@ -222,4 +283,179 @@ func TestWeatherService_GetTemperatureByCity_DifferentTemperatures(t *testing.T)
 			mockClient.AssertExpectations(t)
 		})
 	}
-} 
\ No newline at end of file
+} 
+
+func TestWeatherService_GetConditionsByCity_Success(t *testing.T) {
+	// Mock da resposta da WeatherAPI com umidade e vento
+	mockResponse := `{
+		"location": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"},
+		"current": {"temp_c": 28.5, "temp_f": 83.3, "humidity": 65, "wind_kph": 12.2}
+	}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	// Configura mock do HTTP client
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
+	mockClient.On("Do", expectedURL).Return(resp, nil)
+
+	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
+
+	// Executa o teste
+	result, err := service.GetConditionsByCity(context.Background(), "São Paulo", "SP")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, 28.5, result.TempC)
+	assert.Equal(t, 65.0, result.Humidity)
+	assert.Equal(t, 12.2, result.WindKph)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestWeatherService_GetConditionsByCity_NoAPIKey(t *testing.T) {
+	service := NewWeatherServiceWithClient(nil, "")
+
+	result, err := service.GetConditionsByCity(context.Background(), "São Paulo", "SP")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "weather API key not configured")
+}
+
+func TestWeatherService_GetConditionsByCity_ObservationMetadata(t *testing.T) {
+	// Mock da resposta da WeatherAPI com o horário da observação
+	mockResponse := `{
+		"location": {"name": "São Paulo", "region": "Sao Paulo", "country": "Brazil"},
+		"current": {"last_updated_epoch": 1715351400, "temp_c": 22.0}
+	}`
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(mockResponse)),
+		Header:     make(http.Header),
+	}
+
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
+	mockClient.On("Do", expectedURL).Return(resp, nil)
+
+	service := NewWeatherServiceWithClient(mockClient, "test-api-key")
+
+	result, err := service.GetConditionsByCity(context.Background(), "São Paulo", "SP")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1715351400), result.ObservedAt.Unix())
+	assert.Equal(t, WeatherProviderName, result.Provider)
+	assert.Equal(t, "miss", result.CacheStatus)
+}
+
+func TestWeatherService_GetFreshConditionsByCity_Cache(t *testing.T) {
+	observedAt := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	newResponse := func() *http.Response {
+		body := fmt.Sprintf(`{
+			"location": {"name": "São Paulo", "tz_id": "America/Sao_Paulo"},
+			"current": {"last_updated_epoch": %d, "temp_c": 22.0}
+		}`, observedAt.Unix())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	}
+
+	// Configura mock do HTTP client (duas chamadas: a inicial e a que ignora o cache)
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=S%C3%A3o+Paulo%2C+SP%2C+Brazil&aqi=no"
+	mockClient.On("Do", expectedURL).Return(newResponse(), nil).Once()
+	mockClient.On("Do", expectedURL).Return(newResponse(), nil).Once()
+
+	service := NewWeatherServiceWithCache(mockClient, "test-api-key", time.Hour)
+	now := observedAt.Add(10 * time.Minute)
+	service.now = func() time.Time { return now }
+
+	// Primeira consulta vai ao provedor
+	first, err := service.GetFreshConditionsByCity(context.Background(), "São Paulo", "SP", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "miss", first.CacheStatus)
+	assert.Equal(t, "America/Sao_Paulo", first.TimeZone)
+
+	// Segunda consulta (com chave normalizada) vem do cache
+	second, err := service.GetFreshConditionsByCity(context.Background(), " são paulo ", "sp", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "hit", second.CacheStatus)
+
+	// Uma exigência de frescor maior que a idade da observação ignora o cache
+	third, err := service.GetFreshConditionsByCity(context.Background(), "São Paulo", "SP", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "bypass", third.CacheStatus)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestWeatherService_GetFreshConditionsByCity_CacheExpires(t *testing.T) {
+	newResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"current": {"temp_c": 22.0}}`)),
+			Header:     make(http.Header),
+		}
+	}
+
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=Recife%2C+PE%2C+Brazil&aqi=no"
+	mockClient.On("Do", expectedURL).Return(newResponse(), nil).Once()
+	mockClient.On("Do", expectedURL).Return(newResponse(), nil).Once()
+
+	service := NewWeatherServiceWithCache(mockClient, "test-api-key", time.Minute)
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	_, err := service.GetConditionsByCity(context.Background(), "Recife", "PE")
+	require.NoError(t, err)
+
+	// Após o TTL a entrada expira e o provedor é consultado novamente
+	now = now.Add(2 * time.Minute)
+	result, err := service.GetConditionsByCity(context.Background(), "Recife", "PE")
+	require.NoError(t, err)
+	assert.Equal(t, "miss", result.CacheStatus)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestWeatherService_GetConditionsByCity_SharedBackend(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	expectedURL := "https://api.weatherapi.com/v1/current.json?key=test-api-key&q=Recife%2C+PE%2C+Brazil&aqi=no"
+	mockClient.On("Do", expectedURL).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"location": {"tz_id": "America/Recife"}, "current": {"temp_c": 28.5, "humidity": 70}}`)),
+		Header:     make(http.Header),
+	}, nil).Once()
+
+	// Duas instâncias (ex: réplicas) usando o mesmo backend compartilham as entradas
+	backend := cache.NewMemory()
+	first := NewWeatherServiceWithBackend(mockClient, "test-api-key", backend, time.Minute)
+	second := NewWeatherServiceWithBackend(mockClient, "test-api-key", backend, time.Minute)
+
+	result, err := first.GetConditionsByCity(context.Background(), "Recife", "PE")
+	require.NoError(t, err)
+	assert.Equal(t, "miss", result.CacheStatus)
+
+	cached, err := second.GetConditionsByCity(context.Background(), "Recife", "PE")
+	require.NoError(t, err)
+	assert.Equal(t, "hit", cached.CacheStatus)
+	assert.Equal(t, 28.5, cached.TempC)
+	assert.Equal(t, "America/Recife", cached.TimeZone)
+	assert.Equal(t, result.FetchedAt, cached.FetchedAt)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestWeatherService_NewWeatherServiceWithBackend_Disabled(t *testing.T) {
+	assert.Nil(t, NewWeatherServiceWithBackend(new(MockHTTPClient), "key", nil, time.Minute).cache)
+	assert.Nil(t, NewWeatherServiceWithBackend(new(MockHTTPClient), "key", cache.NewMemory(), 0).cache)
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"weather-cep-api/services"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedClient é o decorator de HTTPClientInterface que cria um span por chamada a um provedor
+type TracedClient struct {
+	provider string
+	inner    services.HTTPClientInterface
+}
+
+// InstrumentClient envolve o client HTTP de um provedor com spans de cliente e o cabeçalho traceparent
+func InstrumentClient(provider string, inner services.HTTPClientInterface) *TracedClient {
+	return &TracedClient{
+		provider: provider,
+		inner:    inner,
+	}
+}
+
+// Do executa a requisição dentro de um span filho do contexto da requisição
+// A query string não é registrada, nem no atributo url.full nem na mensagem dos erros de rede
+// (a URL da WeatherAPI contém a chave da API)
+func (c *TracedClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer().Start(req.Context(), fmt.Sprintf("%s %s", req.Method, req.URL.Host),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLFull(req.URL.Scheme+"://"+req.URL.Host+req.URL.Path),
+			attribute.String("peer.service", c.provider),
+		),
+	)
+	defer span.End()
+
+	// Clona a requisição para não alterar os cabeçalhos de quem chamou
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.inner.Do(req)
+	if err != nil {
+		recordError(span, err)
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"weather-cep-api/logging"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedCEPService é o decorator de CEPServiceInterface que cria um span por consulta
+type TracedCEPService struct {
+	inner services.CEPServiceInterface
+}
+
+// NewTracedCEPService envolve o serviço de CEP com spans
+func NewTracedCEPService(inner services.CEPServiceInterface) *TracedCEPService {
+	return &TracedCEPService{inner: inner}
+}
+
+// GetLocationByCEP consulta o serviço decorado dentro do span "CEPService.GetLocationByCEP"
+func (s *TracedCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	ctx, span := tracer().Start(ctx, "CEPService.GetLocationByCEP", trace.WithAttributes(attribute.String("cep", cep)))
+	defer span.End()
+
+	location, err := s.inner.GetLocationByCEP(ctx, cep)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("city", location.City), attribute.String("state", location.State))
+	return location, nil
+}
+
+// TracedWeatherService é o decorator de WeatherServiceInterface que cria um span por consulta
+type TracedWeatherService struct {
+	inner services.WeatherServiceInterface
+}
+
+// NewTracedWeatherService envolve o serviço de clima com spans
+func NewTracedWeatherService(inner services.WeatherServiceInterface) *TracedWeatherService {
+	return &TracedWeatherService{inner: inner}
+}
+
+// GetTemperatureByCity consulta o serviço decorado dentro do span "WeatherService.GetTemperatureByCity"
+func (s *TracedWeatherService) GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error) {
+	ctx, span := tracer().Start(ctx, "WeatherService.GetTemperatureByCity", trace.WithAttributes(cityAttributes(city, state)...))
+	defer span.End()
+
+	temperature, err := s.inner.GetTemperatureByCity(ctx, city, state)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	return temperature, nil
+}
+
+// GetConditionsByCity consulta o serviço decorado dentro do span "WeatherService.GetConditionsByCity"
+func (s *TracedWeatherService) GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error) {
+	ctx, span := tracer().Start(ctx, "WeatherService.GetConditionsByCity", trace.WithAttributes(cityAttributes(city, state)...))
+	defer span.End()
+
+	conditions, err := s.inner.GetConditionsByCity(ctx, city, state)
+	return conditionsResult(span, conditions, err)
+}
+
+// GetFreshConditionsByCity consulta o serviço decorado dentro do span "WeatherService.GetFreshConditionsByCity"
+func (s *TracedWeatherService) GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error) {
+	attributes := append(cityAttributes(city, state), attribute.String("max_age", maxAge.String()))
+	ctx, span := tracer().Start(ctx, "WeatherService.GetFreshConditionsByCity", trace.WithAttributes(attributes...))
+	defer span.End()
+
+	conditions, err := s.inner.GetFreshConditionsByCity(ctx, city, state, maxAge)
+	return conditionsResult(span, conditions, err)
+}
+
+// GetConditionsByCoordinates consulta o serviço decorado dentro do span "WeatherService.GetConditionsByCoordinates"
+func (s *TracedWeatherService) GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error) {
+	ctx, span := tracer().Start(ctx, "WeatherService.GetConditionsByCoordinates",
+		trace.WithAttributes(attribute.Float64("lat", lat), attribute.Float64("lon", lon)))
+	defer span.End()
+
+	conditions, err := s.inner.GetConditionsByCoordinates(ctx, lat, lon)
+	return conditionsResult(span, conditions, err)
+}
+
+// cityAttributes retorna os atributos de span de uma consulta por cidade
+func cityAttributes(city, state string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("city", city), attribute.String("state", state)}
+}
+
+// conditionsResult registra no span o status do cache (hit, miss, bypass) ou o erro da consulta
+func conditionsResult(span trace.Span, conditions *models.WeatherConditions, err error) (*models.WeatherConditions, error) {
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	if conditions.CacheStatus != "" {
+		span.SetAttributes(attribute.String("cache.status", conditions.CacheStatus))
+	}
+	return conditions, nil
+}
+
+// recordError marca o span com o erro da consulta
+// A mensagem passa por logging.Redact: erros de rede (*url.Error) trazem a URL completa, com a chave da API
+func recordError(span trace.Span, err error) {
+	message := logging.Redact(err.Error())
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", err)),
+		semconv.ExceptionMessage(message),
+	))
+	span.SetStatus(codes.Error, message)
+}
@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifica os spans criados pela API
+const TracerName = "weather-cep-api"
+
+// Exporter identifica o destino dos spans
+type Exporter string
+
+const (
+	ExporterNone   Exporter = "none"
+	ExporterOTLP   Exporter = "otlp"
+	ExporterStdout Exporter = "stdout"
+)
+
+// Config descreve o tracing da API
+type Config struct {
+	Exporter    Exporter
+	ServiceName string
+	SampleRatio float64
+}
+
+// Enabled indica se os spans devem ser exportados
+func (c Config) Enabled() bool {
+	return c.Exporter != ExporterNone
+}
+
+// ConfigFrom lê a configuração das variáveis padrão do OpenTelemetry:
+// OTEL_TRACES_EXPORTER (otlp, stdout ou none), OTEL_SERVICE_NAME e OTEL_TRACES_SAMPLER_ARG (proporção de 0 a 1)
+// O endpoint do OTLP é lido pelo próprio exportador (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_HEADERS...)
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := Config{
+		Exporter:    ExporterNone,
+		ServiceName: TracerName,
+		SampleRatio: 1,
+	}
+
+	if raw := strings.TrimSpace(getenv("OTEL_TRACES_EXPORTER")); raw != "" {
+		cfg.Exporter = Exporter(strings.ToLower(raw))
+	}
+	switch cfg.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+	default:
+		return cfg, fmt.Errorf("invalid OTEL_TRACES_EXPORTER: %q (use otlp, stdout or none)", cfg.Exporter)
+	}
+
+	if raw := strings.TrimSpace(getenv("OTEL_SERVICE_NAME")); raw != "" {
+		cfg.ServiceName = raw
+	}
+	if raw := strings.TrimSpace(getenv("OTEL_TRACES_SAMPLER_ARG")); raw != "" {
+		ratio, err := strconv.ParseFloat(raw, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return cfg, fmt.Errorf("invalid OTEL_TRACES_SAMPLER_ARG: %q", raw)
+		}
+		cfg.SampleRatio = ratio
+	}
+	return cfg, nil
+}
+
+// Setup configura o propagador W3C (traceparent) e, se habilitado, o provedor de spans com o exportador
+// Spans em stdout são escritos em w assim que terminam; os do OTLP são enviados em lote
+// A função devolvida descarrega os spans pendentes e deve ser chamada no encerramento
+func Setup(ctx context.Context, cfg Config, w io.Writer) (func(context.Context) error, error) {
+	// O propagador vale mesmo sem exportador: o traceparent recebido é repassado aos provedores
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var processor sdktrace.SpanProcessor
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("error creating stdout exporter: %w", err)
+		}
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+	case ExporterOTLP:
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP exporter: %w", err)
+		}
+		processor = sdktrace.NewBatchSpanProcessor(exporter)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// tracer retorna o tracer da API a partir do provedor global (configurado por Setup)
+func tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
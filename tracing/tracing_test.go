@@ -0,0 +1,339 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MockHTTPClient é um mock do HTTPClientInterface que guarda as requisições enviadas
+type MockHTTPClient struct {
+	mock.Mock
+	sent []*http.Request
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.sent = append(m.sent, req)
+	args := m.Called(req.URL.String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+// MockWeatherService é um mock do WeatherServiceInterface
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error) {
+	args := m.Called(ctx, city, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TemperatureResponse), args.Error(1)
+}
+
+func (m *MockWeatherService) GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state, maxAge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+// setupRecorder instala um provedor que grava os spans em memória e restaura o global ao final do teste
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+// spansByName indexa os spans finalizados pelo nome
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return spans
+}
+
+// attributeValue retorna o valor de um atributo do span ("" se ausente)
+func attributeValue(span sdktrace.ReadOnlySpan, key string) string {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracing_RequestSpanHierarchy(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", "https://viacep.com.br/ws/01310100/json/").Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"cep":"01310-100","localidade":"São Paulo","uf":"SP"}`)),
+	}, nil)
+	cepService := NewTracedCEPService(services.NewCEPServiceWithClient(InstrumentClient("viacep", mockClient)))
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/temperature/:cep", func(c *gin.Context) {
+		location, err := cepService.GetLocationByCEP(c.Request.Context(), c.Param("cep"))
+		require.NoError(t, err)
+		c.JSON(http.StatusOK, location)
+	})
+
+	// Trace iniciado por quem chamou a API
+	const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/temperature/01310100", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := spansByName(recorder)
+	require.Len(t, spans, 3)
+	server, service, client := spans["GET /temperature/:cep"], spans["CEPService.GetLocationByCEP"], spans["GET viacep.com.br"]
+	require.NotNil(t, server)
+	require.NotNil(t, service)
+	require.NotNil(t, client)
+
+	assert.Equal(t, incomingTraceID, server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "/temperature/:cep", attributeValue(server, "http.route"))
+	assert.Equal(t, "200", attributeValue(server, "http.response.status_code"))
+
+	assert.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+	assert.Equal(t, "São Paulo", attributeValue(service, "city"))
+
+	assert.Equal(t, service.SpanContext().SpanID(), client.Parent().SpanID())
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, "viacep", attributeValue(client, "peer.service"))
+
+	// O traceparent enviado à ViaCEP aponta para o span do cliente
+	require.Len(t, mockClient.sent, 1)
+	expected := "00-" + incomingTraceID + "-" + client.SpanContext().SpanID().String() + "-01"
+	assert.Equal(t, expected, mockClient.sent[0].Header.Get("traceparent"))
+}
+
+func TestMiddleware_ServerError(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/temperature/:cep", func(c *gin.Context) {
+		c.String(http.StatusInternalServerError, "error fetching weather data")
+	})
+
+	for _, path := range []string{"/temperature/01310100", "/unknown"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	}
+
+	spans := spansByName(recorder)
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans["GET /temperature/:cep"].Status().Code)
+	// Rotas inexistentes não geram um nome por caminho
+	assert.Equal(t, "404", attributeValue(spans["GET"], "http.response.status_code"))
+	assert.Equal(t, codes.Unset, spans["GET"].Status().Code)
+}
+
+func TestTracedClient_Do(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	const weatherURL = "https://api.weatherapi.com/v1/current.json?key=secret&q=Recife"
+	mockClient := new(MockHTTPClient)
+	mockClient.On("Do", weatherURL).Return(&http.Response{StatusCode: http.StatusForbidden, Body: http.NoBody}, nil).Once()
+	mockClient.On("Do", weatherURL).Return(nil, &url.Error{Op: "Get", URL: weatherURL, Err: errors.New("connection refused")}).Once()
+	client := InstrumentClient("weatherapi", mockClient)
+
+	original := httptest.NewRequest("GET", weatherURL, nil)
+	resp, err := client.Do(original)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	// A requisição de quem chamou não é alterada
+	assert.Empty(t, original.Header.Get("traceparent"))
+
+	_, err = client.Do(httptest.NewRequest("GET", weatherURL, nil))
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "403", attributeValue(spans[0], "http.response.status_code"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+
+	assert.Equal(t, `Get "https://api.weatherapi.com/v1/current.json?key=REDACTED&q=Recife": connection refused`,
+		spans[1].Status().Description)
+
+	// A chave da API (query string) não é registrada, nem nos atributos nem no erro
+	for _, span := range spans {
+		assert.Equal(t, "https://api.weatherapi.com/v1/current.json", attributeValue(span, "url.full"))
+		assert.NotContains(t, span.Status().Description, "secret")
+		for _, kv := range span.Attributes() {
+			assert.NotContains(t, kv.Value.Emit(), "secret")
+		}
+		for _, event := range span.Events() {
+			for _, kv := range event.Attributes {
+				assert.NotContains(t, kv.Value.Emit(), "secret")
+			}
+		}
+	}
+}
+
+func TestTracedWeatherService(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	mockWeather := new(MockWeatherService)
+	mockWeather.On("GetFreshConditionsByCity", mock.Anything, "Recife", "PE", 5*time.Minute).
+		Return(&models.WeatherConditions{TempC: 28, CacheStatus: models.CacheStatusHit}, nil)
+	mockWeather.On("GetTemperatureByCity", mock.Anything, "Recife", "PE").
+		Return(nil, errors.New("error fetching weather data: status 502"))
+	service := NewTracedWeatherService(mockWeather)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	conditions, err := service.GetFreshConditionsByCity(ctx, "Recife", "PE", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 28.0, conditions.TempC)
+	_, err = service.GetTemperatureByCity(ctx, "Recife", "PE")
+	assert.Error(t, err)
+	parent.End()
+
+	spans := spansByName(recorder)
+	fresh := spans["WeatherService.GetFreshConditionsByCity"]
+	require.NotNil(t, fresh)
+	assert.Equal(t, parent.SpanContext().SpanID(), fresh.Parent().SpanID())
+	assert.Equal(t, "hit", attributeValue(fresh, "cache.status"))
+	assert.Equal(t, "5m0s", attributeValue(fresh, "max_age"))
+
+	temperature := spans["WeatherService.GetTemperatureByCity"]
+	require.NotNil(t, temperature)
+	assert.Equal(t, codes.Error, temperature.Status().Code)
+	assert.Equal(t, "error fetching weather data: status 502", temperature.Status().Description)
+
+	// O serviço decorado recebe o contexto com o span filho
+	calledCtx := mockWeather.Calls[0].Arguments.Get(0).(context.Context)
+	assert.Equal(t, fresh.SpanContext().SpanID(), trace.SpanContextFromContext(calledCtx).SpanID())
+}
+
+func TestConfigFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    Config
+		expectError bool
+	}{
+		{
+			name:     "Defaults",
+			env:      map[string]string{},
+			expected: Config{Exporter: ExporterNone, ServiceName: "weather-cep-api", SampleRatio: 1},
+		},
+		{
+			name:     "OTLP with sampling",
+			env:      map[string]string{"OTEL_TRACES_EXPORTER": "OTLP", "OTEL_SERVICE_NAME": "weather-prod", "OTEL_TRACES_SAMPLER_ARG": "0.25"},
+			expected: Config{Exporter: ExporterOTLP, ServiceName: "weather-prod", SampleRatio: 0.25},
+		},
+		{
+			name:        "Unknown exporter",
+			env:         map[string]string{"OTEL_TRACES_EXPORTER": "jaeger"},
+			expectError: true,
+		},
+		{
+			name:        "Invalid ratio",
+			env:         map[string]string{"OTEL_TRACES_SAMPLER_ARG": "2"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ConfigFrom(func(key string) string { return tt.env[key] })
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	setupRecorder(t)
+
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout, ServiceName: "weather-test", SampleRatio: 1}, &out)
+	require.NoError(t, err)
+
+	_, span := tracer().Start(context.Background(), "CEPService.GetLocationByCEP")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, out.String(), `"Name": "CEPService.GetLocationByCEP"`)
+	assert.Contains(t, out.String(), "weather-test")
+}
+
+func TestSetup_None(t *testing.T) {
+	setupRecorder(t)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone}, io.Discard)
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// Sem exportador o traceparent recebido continua sendo repassado
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
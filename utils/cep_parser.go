@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CEPParseMode define o quão tolerante é o parser de CEP
+type CEPParseMode int
+
+const (
+	// CEPParseStrict aceita apenas 12345678 ou 12345-678 (com espaços nas pontas), como IsValidCEP
+	CEPParseStrict CEPParseMode = iota
+	// CEPParseLenient aceita entradas coladas pelo usuário (pontos, espaços, prefixo "CEP", dígitos full-width)
+	CEPParseLenient
+)
+
+// CEPNormalization identifica uma normalização aplicada pelo parser
+type CEPNormalization string
+
+const (
+	NormalizationTrimmedSpace     CEPNormalization = "trimmed_space"
+	NormalizationRemovedPrefix    CEPNormalization = "removed_prefix"
+	NormalizationConvertedDigits  CEPNormalization = "converted_digits"
+	NormalizationRemovedSeparator CEPNormalization = "removed_separators"
+)
+
+// ParsedCEP é o resultado do parser: o CEP canônico (8 dígitos) e as normalizações aplicadas
+type ParsedCEP struct {
+	CEP            string
+	Input          string
+	Normalizations []CEPNormalization
+}
+
+// maxCEPInputLength limita o tamanho da entrada aceita pelo parser tolerante
+const maxCEPInputLength = 64
+
+// cepPrefixRegex reconhece o prefixo "CEP", "C.E.P." e variações, seguido de ":" ou "-"
+var cepPrefixRegex = regexp.MustCompile(`(?i)^c\.?\s*e\.?\s*p\.?\s*[:\-]?\s*`)
+
+// ParseCEP extrai o CEP canônico (8 dígitos) da entrada no modo informado
+func ParseCEP(input string, mode CEPParseMode) (*ParsedCEP, error) {
+	if mode == CEPParseStrict {
+		if !IsValidCEP(input) {
+			return nil, fmt.Errorf("invalid zipcode")
+		}
+		parsed := &ParsedCEP{CEP: NormalizeCEP(input), Input: input}
+		if strings.TrimSpace(input) != input {
+			parsed.Normalizations = append(parsed.Normalizations, NormalizationTrimmedSpace)
+		}
+		return parsed, nil
+	}
+	return parseLenientCEP(input)
+}
+
+// parseLenientCEP implementa o modo tolerante de ParseCEP
+func parseLenientCEP(input string) (*ParsedCEP, error) {
+	if len(input) > maxCEPInputLength {
+		return nil, fmt.Errorf("invalid zipcode")
+	}
+
+	parsed := &ParsedCEP{Input: input}
+	applied := make(map[CEPNormalization]bool)
+	mark := func(n CEPNormalization) {
+		if !applied[n] {
+			applied[n] = true
+			parsed.Normalizations = append(parsed.Normalizations, n)
+		}
+	}
+
+	value := strings.TrimSpace(input)
+	if value != input {
+		mark(NormalizationTrimmedSpace)
+	}
+
+	// Converte dígitos full-width e outras formas de compatibilidade para ASCII
+	if normalized := norm.NFKC.String(value); normalized != value {
+		mark(NormalizationConvertedDigits)
+		value = strings.TrimSpace(normalized)
+	}
+
+	if loc := cepPrefixRegex.FindStringIndex(value); loc != nil && loc[1] > 0 {
+		mark(NormalizationRemovedPrefix)
+		value = value[loc[1]:]
+	}
+
+	var digits strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case unicode.IsDigit(r):
+			// Dígitos de outros sistemas de escrita (ex: árabe-índicos) não são aceitos
+			return nil, fmt.Errorf("invalid zipcode")
+		case r == '.' || r == '/' || unicode.IsSpace(r) || unicode.Is(unicode.Pd, r):
+			// Separadores são descartados
+		default:
+			return nil, fmt.Errorf("invalid zipcode")
+		}
+	}
+
+	if digits.Len() != 8 {
+		return nil, fmt.Errorf("invalid zipcode")
+	}
+	parsed.CEP = digits.String()
+
+	// O hífen na posição padrão (12345-678) não conta como normalização
+	if value != parsed.CEP && value != FormatCEP(parsed.CEP) {
+		mark(NormalizationRemovedSeparator)
+	}
+
+	return parsed, nil
+}
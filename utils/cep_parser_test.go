@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCEP_Strict(t *testing.T) {
+	parsed, err := ParseCEP(" 01310-100 ", CEPParseStrict)
+	require.NoError(t, err)
+	assert.Equal(t, "01310100", parsed.CEP)
+	assert.Equal(t, []CEPNormalization{NormalizationTrimmedSpace}, parsed.Normalizations)
+
+	// O modo estrito continua rejeitando entradas "sujas"
+	for _, input := range []string{"01.310-100", "CEP: 01310100", "01310 100"} {
+		_, err := ParseCEP(input, CEPParseStrict)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseCEP_Lenient(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expected       string
+		normalizations []CEPNormalization
+	}{
+		{
+			name:     "Canonical CEP has no normalization",
+			input:    "01310100",
+			expected: "01310100",
+		},
+		{
+			name:     "Hyphen in standard position has no normalization",
+			input:    "01310-100",
+			expected: "01310100",
+		},
+		{
+			name:           "Dots and hyphen",
+			input:          "01.310-100",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationRemovedSeparator},
+		},
+		{
+			name:           "Inner and outer spaces",
+			input:          " 01310 100 ",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationTrimmedSpace, NormalizationRemovedSeparator},
+		},
+		{
+			name:           "CEP prefix",
+			input:          "CEP: 01310100",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationRemovedPrefix},
+		},
+		{
+			name:           "Dotted lowercase prefix",
+			input:          "c.e.p. 01310-100",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationRemovedPrefix},
+		},
+		{
+			name:           "Full-width digits",
+			input:          "０１３１０－１００",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationConvertedDigits},
+		},
+		{
+			name:           "En dash separator",
+			input:          "01310–100",
+			expected:       "01310100",
+			normalizations: []CEPNormalization{NormalizationRemovedSeparator},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCEP(tt.input, CEPParseLenient)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed.CEP)
+			assert.Equal(t, tt.input, parsed.Input)
+			assert.Equal(t, tt.normalizations, parsed.Normalizations)
+		})
+	}
+}
+
+func TestParseCEP_LenientRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Too few digits", input: "0131010"},
+		{name: "Too many digits", input: "013101000"},
+		{name: "Letters", input: "01310a00"},
+		{name: "Two CEPs", input: "01310100 22070900"},
+		{name: "Arabic-Indic digits", input: "٠١٣١٠١٠٠"},
+		{name: "Empty", input: ""},
+		{name: "Only prefix", input: "CEP:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCEP(tt.input, CEPParseLenient)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// canonicalCEPRegex descreve o único formato que o parser pode devolver
+var canonicalCEPRegex = regexp.MustCompile(`^[0-9]{8}$`)
+
+func FuzzParseCEP(f *testing.F) {
+	for _, seed := range []string{
+		"01310100", "01310-100", "01.310-100", " 01310 100 ", "CEP: 01310100",
+		"０１３１０１００", "c.e.p.-01310100", "013101000", "", "CEP", "٠١٣١٠١٠٠",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		lenient, lenientErr := ParseCEP(input, CEPParseLenient)
+		strict, strictErr := ParseCEP(input, CEPParseStrict)
+
+		if lenientErr == nil {
+			// O resultado é sempre canônico e aceito pelo modo estrito (idempotência)
+			if !canonicalCEPRegex.MatchString(lenient.CEP) {
+				t.Fatalf("non canonical CEP %q from %q", lenient.CEP, input)
+			}
+			again, err := ParseCEP(lenient.CEP, CEPParseStrict)
+			if err != nil || again.CEP != lenient.CEP {
+				t.Fatalf("canonical CEP %q is not stable", lenient.CEP)
+			}
+		}
+
+		// Tudo o que o modo estrito aceita o modo tolerante também aceita, com o mesmo resultado
+		if strictErr == nil {
+			if lenientErr != nil {
+				t.Fatalf("lenient rejected %q accepted by strict mode", input)
+			}
+			if strict.CEP != lenient.CEP {
+				t.Fatalf("strict %q and lenient %q disagree for %q", strict.CEP, lenient.CEP, input)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package utils
+
+import "strconv"
+
+// CEPRange representa uma faixa de CEPs atribuída a uma UF (limites inclusivos, 8 dígitos)
+type CEPRange struct {
+	UF    string
+	Start int
+	End   int
+}
+
+// CEPRanges é a tabela de faixas de CEP por UF dos Correios
+// CEPs abaixo de 01000-000 não pertencem a nenhuma UF
+var CEPRanges = []CEPRange{
+	{UF: "SP", Start: 1000000, End: 19999999},
+	{UF: "RJ", Start: 20000000, End: 28999999},
+	{UF: "ES", Start: 29000000, End: 29999999},
+	{UF: "MG", Start: 30000000, End: 39999999},
+	{UF: "BA", Start: 40000000, End: 48999999},
+	{UF: "SE", Start: 49000000, End: 49999999},
+	{UF: "PE", Start: 50000000, End: 56999999},
+	{UF: "AL", Start: 57000000, End: 57999999},
+	{UF: "PB", Start: 58000000, End: 58999999},
+	{UF: "RN", Start: 59000000, End: 59999999},
+	{UF: "CE", Start: 60000000, End: 63999999},
+	{UF: "PI", Start: 64000000, End: 64999999},
+	{UF: "MA", Start: 65000000, End: 65999999},
+	{UF: "PA", Start: 66000000, End: 68899999},
+	{UF: "AP", Start: 68900000, End: 68999999},
+	{UF: "AM", Start: 69000000, End: 69299999},
+	{UF: "RR", Start: 69300000, End: 69399999},
+	{UF: "AM", Start: 69400000, End: 69899999},
+	{UF: "AC", Start: 69900000, End: 69999999},
+	{UF: "DF", Start: 70000000, End: 72799999},
+	{UF: "GO", Start: 72800000, End: 72999999},
+	{UF: "DF", Start: 73000000, End: 73699999},
+	{UF: "GO", Start: 73700000, End: 76799999},
+	{UF: "RO", Start: 76800000, End: 76999999},
+	{UF: "TO", Start: 77000000, End: 77999999},
+	{UF: "MT", Start: 78000000, End: 78899999},
+	{UF: "RO", Start: 78900000, End: 78999999},
+	{UF: "MS", Start: 79000000, End: 79999999},
+	{UF: "PR", Start: 80000000, End: 87999999},
+	{UF: "SC", Start: 88000000, End: 89999999},
+	{UF: "RS", Start: 90000000, End: 99999999},
+}
+
+// UFForCEP infere a UF pelo prefixo do CEP; retorna false se o CEP for inválido ou estiver fora das faixas
+func UFForCEP(cep string) (string, bool) {
+	if !IsValidCEP(cep) {
+		return "", false
+	}
+	value, err := strconv.Atoi(NormalizeCEP(cep))
+	if err != nil {
+		return "", false
+	}
+
+	for _, r := range CEPRanges {
+		if value >= r.Start && value <= r.End {
+			return r.UF, true
+		}
+	}
+	return "", false
+}
+
+// IsCEPInKnownRange verifica se o CEP pertence a alguma faixa de UF dos Correios
+func IsCEPInKnownRange(cep string) bool {
+	_, ok := UFForCEP(cep)
+	return ok
+}
+
+// IsUFConsistentWithCEP verifica se a UF informada (ex: pela ViaCEP) corresponde à faixa do CEP
+func IsUFConsistentWithCEP(cep, uf string) bool {
+	expected, ok := UFForCEP(cep)
+	return ok && expected == NormalizeUF(uf)
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUFForCEP(t *testing.T) {
+	tests := []struct {
+		name       string
+		cep        string
+		expectedUF string
+		expectedOK bool
+	}{
+		{name: "São Paulo - Avenida Paulista", cep: "01310-100", expectedUF: "SP", expectedOK: true},
+		{name: "Rio de Janeiro - Copacabana", cep: "22070900", expectedUF: "RJ", expectedOK: true},
+		{name: "Brasília - Asa Norte", cep: "70040010", expectedUF: "DF", expectedOK: true},
+		{name: "Entorno do DF pertence a GO", cep: "72800000", expectedUF: "GO", expectedOK: true},
+		{name: "Roraima entre faixas do Amazonas", cep: "69301000", expectedUF: "RR", expectedOK: true},
+		{name: "Upper bound of RS", cep: "99999999", expectedUF: "RS", expectedOK: true},
+		{name: "Zero CEP is out of range", cep: "00000000", expectedOK: false},
+		{name: "Below first range", cep: "00999-999", expectedOK: false},
+		{name: "Invalid format", cep: "0131010", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uf, ok := UFForCEP(tt.cep)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedUF, uf)
+		})
+	}
+}
+
+func TestCEPRanges_CoverAllStates(t *testing.T) {
+	// Toda UF tem ao menos uma faixa e as faixas não se sobrepõem
+	covered := make(map[string]bool)
+	for i, r := range CEPRanges {
+		covered[r.UF] = true
+		assert.True(t, IsValidUF(r.UF), r.UF)
+		assert.LessOrEqual(t, r.Start, r.End)
+		if i > 0 {
+			assert.Greater(t, r.Start, CEPRanges[i-1].End, "ranges must be sorted and disjoint")
+		}
+	}
+	assert.Len(t, covered, 27)
+}
+
+func TestIsUFConsistentWithCEP(t *testing.T) {
+	assert.True(t, IsUFConsistentWithCEP("01310100", "SP"))
+	assert.True(t, IsUFConsistentWithCEP("01310100", "sp"))
+	assert.False(t, IsUFConsistentWithCEP("01310100", "RJ"))
+	assert.False(t, IsUFConsistentWithCEP("00000000", "SP"))
+}
@@ -0,0 +1,127 @@
+package utils
+
+import "math"
+
+// RiskLevel representa a categoria de risco térmico para trabalho ao ar livre
+type RiskLevel string
+
+const (
+	RiskLow            RiskLevel = "low"
+	RiskCaution        RiskLevel = "caution"
+	RiskExtremeCaution RiskLevel = "extreme_caution"
+	RiskDanger         RiskLevel = "danger"
+	RiskExtremeDanger  RiskLevel = "extreme_danger"
+)
+
+// riskOrder permite comparar categorias de risco
+var riskOrder = map[RiskLevel]int{
+	RiskLow:            0,
+	RiskCaution:        1,
+	RiskExtremeCaution: 2,
+	RiskDanger:         3,
+	RiskExtremeDanger:  4,
+}
+
+// fahrenheitToCelsius converte Fahrenheit para Celsius (uso interno das fórmulas em °F)
+func fahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) / 1.8
+}
+
+// HeatIndex calcula o índice de calor (°C) pela regressão de Rothfusz usada pelo NWS
+// Abaixo de 80°F usa a fórmula simplificada de Steadman, como recomenda o NWS
+func HeatIndex(tempC, humidity float64) float64 {
+	t := CelsiusToFahrenheit(tempC)
+	rh := humidity
+
+	simple := 0.5 * (t + 61.0 + (t-68.0)*1.2 + rh*0.094)
+	if (simple+t)/2 < 80 {
+		return fahrenheitToCelsius(simple)
+	}
+
+	hi := -42.379 + 2.04901523*t + 10.14333127*rh -
+		0.22475541*t*rh - 0.00683783*t*t -
+		0.05481717*rh*rh + 0.00122874*t*t*rh +
+		0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+
+	// Ajustes da regressão para umidade muito baixa ou muito alta
+	if rh < 13 && t >= 80 && t <= 112 {
+		hi -= ((13 - rh) / 4) * math.Sqrt((17-math.Abs(t-95))/17)
+	} else if rh > 85 && t >= 80 && t <= 87 {
+		hi += ((rh - 85) / 10) * ((87 - t) / 5)
+	}
+
+	return fahrenheitToCelsius(hi)
+}
+
+// WindChill calcula a sensação térmica pelo vento (°C) pela fórmula de Environment Canada/NWS
+// Fora da faixa de validade (T > 10°C ou vento < 4.8 km/h) retorna a própria temperatura
+func WindChill(tempC, windKph float64) float64 {
+	if tempC > 10 || windKph < 4.8 {
+		return tempC
+	}
+	v := math.Pow(windKph, 0.16)
+	return 13.12 + 0.6215*tempC - 11.37*v + 0.3965*tempC*v
+}
+
+// DewPoint calcula o ponto de orvalho (°C) pela aproximação de Magnus
+func DewPoint(tempC, humidity float64) float64 {
+	const a, b = 17.62, 243.12
+	if humidity <= 0 {
+		humidity = 0.01
+	}
+	gamma := math.Log(humidity/100) + a*tempC/(b+tempC)
+	return b * gamma / (a - gamma)
+}
+
+// Humidex calcula o índice canadense humidex a partir da temperatura e do ponto de orvalho
+func Humidex(tempC, dewPointC float64) float64 {
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(KelvinOffsetExact+dewPointC)))
+	return tempC + 0.5555*(e-10)
+}
+
+// WBGT aproxima o Wet Bulb Globe Temperature (°C) pela fórmula do Australian Bureau of Meteorology
+// A aproximação assume exposição à sombra e vento moderado
+func WBGT(tempC, humidity float64) float64 {
+	e := humidity / 100 * 6.105 * math.Exp(17.27*tempC/(237.7+tempC))
+	return 0.567*tempC + 0.393*e + 3.94
+}
+
+// HeatRisk classifica o índice de calor (°C) nas faixas do NWS
+func HeatRisk(heatIndexC float64) RiskLevel {
+	switch {
+	case heatIndexC >= 54:
+		return RiskExtremeDanger
+	case heatIndexC >= 41:
+		return RiskDanger
+	case heatIndexC >= 32:
+		return RiskExtremeCaution
+	case heatIndexC >= 27:
+		return RiskCaution
+	}
+	return RiskLow
+}
+
+// ColdRisk classifica a sensação térmica pelo vento (°C) nas faixas de Environment Canada
+func ColdRisk(windChillC float64) RiskLevel {
+	switch {
+	case windChillC <= -48:
+		return RiskExtremeDanger
+	case windChillC <= -40:
+		return RiskDanger
+	case windChillC <= -28:
+		return RiskExtremeCaution
+	case windChillC <= -10:
+		return RiskCaution
+	}
+	return RiskLow
+}
+
+// ThermalRisk retorna a categoria mais severa entre o risco por calor e por frio
+func ThermalRisk(heatIndexC, windChillC float64) RiskLevel {
+	heat := HeatRisk(heatIndexC)
+	cold := ColdRisk(windChillC)
+	if riskOrder[cold] > riskOrder[heat] {
+		return cold
+	}
+	return heat
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		tempC    float64
+		humidity float64
+		expected float64
+	}{
+		{
+			name:     "NWS table 90F and 70% humidity is about 106F",
+			tempC:    fahrenheitToCelsius(90),
+			humidity: 70,
+			expected: fahrenheitToCelsius(105.92),
+		},
+		{
+			name:     "Mild temperature uses simple formula",
+			tempC:    20,
+			humidity: 50,
+			expected: 19.36,
+		},
+		{
+			name:     "Very dry air applies low humidity adjustment",
+			tempC:    fahrenheitToCelsius(110),
+			humidity: 10,
+			expected: 40.22,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HeatIndex(tt.tempC, tt.humidity)
+			assert.InDelta(t, tt.expected, result, 0.05)
+		})
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	tests := []struct {
+		name     string
+		tempC    float64
+		windKph  float64
+		expected float64
+	}{
+		{
+			name:     "Cold and windy",
+			tempC:    -10,
+			windKph:  20,
+			expected: -17.86,
+		},
+		{
+			name:     "Above 10C returns the air temperature",
+			tempC:    15,
+			windKph:  30,
+			expected: 15,
+		},
+		{
+			name:     "Calm wind returns the air temperature",
+			tempC:    -5,
+			windKph:  2,
+			expected: -5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := WindChill(tt.tempC, tt.windKph)
+			assert.InDelta(t, tt.expected, result, 0.01)
+		})
+	}
+}
+
+func TestDewPoint(t *testing.T) {
+	assert.InDelta(t, 16.69, DewPoint(25, 60), 0.01)
+	assert.InDelta(t, 25.0, DewPoint(25, 100), 0.01)
+	assert.Less(t, DewPoint(25, 0), -50.0)
+}
+
+func TestHumidex(t *testing.T) {
+	assert.InDelta(t, 33.97, Humidex(30, 15), 0.01)
+}
+
+func TestWBGT(t *testing.T) {
+	assert.InDelta(t, 29.26, WBGT(30, 50), 0.01)
+}
+
+func TestThermalRisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		heatIndex float64
+		windChill float64
+		expected  RiskLevel
+	}{
+		{name: "Comfortable", heatIndex: 22, windChill: 22, expected: RiskLow},
+		{name: "Heat caution", heatIndex: 28, windChill: 28, expected: RiskCaution},
+		{name: "Heat extreme caution", heatIndex: 35, windChill: 30, expected: RiskExtremeCaution},
+		{name: "Heat danger", heatIndex: 45, windChill: 30, expected: RiskDanger},
+		{name: "Heat extreme danger", heatIndex: 60, windChill: 30, expected: RiskExtremeDanger},
+		{name: "Cold caution", heatIndex: -12, windChill: -15, expected: RiskCaution},
+		{name: "Cold danger", heatIndex: -30, windChill: -42, expected: RiskDanger},
+		{name: "Cold extreme danger", heatIndex: -35, windChill: -50, expected: RiskExtremeDanger},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ThermalRisk(tt.heatIndex, tt.windChill))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import "math"
+
+// earthRadiusKm é o raio médio da Terra usado no cálculo de distâncias
+const earthRadiusKm = 6371.0
+
+// IsValidCoordinates verifica se latitude e longitude estão dentro dos limites geográficos
+func IsValidCoordinates(lat, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// HaversineKm calcula a distância (km) entre dois pontos pela fórmula de haversine
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
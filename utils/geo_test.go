@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidCoordinates(t *testing.T) {
+	assert.True(t, IsValidCoordinates(-23.5613, -46.6565))
+	assert.True(t, IsValidCoordinates(90, 180))
+	assert.False(t, IsValidCoordinates(-90.1, 0))
+	assert.False(t, IsValidCoordinates(0, 180.5))
+	assert.False(t, IsValidCoordinates(math.NaN(), 0))
+}
+
+func TestHaversineKm(t *testing.T) {
+	// São Paulo -> Rio de Janeiro (~360 km)
+	assert.InDelta(t, 360, HaversineKm(-23.5505, -46.6333, -22.9068, -43.1729), 5)
+	assert.Equal(t, 0.0, HaversineKm(-23.5, -46.6, -23.5, -46.6))
+}
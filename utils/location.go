@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// BrazilianStates mapeia a sigla (UF) de cada uma das 27 unidades federativas para o seu nome
+var BrazilianStates = map[string]string{
+	"AC": "Acre",
+	"AL": "Alagoas",
+	"AP": "Amapá",
+	"AM": "Amazonas",
+	"BA": "Bahia",
+	"CE": "Ceará",
+	"DF": "Distrito Federal",
+	"ES": "Espírito Santo",
+	"GO": "Goiás",
+	"MA": "Maranhão",
+	"MT": "Mato Grosso",
+	"MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais",
+	"PA": "Pará",
+	"PB": "Paraíba",
+	"PR": "Paraná",
+	"PE": "Pernambuco",
+	"PI": "Piauí",
+	"RJ": "Rio de Janeiro",
+	"RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul",
+	"RO": "Rondônia",
+	"RR": "Roraima",
+	"SC": "Santa Catarina",
+	"SP": "São Paulo",
+	"SE": "Sergipe",
+	"TO": "Tocantins",
+}
+
+// maxCityNameLength limita o tamanho do nome de cidade aceito
+const maxCityNameLength = 100
+
+// cityNameRegex aceita letras, espaços, hífen, apóstrofo e ponto (ex: "Santa Bárbara d'Oeste")
+var cityNameRegex = regexp.MustCompile(`^[\p{L} '.-]+$`)
+
+// NormalizeUF remove espaços e converte a sigla para maiúsculas
+func NormalizeUF(uf string) string {
+	return strings.ToUpper(strings.TrimSpace(uf))
+}
+
+// IsValidUF verifica se a sigla corresponde a uma das 27 unidades federativas
+func IsValidUF(uf string) bool {
+	_, ok := BrazilianStates[NormalizeUF(uf)]
+	return ok
+}
+
+// RemoveAccents remove acentos e diacríticos (ex: "São Paulo" -> "Sao Paulo")
+func RemoveAccents(value string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, value)
+	if err != nil {
+		return value
+	}
+	return result
+}
+
+// NormalizeCityName remove acentos, espaços extras e padroniza a capitalização do nome da cidade
+// Ex: "  SÃO   paulo " -> "Sao Paulo"; preposições ("de", "do", "da"...) ficam em minúsculas
+func NormalizeCityName(city string) string {
+	words := strings.Fields(RemoveAccents(city))
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		if i > 0 && isCityConnector(lower) {
+			words[i] = lower
+			continue
+		}
+		words[i] = capitalize(lower)
+	}
+	return strings.Join(words, " ")
+}
+
+// IsValidCityName verifica se o nome da cidade contém apenas caracteres aceitos
+func IsValidCityName(city string) bool {
+	city = strings.TrimSpace(city)
+	if city == "" || len(city) > maxCityNameLength {
+		return false
+	}
+	return cityNameRegex.MatchString(city)
+}
+
+// isCityConnector identifica preposições que ficam em minúsculas em nomes de cidades
+func isCityConnector(word string) bool {
+	switch word {
+	case "de", "da", "do", "das", "dos", "e":
+		return true
+	}
+	return false
+}
+
+// capitalize coloca a primeira letra em maiúscula, inclusive após hífen (ex: "Embu-Guacu")
+func capitalize(word string) string {
+	runesOfWord := []rune(word)
+	upperNext := true
+	for i, r := range runesOfWord {
+		if upperNext && unicode.IsLetter(r) {
+			runesOfWord[i] = unicode.ToUpper(r)
+			upperNext = false
+			continue
+		}
+		if r == '-' {
+			upperNext = true
+		}
+	}
+	return string(runesOfWord)
+}
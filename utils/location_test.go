@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidUF(t *testing.T) {
+	tests := []struct {
+		name     string
+		uf       string
+		expected bool
+	}{
+		{name: "Uppercase UF", uf: "SP", expected: true},
+		{name: "Lowercase UF", uf: "rj", expected: true},
+		{name: "UF with spaces", uf: " df ", expected: true},
+		{name: "Unknown UF", uf: "XX", expected: false},
+		{name: "Full state name", uf: "Bahia", expected: false},
+		{name: "Empty UF", uf: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidUF(tt.uf))
+		})
+	}
+
+	assert.Len(t, BrazilianStates, 27)
+}
+
+func TestNormalizeCityName(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		expected string
+	}{
+		{name: "Removes accents", city: "São Paulo", expected: "Sao Paulo"},
+		{name: "Uppercase without accents", city: "SAO PAULO", expected: "Sao Paulo"},
+		{name: "Collapses spaces", city: "  rio   de  janeiro ", expected: "Rio de Janeiro"},
+		{name: "Keeps connectors lowercase", city: "CAMPOS DOS GOYTACAZES", expected: "Campos dos Goytacazes"},
+		{name: "Capitalizes after hyphen", city: "embu-guaçu", expected: "Embu-Guacu"},
+		{name: "Cedilla and tilde", city: "florianópolis", expected: "Florianopolis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeCityName(tt.city))
+		})
+	}
+}
+
+func TestIsValidCityName(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		expected bool
+	}{
+		{name: "Simple name", city: "Recife", expected: true},
+		{name: "Name with accents and apostrophe", city: "Santa Bárbara d'Oeste", expected: true},
+		{name: "Name with hyphen", city: "Embu-Guaçu", expected: true},
+		{name: "Digits are rejected", city: "Cidade 123", expected: false},
+		{name: "Symbols are rejected", city: "Recife;DROP", expected: false},
+		{name: "Blank name", city: "   ", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidCityName(tt.city))
+		})
+	}
+}
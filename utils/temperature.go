@@ -1,20 +1,227 @@
 package utils
 
+import (
+	"fmt"
+	"strings"
+)
+
+// ConversionMode define qual offset é usado na conversão Celsius -> Kelvin
+type ConversionMode string
+
+const (
+	// ModeLegacy mantém o contrato original da API (K = C + 273)
+	ModeLegacy ConversionMode = "legacy"
+	// ModeExact usa o offset cientificamente correto (K = C + 273.15)
+	ModeExact ConversionMode = "exact"
+)
+
+const (
+	// KelvinOffsetLegacy é o offset usado pelo contrato original da API
+	KelvinOffsetLegacy = 273.0
+	// KelvinOffsetExact é o offset definido pelo SI
+	KelvinOffsetExact = 273.15
+)
+
+// Scale identifica uma escala de temperatura suportada
+type Scale string
+
+const (
+	ScaleCelsius    Scale = "C"
+	ScaleFahrenheit Scale = "F"
+	ScaleKelvin     Scale = "K"
+	ScaleRankine    Scale = "Ra"
+	ScaleReaumur    Scale = "Re"
+	ScaleDelisle    Scale = "De"
+	ScaleNewton     Scale = "N"
+	ScaleRomer      Scale = "Ro"
+)
+
+// AllScales lista todas as escalas suportadas na ordem em que são apresentadas
+var AllScales = []Scale{
+	ScaleCelsius,
+	ScaleFahrenheit,
+	ScaleKelvin,
+	ScaleRankine,
+	ScaleReaumur,
+	ScaleDelisle,
+	ScaleNewton,
+	ScaleRomer,
+}
+
+// scaleNames mapeia cada escala para o seu nome por extenso
+var scaleNames = map[Scale]string{
+	ScaleCelsius:    "celsius",
+	ScaleFahrenheit: "fahrenheit",
+	ScaleKelvin:     "kelvin",
+	ScaleRankine:    "rankine",
+	ScaleReaumur:    "reaumur",
+	ScaleDelisle:    "delisle",
+	ScaleNewton:     "newton",
+	ScaleRomer:      "romer",
+}
+
+// scaleAliases mapeia nomes aceitos (em minúsculas) para a escala correspondente
+var scaleAliases = map[string]Scale{
+	"c":          ScaleCelsius,
+	"celsius":    ScaleCelsius,
+	"f":          ScaleFahrenheit,
+	"fahrenheit": ScaleFahrenheit,
+	"k":          ScaleKelvin,
+	"kelvin":     ScaleKelvin,
+	"ra":         ScaleRankine,
+	"rankine":    ScaleRankine,
+	"re":         ScaleReaumur,
+	"ré":         ScaleReaumur,
+	"reaumur":    ScaleReaumur,
+	"réaumur":    ScaleReaumur,
+	"de":         ScaleDelisle,
+	"delisle":    ScaleDelisle,
+	"n":          ScaleNewton,
+	"newton":     ScaleNewton,
+	"ro":         ScaleRomer,
+	"rø":         ScaleRomer,
+	"romer":      ScaleRomer,
+	"rømer":      ScaleRomer,
+}
+
+// ScaleName retorna o nome por extenso da escala (vazio se não for suportada)
+func ScaleName(scale Scale) string {
+	return scaleNames[scale]
+}
+
 // CelsiusToFahrenheit converte temperatura de Celsius para Fahrenheit
 // Fórmula: F = C * 1.8 + 32
 func CelsiusToFahrenheit(celsius float64) float64 {
 	return celsius*1.8 + 32
 }
 
-// CelsiusToKelvin converte temperatura de Celsius para Kelvin  
+// CelsiusToKelvin converte temperatura de Celsius para Kelvin
 // Fórmula: K = C + 273
 func CelsiusToKelvin(celsius float64) float64 {
-	return celsius + 273
+	return celsius + KelvinOffsetLegacy
+}
+
+// CelsiusToKelvinExact converte temperatura de Celsius para Kelvin usando o offset do SI
+// Fórmula: K = C + 273.15
+func CelsiusToKelvinExact(celsius float64) float64 {
+	return celsius + KelvinOffsetExact
+}
+
+// CelsiusToKelvinWithMode converte Celsius para Kelvin respeitando o modo de conversão
+func CelsiusToKelvinWithMode(celsius float64, mode ConversionMode) float64 {
+	if mode == ModeExact {
+		return CelsiusToKelvinExact(celsius)
+	}
+	return CelsiusToKelvin(celsius)
+}
+
+// CelsiusToRankine converte temperatura de Celsius para Rankine
+// Fórmula: Ra = (C + 273.15) * 1.8
+func CelsiusToRankine(celsius float64) float64 {
+	return (celsius + KelvinOffsetExact) * 1.8
+}
+
+// CelsiusToReaumur converte temperatura de Celsius para Réaumur
+// Fórmula: Ré = C * 0.8
+func CelsiusToReaumur(celsius float64) float64 {
+	return celsius * 0.8
+}
+
+// CelsiusToDelisle converte temperatura de Celsius para Delisle
+// Fórmula: De = (100 - C) * 1.5
+func CelsiusToDelisle(celsius float64) float64 {
+	return (100 - celsius) * 1.5
+}
+
+// CelsiusToNewton converte temperatura de Celsius para Newton
+// Fórmula: N = C * 0.33
+func CelsiusToNewton(celsius float64) float64 {
+	return celsius * 0.33
+}
+
+// CelsiusToRomer converte temperatura de Celsius para Rømer
+// Fórmula: Rø = C * 21/40 + 7.5
+func CelsiusToRomer(celsius float64) float64 {
+	return celsius*21/40 + 7.5
 }
 
 // ConvertTemperatures converte uma temperatura em Celsius para todas as escalas
 func ConvertTemperatures(celsius float64) (float64, float64, float64) {
+	return ConvertTemperaturesWithMode(celsius, ModeLegacy)
+}
+
+// ConvertTemperaturesWithMode converte Celsius para Celsius, Fahrenheit e Kelvin no modo informado
+func ConvertTemperaturesWithMode(celsius float64, mode ConversionMode) (float64, float64, float64) {
 	fahrenheit := CelsiusToFahrenheit(celsius)
-	kelvin := CelsiusToKelvin(celsius)
+	kelvin := CelsiusToKelvinWithMode(celsius, mode)
 	return celsius, fahrenheit, kelvin
-} 
\ No newline at end of file
+}
+
+// ConvertToScale converte uma temperatura em Celsius para a escala informada
+// O modo só afeta a escala Kelvin; Rankine é sempre calculada a partir do zero absoluto exato
+func ConvertToScale(celsius float64, scale Scale, mode ConversionMode) (float64, error) {
+	switch scale {
+	case ScaleCelsius:
+		return celsius, nil
+	case ScaleFahrenheit:
+		return CelsiusToFahrenheit(celsius), nil
+	case ScaleKelvin:
+		return CelsiusToKelvinWithMode(celsius, mode), nil
+	case ScaleRankine:
+		return CelsiusToRankine(celsius), nil
+	case ScaleReaumur:
+		return CelsiusToReaumur(celsius), nil
+	case ScaleDelisle:
+		return CelsiusToDelisle(celsius), nil
+	case ScaleNewton:
+		return CelsiusToNewton(celsius), nil
+	case ScaleRomer:
+		return CelsiusToRomer(celsius), nil
+	}
+	return 0, fmt.Errorf("unsupported scale: %s", scale)
+}
+
+// ParseConversionMode interpreta o modo de conversão (vazio equivale a legacy)
+func ParseConversionMode(value string) (ConversionMode, error) {
+	switch ConversionMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ModeLegacy:
+		return ModeLegacy, nil
+	case ModeExact:
+		return ModeExact, nil
+	}
+	return "", fmt.Errorf("invalid conversion mode: %s", value)
+}
+
+// ParseScales interpreta uma lista de escalas separadas por vírgula (ex: "C,K,Ra")
+// O valor "all" seleciona todas as escalas; duplicatas são ignoradas
+func ParseScales(value string) ([]Scale, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("no scales informed")
+	}
+	if strings.EqualFold(value, "all") {
+		return append([]Scale(nil), AllScales...), nil
+	}
+
+	seen := make(map[Scale]bool)
+	var scales []Scale
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		scale, ok := scaleAliases[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported scale: %s", strings.TrimSpace(part))
+		}
+		if !seen[scale] {
+			seen[scale] = true
+			scales = append(scales, scale)
+		}
+	}
+
+	if len(scales) == 0 {
+		return nil, fmt.Errorf("no scales informed")
+	}
+	return scales, nil
+}
@@ -117,4 +117,137 @@ func TestConvertTemperatures(t *testing.T) {
 			assert.Equal(t, tt.expectedK, k)
 		})
 	}
-} 
\ No newline at end of file
+} 
+
+func TestCelsiusToKelvinWithMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		celsius  float64
+		mode     ConversionMode
+		expected float64
+	}{
+		{
+			name:     "Legacy mode keeps +273 offset",
+			celsius:  25.0,
+			mode:     ModeLegacy,
+			expected: 298.0,
+		},
+		{
+			name:     "Exact mode uses +273.15 offset",
+			celsius:  25.0,
+			mode:     ModeExact,
+			expected: 298.15,
+		},
+		{
+			name:     "Exact mode at absolute zero",
+			celsius:  -273.15,
+			mode:     ModeExact,
+			expected: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CelsiusToKelvinWithMode(tt.celsius, tt.mode)
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+}
+
+func TestConvertToScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		celsius  float64
+		scale    Scale
+		expected float64
+	}{
+		{name: "Rankine at water freezing point", celsius: 0, scale: ScaleRankine, expected: 491.67},
+		{name: "Rankine at water boiling point", celsius: 100, scale: ScaleRankine, expected: 671.67},
+		{name: "Reaumur at water boiling point", celsius: 100, scale: ScaleReaumur, expected: 80},
+		{name: "Delisle at water freezing point", celsius: 0, scale: ScaleDelisle, expected: 150},
+		{name: "Delisle at water boiling point", celsius: 100, scale: ScaleDelisle, expected: 0},
+		{name: "Newton at water boiling point", celsius: 100, scale: ScaleNewton, expected: 33},
+		{name: "Romer at water freezing point", celsius: 0, scale: ScaleRomer, expected: 7.5},
+		{name: "Romer at water boiling point", celsius: 100, scale: ScaleRomer, expected: 60},
+		{name: "Celsius is returned unchanged", celsius: 21.5, scale: ScaleCelsius, expected: 21.5},
+		{name: "Fahrenheit at 25 Celsius", celsius: 25, scale: ScaleFahrenheit, expected: 77},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertToScale(tt.celsius, tt.scale, ModeExact)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+
+	t.Run("Unsupported scale returns error", func(t *testing.T) {
+		_, err := ConvertToScale(10, Scale("X"), ModeExact)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseScales(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []Scale
+		wantErr  bool
+	}{
+		{
+			name:     "Short codes",
+			value:    "C,K,Ra",
+			expected: []Scale{ScaleCelsius, ScaleKelvin, ScaleRankine},
+		},
+		{
+			name:     "Full names with accents and mixed case",
+			value:    "Réaumur, Rømer ,newton",
+			expected: []Scale{ScaleReaumur, ScaleRomer, ScaleNewton},
+		},
+		{
+			name:     "Duplicates are ignored",
+			value:    "k,kelvin,K",
+			expected: []Scale{ScaleKelvin},
+		},
+		{
+			name:     "All selects every scale",
+			value:    "all",
+			expected: AllScales,
+		},
+		{
+			name:    "Unknown scale",
+			value:   "C,X",
+			wantErr: true,
+		},
+		{
+			name:    "Empty value",
+			value:   " , ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseScales(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseConversionMode(t *testing.T) {
+	mode, err := ParseConversionMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeLegacy, mode)
+
+	mode, err = ParseConversionMode("EXACT")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeExact, mode)
+
+	_, err = ParseConversionMode("precise")
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+// Package warmup pré-aquece os caches de CEP e clima com os CEPs mais consultados
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"weather-cep-api/models"
+	"weather-cep-api/services"
+	"weather-cep-api/utils"
+)
+
+// City identifica uma cidade pelo nome e UF
+type City struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// cityKey normaliza a cidade para contagem (mesma normalização da chave do cache de clima)
+func cityKey(name, state string) string {
+	return strings.ToLower(strings.TrimSpace(name)) + "|" + strings.ToUpper(strings.TrimSpace(state))
+}
+
+// cityCount acumula os acessos de uma cidade
+type cityCount struct {
+	City  City  `json:"city"`
+	Count int64 `json:"count"`
+}
+
+// Stats registra quantas vezes cada CEP e cada cidade foram consultados
+type Stats struct {
+	mu     sync.Mutex
+	ceps   map[string]int64
+	cities map[string]*cityCount
+}
+
+// statsFile é o formato persistido das estatísticas
+type statsFile struct {
+	CEPs   map[string]int64 `json:"ceps"`
+	Cities []cityCount      `json:"cities"`
+}
+
+// NewStats cria estatísticas vazias
+func NewStats() *Stats {
+	return &Stats{
+		ceps:   make(map[string]int64),
+		cities: make(map[string]*cityCount),
+	}
+}
+
+// Record contabiliza uma consulta bem-sucedida
+func (s *Stats) Record(location *models.LocationInfo) {
+	if location == nil {
+		return
+	}
+	cep := utils.NormalizeCEP(location.CEP)
+	key := cityKey(location.City, location.State)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cep != "" {
+		s.ceps[cep]++
+	}
+	if location.City != "" {
+		entry, ok := s.cities[key]
+		if !ok {
+			entry = &cityCount{City: City{Name: location.City, State: location.State}}
+			s.cities[key] = entry
+		}
+		entry.Count++
+	}
+}
+
+// TopCEPs retorna os n CEPs mais consultados (n <= 0 retorna todos)
+func (s *Stats) TopCEPs(n int) []string {
+	s.mu.Lock()
+	ceps := make([]string, 0, len(s.ceps))
+	counts := make(map[string]int64, len(s.ceps))
+	for cep, count := range s.ceps {
+		ceps = append(ceps, cep)
+		counts[cep] = count
+	}
+	s.mu.Unlock()
+
+	// Desempate pelo CEP para que a ordem seja estável
+	sort.Slice(ceps, func(i, j int) bool {
+		if counts[ceps[i]] != counts[ceps[j]] {
+			return counts[ceps[i]] > counts[ceps[j]]
+		}
+		return ceps[i] < ceps[j]
+	})
+	if n > 0 && len(ceps) > n {
+		ceps = ceps[:n]
+	}
+	return ceps
+}
+
+// TopCities retorna as n cidades mais consultadas (n <= 0 retorna todas)
+func (s *Stats) TopCities(n int) []City {
+	s.mu.Lock()
+	entries := make([]cityCount, 0, len(s.cities))
+	for _, entry := range s.cities {
+		entries = append(entries, *entry)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return cityKey(entries[i].City.Name, entries[i].City.State) < cityKey(entries[j].City.Name, entries[j].City.State)
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+
+	cities := make([]City, len(entries))
+	for i, entry := range entries {
+		cities[i] = entry.City
+	}
+	return cities
+}
+
+// LoadStats lê as estatísticas gravadas por Save (arquivo inexistente resulta em estatísticas vazias)
+func LoadStats(path string) (*Stats, error) {
+	stats := NewStats()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading access stats: %w", err)
+	}
+
+	var file statsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error decoding access stats: %w", err)
+	}
+	for cep, count := range file.CEPs {
+		if utils.IsValidCEP(cep) && count > 0 {
+			stats.ceps[utils.NormalizeCEP(cep)] += count
+		}
+	}
+	for _, entry := range file.Cities {
+		if entry.City.Name == "" || entry.Count <= 0 {
+			continue
+		}
+		entry := entry
+		stats.cities[cityKey(entry.City.Name, entry.City.State)] = &entry
+	}
+	return stats, nil
+}
+
+// Save grava as estatísticas de forma atômica (arquivo temporário + rename)
+func (s *Stats) Save(path string) error {
+	s.mu.Lock()
+	file := statsFile{CEPs: make(map[string]int64, len(s.ceps))}
+	for cep, count := range s.ceps {
+		file.CEPs[cep] = count
+	}
+	for _, entry := range s.cities {
+		file.Cities = append(file.Cities, *entry)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(file.Cities, func(i, j int) bool { return file.Cities[i].Count > file.Cities[j].Count })
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error encoding access stats: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".stats-*")
+	if err != nil {
+		return fmt.Errorf("error writing access stats: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing access stats: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing access stats: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing access stats: %w", err)
+	}
+	return nil
+}
+
+// RecordingCEPService decora um CEPServiceInterface registrando as consultas bem-sucedidas nas estatísticas
+type RecordingCEPService struct {
+	inner services.CEPServiceInterface
+	stats *Stats
+}
+
+// NewRecordingCEPService cria o decorador de estatísticas
+func NewRecordingCEPService(inner services.CEPServiceInterface, stats *Stats) *RecordingCEPService {
+	return &RecordingCEPService{
+		inner: inner,
+		stats: stats,
+	}
+}
+
+// GetLocationByCEP consulta o serviço decorado e contabiliza o acesso
+func (r *RecordingCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	location, err := r.inner.GetLocationByCEP(ctx, cep)
+	if err == nil {
+		r.stats.Record(location)
+	}
+	return location, err
+}
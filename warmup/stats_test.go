@@ -0,0 +1,82 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_TopCEPsAndCities(t *testing.T) {
+	stats := NewStats()
+	for i := 0; i < 3; i++ {
+		stats.Record(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"})
+	}
+	stats.Record(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01001-000"})
+	stats.Record(&models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"})
+	stats.Record(&models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"})
+	stats.Record(nil)
+
+	assert.Equal(t, []string{"01310100", "20040020", "01001000"}, stats.TopCEPs(0))
+	assert.Equal(t, []string{"01310100", "20040020"}, stats.TopCEPs(2))
+
+	// Os acessos de CEPs diferentes da mesma cidade são somados
+	assert.Equal(t, []City{{Name: "São Paulo", State: "SP"}, {Name: "Rio de Janeiro", State: "RJ"}}, stats.TopCities(0))
+	assert.Equal(t, []City{{Name: "São Paulo", State: "SP"}}, stats.TopCities(1))
+}
+
+func TestStats_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	stats := NewStats()
+	stats.Record(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"})
+	stats.Record(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"})
+	stats.Record(&models.LocationInfo{City: "Manaus", State: "AM", CEP: "69005-070"})
+	require.NoError(t, stats.Save(path))
+
+	loaded, err := LoadStats(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"50030230", "69005070"}, loaded.TopCEPs(0))
+	assert.Equal(t, []City{{Name: "Recife", State: "PE"}, {Name: "Manaus", State: "AM"}}, loaded.TopCities(0))
+
+	// Novos acessos somam aos carregados
+	loaded.Record(&models.LocationInfo{City: "Manaus", State: "AM", CEP: "69005-070"})
+	loaded.Record(&models.LocationInfo{City: "Manaus", State: "AM", CEP: "69005-070"})
+	assert.Equal(t, "69005070", loaded.TopCEPs(1)[0])
+}
+
+func TestLoadStats_MissingAndInvalid(t *testing.T) {
+	stats, err := LoadStats(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, stats.TopCEPs(0))
+
+	path := filepath.Join(t.TempDir(), "stats.json")
+	require.NoError(t, os.WriteFile(path, []byte("{invalid"), 0o600))
+	_, err = LoadStats(path)
+	assert.Error(t, err)
+}
+
+func TestRecordingCEPService(t *testing.T) {
+	inner := new(MockCEPService)
+	inner.On("GetLocationByCEP", mock.Anything, "01310100").Return(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}, nil)
+	inner.On("GetLocationByCEP", mock.Anything, "99999999").Return(nil, errors.New("can not find zipcode"))
+
+	stats := NewStats()
+	service := NewRecordingCEPService(inner, stats)
+
+	location, err := service.GetLocationByCEP(context.Background(), "01310100")
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", location.City)
+
+	// Falhas não contam como acesso
+	_, err = service.GetLocationByCEP(context.Background(), "99999999")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"01310100"}, stats.TopCEPs(0))
+}
@@ -0,0 +1,410 @@
+package warmup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weather-cep-api/services"
+	"weather-cep-api/utils"
+)
+
+// Config descreve o pré-aquecimento dos caches
+type Config struct {
+	// CEPFile é uma lista de CEPs (um por linha, "#" inicia comentário)
+	CEPFile string
+	// StatsFile guarda as estatísticas de acesso entre execuções
+	StatsFile string
+	// Top é quantos CEPs mais consultados das estatísticas entram no pré-aquecimento
+	Top int
+	// Concurrency é o número de consultas simultâneas
+	Concurrency int
+	// Rate limita as consultas por segundo (<= 0 sem limite)
+	Rate float64
+	// Timeout limita a duração do pré-aquecimento na inicialização
+	Timeout time.Duration
+	// RefreshInterval é o intervalo de atualização das cidades mais consultadas (0 desativa)
+	RefreshInterval time.Duration
+	// RefreshTop é quantas cidades são atualizadas a cada intervalo
+	RefreshTop int
+}
+
+// Enabled indica se há uma fonte de CEPs para o pré-aquecimento
+func (c Config) Enabled() bool {
+	return c.CEPFile != "" || c.StatsFile != ""
+}
+
+// DefaultConfig retorna a configuração padrão (sem fontes de CEP, portanto desativada)
+func DefaultConfig() Config {
+	return Config{
+		Top:             100,
+		Concurrency:     4,
+		Rate:            5,
+		Timeout:         30 * time.Second,
+		RefreshInterval: 4 * time.Minute,
+		RefreshTop:      20,
+	}
+}
+
+// ConfigFrom lê a configuração das variáveis WARMUP_*
+func ConfigFrom(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	cfg.CEPFile = getenv("WARMUP_FILE")
+	cfg.StatsFile = getenv("WARMUP_STATS_FILE")
+
+	var err error
+	if cfg.Top, err = envInt(getenv, "WARMUP_TOP", cfg.Top); err != nil {
+		return cfg, err
+	}
+	if cfg.Concurrency, err = envInt(getenv, "WARMUP_CONCURRENCY", cfg.Concurrency); err != nil {
+		return cfg, err
+	}
+	if cfg.Concurrency < 1 {
+		return cfg, fmt.Errorf("invalid WARMUP_CONCURRENCY: must be at least 1")
+	}
+	if cfg.RefreshTop, err = envInt(getenv, "WARMUP_REFRESH_TOP", cfg.RefreshTop); err != nil {
+		return cfg, err
+	}
+	if raw := strings.TrimSpace(getenv("WARMUP_RATE")); raw != "" {
+		if cfg.Rate, err = strconv.ParseFloat(raw, 64); err != nil {
+			return cfg, fmt.Errorf("invalid WARMUP_RATE: %q", raw)
+		}
+	}
+	if cfg.Timeout, err = envDuration(getenv, "WARMUP_TIMEOUT", cfg.Timeout); err != nil {
+		return cfg, err
+	}
+	if cfg.RefreshInterval, err = envDuration(getenv, "WARMUP_REFRESH_INTERVAL", cfg.RefreshInterval); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// envInt lê um inteiro não negativo de uma variável
+func envInt(getenv func(string) string, name string, fallback int) (int, error) {
+	raw := strings.TrimSpace(getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return fallback, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+	return value, nil
+}
+
+// envDuration lê uma duração não negativa de uma variável
+func envDuration(getenv func(string) string, name string, fallback time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value < 0 {
+		return fallback, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+	return value, nil
+}
+
+// LoadCEPFile lê uma lista de CEPs: um por linha (ou na primeira coluna de um CSV), "#" inicia comentário
+func LoadCEPFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening warm-up file: %w", err)
+	}
+	defer file.Close()
+
+	var ceps []string
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		if i := strings.IndexAny(text, ",;"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || (line == 1 && strings.EqualFold(text, "cep")) {
+			continue
+		}
+		if !utils.IsValidCEP(text) {
+			return nil, fmt.Errorf("warm-up file line %d: invalid zipcode %q", line, text)
+		}
+		ceps = append(ceps, utils.NormalizeCEP(text))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading warm-up file: %w", err)
+	}
+	return ceps, nil
+}
+
+// Result resume uma execução do pré-aquecimento ou da atualização
+type Result struct {
+	CEPs     int
+	Cities   int
+	Failed   int
+	Duration time.Duration
+}
+
+// Warmer consulta CEPs e cidades pelos serviços para popular os caches
+type Warmer struct {
+	cepService     services.CEPServiceInterface
+	weatherService services.WeatherServiceInterface
+	stats          *Stats
+	cfg            Config
+	ready          atomic.Bool
+
+	mu     sync.Mutex
+	warmed []City
+}
+
+// NewWarmer cria o pré-aquecedor
+// cepService não deve ser o RecordingCEPService, para que o pré-aquecimento não conte como acesso
+func NewWarmer(cepService services.CEPServiceInterface, weatherService services.WeatherServiceInterface, stats *Stats, cfg Config) *Warmer {
+	if stats == nil {
+		stats = NewStats()
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return &Warmer{
+		cepService:     cepService,
+		weatherService: weatherService,
+		stats:          stats,
+		cfg:            cfg,
+	}
+}
+
+// Ready indica se o pré-aquecimento inicial terminou (com sucesso, falhas parciais ou timeout)
+func (w *Warmer) Ready() bool {
+	return w.ready.Load()
+}
+
+// Targets monta a lista de CEPs do pré-aquecimento: o arquivo seguido dos mais consultados, sem repetições
+func (w *Warmer) Targets() ([]string, error) {
+	var ceps []string
+	if w.cfg.CEPFile != "" {
+		fromFile, err := LoadCEPFile(w.cfg.CEPFile)
+		if err != nil {
+			return nil, err
+		}
+		ceps = append(ceps, fromFile...)
+	}
+	if w.cfg.Top > 0 {
+		ceps = append(ceps, w.stats.TopCEPs(w.cfg.Top)...)
+	}
+
+	seen := make(map[string]bool, len(ceps))
+	unique := ceps[:0]
+	for _, cep := range ceps {
+		if !seen[cep] {
+			seen[cep] = true
+			unique = append(unique, cep)
+		}
+	}
+	return unique, nil
+}
+
+// Run executa o pré-aquecimento: resolve os CEPs e depois consulta o clima de cada cidade uma única vez
+// Falhas individuais são contadas e registradas; ao final (ou no cancelamento de ctx) o Warmer fica pronto
+func (w *Warmer) Run(ctx context.Context) (Result, error) {
+	defer w.ready.Store(true)
+	start := time.Now()
+
+	ceps, err := w.Targets()
+	if err != nil {
+		return Result{}, err
+	}
+
+	limiter := newRateLimiter(w.cfg.Rate)
+	defer limiter.stop()
+
+	var mu sync.Mutex
+	var result Result
+	var cities []City
+	seen := make(map[string]bool)
+
+	forEach(ctx, w.cfg.Concurrency, limiter, ceps, func(cep string) {
+		location, err := w.cepService.GetLocationByCEP(ctx, cep)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.WarnContext(ctx, "Pré-aquecimento do CEP falhou", "cep", cep, "error", err)
+			result.Failed++
+			return
+		}
+		result.CEPs++
+		if key := cityKey(location.City, location.State); !seen[key] {
+			seen[key] = true
+			cities = append(cities, City{Name: location.City, State: location.State})
+		}
+	})
+
+	forEach(ctx, w.cfg.Concurrency, limiter, cities, func(city City) {
+		_, err := w.weatherService.GetConditionsByCity(ctx, city.Name, city.State)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.WarnContext(ctx, "Pré-aquecimento do clima falhou", "city", city.Name, "state", city.State, "error", err)
+			result.Failed++
+			return
+		}
+		result.Cities++
+	})
+
+	w.mu.Lock()
+	w.warmed = cities
+	w.mu.Unlock()
+
+	result.Duration = time.Since(start)
+	return result, ctx.Err()
+}
+
+// Start executa o pré-aquecimento em segundo plano, limitado por Timeout, e depois inicia a atualização
+// periódica; o servidor já atende enquanto isso e Ready indica quando a prontidão pode ser reportada
+func (w *Warmer) Start(ctx context.Context) {
+	go func() {
+		runCtx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+		result, err := w.Run(runCtx)
+		cancel()
+		if err != nil {
+			slog.Warn("Pré-aquecimento incompleto", "error", err)
+		}
+		slog.Info("Pré-aquecimento concluído", "ceps", result.CEPs, "cities", result.Cities,
+			"duration", result.Duration.Round(time.Millisecond), "failed", result.Failed)
+		w.StartRefresh(ctx)
+	}()
+}
+
+// Refresh atualiza o clima das cidades mais consultadas (ou das pré-aquecidas, sem estatísticas)
+// Entradas observadas há menos de RefreshInterval são mantidas
+func (w *Warmer) Refresh(ctx context.Context) Result {
+	start := time.Now()
+
+	cities := w.stats.TopCities(w.cfg.RefreshTop)
+	if len(cities) == 0 {
+		w.mu.Lock()
+		cities = append(cities, w.warmed...)
+		w.mu.Unlock()
+		if w.cfg.RefreshTop > 0 && len(cities) > w.cfg.RefreshTop {
+			cities = cities[:w.cfg.RefreshTop]
+		}
+	}
+
+	limiter := newRateLimiter(w.cfg.Rate)
+	defer limiter.stop()
+
+	var mu sync.Mutex
+	var result Result
+	forEach(ctx, w.cfg.Concurrency, limiter, cities, func(city City) {
+		_, err := w.weatherService.GetFreshConditionsByCity(ctx, city.Name, city.State, w.cfg.RefreshInterval)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.WarnContext(ctx, "Atualização do clima falhou", "city", city.Name, "state", city.State, "error", err)
+			result.Failed++
+			return
+		}
+		result.Cities++
+	})
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// StartRefresh atualiza periodicamente as cidades mais consultadas e grava as estatísticas até ctx ser cancelado
+func (w *Warmer) StartRefresh(ctx context.Context) {
+	if w.cfg.RefreshInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.cfg.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result := w.Refresh(ctx)
+				slog.Info("Atualização do cache concluída", "cities", result.Cities, "duration", result.Duration.Round(time.Millisecond), "failed", result.Failed)
+				if err := w.SaveStats(); err != nil {
+					slog.Warn("Erro ao gravar estatísticas de acesso", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// SaveStats grava as estatísticas de acesso em WARMUP_STATS_FILE (sem arquivo configurado não faz nada)
+func (w *Warmer) SaveStats() error {
+	if w.cfg.StatsFile == "" || w.stats == nil {
+		return nil
+	}
+	return w.stats.Save(w.cfg.StatsFile)
+}
+
+// rateLimiter libera no máximo rate operações por segundo
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// newRateLimiter cria o limitador (rate <= 0 não limita)
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / rate))}
+}
+
+// wait bloqueia até a próxima liberação ou o cancelamento de ctx
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.ticker == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.ticker.C:
+		return nil
+	}
+}
+
+// stop libera o ticker do limitador
+func (l *rateLimiter) stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
+
+// forEach processa os itens com até concurrency workers, respeitando o limitador e o cancelamento de ctx
+func forEach[T any](ctx context.Context, concurrency int, limiter *rateLimiter, items []T, fn func(T)) {
+	work := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		if limiter.wait(ctx) != nil {
+			break
+		}
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+}
@@ -0,0 +1,275 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weather-cep-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock do CEPService
+type MockCEPService struct {
+	mock.Mock
+}
+
+func (m *MockCEPService) GetLocationByCEP(ctx context.Context, cep string) (*models.LocationInfo, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LocationInfo), args.Error(1)
+}
+
+// Mock do WeatherService
+type MockWeatherService struct {
+	mock.Mock
+}
+
+func (m *MockWeatherService) GetTemperatureByCity(ctx context.Context, city, state string) (*models.TemperatureResponse, error) {
+	args := m.Called(ctx, city, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TemperatureResponse), args.Error(1)
+}
+
+func (m *MockWeatherService) GetConditionsByCity(ctx context.Context, city, state string) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetFreshConditionsByCity(ctx context.Context, city, state string, maxAge time.Duration) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, city, state, maxAge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+func (m *MockWeatherService) GetConditionsByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherConditions, error) {
+	args := m.Called(ctx, lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherConditions), args.Error(1)
+}
+
+// writeCEPFile grava um arquivo de CEPs temporário
+func writeCEPFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ceps.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadCEPFile(t *testing.T) {
+	path := writeCEPFile(t, "cep\n# capitais\n01310-100\n20040020, Rio de Janeiro\n\n70040010 # Brasília\n")
+
+	ceps, err := LoadCEPFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01310100", "20040020", "70040010"}, ceps)
+
+	_, err = LoadCEPFile(writeCEPFile(t, "01310100\n1234\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	_, err = LoadCEPFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestWarmer_Run(t *testing.T) {
+	cepService := new(MockCEPService)
+	cepService.On("GetLocationByCEP", mock.Anything, "01310100").Return(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"}, nil)
+	cepService.On("GetLocationByCEP", mock.Anything, "01001000").Return(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01001-000"}, nil)
+	cepService.On("GetLocationByCEP", mock.Anything, "20040020").Return(&models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"}, nil)
+	cepService.On("GetLocationByCEP", mock.Anything, "99999999").Return(nil, errors.New("can not find zipcode"))
+
+	// Cada cidade é consultada uma única vez, mesmo com vários CEPs
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetConditionsByCity", mock.Anything, "São Paulo", "SP").Return(&models.WeatherConditions{TempC: 22}, nil).Once()
+	weatherService.On("GetConditionsByCity", mock.Anything, "Rio de Janeiro", "RJ").Return(&models.WeatherConditions{TempC: 30}, nil).Once()
+
+	// O arquivo vem primeiro; os mais consultados completam a lista sem repetir CEPs
+	stats := NewStats()
+	stats.Record(&models.LocationInfo{City: "Rio de Janeiro", State: "RJ", CEP: "20040-020"})
+	stats.Record(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"})
+
+	cfg := DefaultConfig()
+	cfg.CEPFile = writeCEPFile(t, "01310100\n01001000\n99999999\n")
+	cfg.Rate = 0
+	warmer := NewWarmer(cepService, weatherService, stats, cfg)
+
+	targets, err := warmer.Targets()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"01310100", "01001000", "99999999", "20040020"}, targets)
+
+	assert.False(t, warmer.Ready())
+	result, err := warmer.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, warmer.Ready())
+	assert.Equal(t, 3, result.CEPs)
+	assert.Equal(t, 2, result.Cities)
+	assert.Equal(t, 1, result.Failed)
+	cepService.AssertExpectations(t)
+	weatherService.AssertExpectations(t)
+}
+
+func TestWarmer_Run_RateLimited(t *testing.T) {
+	var calls atomic.Int32
+	cepService := new(MockCEPService)
+	cepService.On("GetLocationByCEP", mock.Anything, mock.Anything).Run(func(mock.Arguments) { calls.Add(1) }).
+		Return(nil, errors.New("can not find zipcode"))
+
+	cfg := DefaultConfig()
+	cfg.CEPFile = writeCEPFile(t, "01310100\n01001000\n20040020\n70040010\n")
+	cfg.Concurrency = 4
+	cfg.Rate = 20 // uma consulta a cada 50ms
+
+	warmer := NewWarmer(cepService, new(MockWeatherService), nil, cfg)
+	start := time.Now()
+	result, err := warmer.Run(context.Background())
+	require.NoError(t, err)
+
+	// Mesmo com 4 workers as consultas respeitam o limite
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, 4, result.Failed)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestWarmer_Run_Timeout(t *testing.T) {
+	cepService := new(MockCEPService)
+	cepService.On("GetLocationByCEP", mock.Anything, mock.Anything).Return(nil, errors.New("can not find zipcode"))
+
+	cfg := DefaultConfig()
+	cfg.CEPFile = writeCEPFile(t, "01310100\n01001000\n20040020\n70040010\n")
+	cfg.Rate = 1
+
+	warmer := NewWarmer(cepService, new(MockWeatherService), nil, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	result, err := warmer.Run(ctx)
+
+	// O timeout interrompe o pré-aquecimento, mas o serviço fica pronto mesmo assim
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, warmer.Ready())
+	assert.Less(t, result.Failed, 4)
+}
+
+func TestWarmer_Refresh(t *testing.T) {
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetFreshConditionsByCity", mock.Anything, "São Paulo", "SP", 4*time.Minute).Return(&models.WeatherConditions{TempC: 22}, nil).Once()
+	weatherService.On("GetFreshConditionsByCity", mock.Anything, "Recife", "PE", 4*time.Minute).Return(nil, errors.New("error fetching weather data")).Once()
+
+	stats := NewStats()
+	stats.Record(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"})
+	stats.Record(&models.LocationInfo{City: "São Paulo", State: "SP", CEP: "01310-100"})
+	stats.Record(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"})
+	stats.Record(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"})
+	stats.Record(&models.LocationInfo{City: "Manaus", State: "AM", CEP: "69005-070"})
+
+	// Apenas as duas cidades mais consultadas são atualizadas
+	cfg := DefaultConfig()
+	cfg.Rate = 0
+	cfg.RefreshTop = 2
+	warmer := NewWarmer(new(MockCEPService), weatherService, stats, cfg)
+
+	result := warmer.Refresh(context.Background())
+
+	assert.Equal(t, 1, result.Cities)
+	assert.Equal(t, 1, result.Failed)
+	weatherService.AssertExpectations(t)
+}
+
+func TestWarmer_Refresh_WithoutStats(t *testing.T) {
+	cepService := new(MockCEPService)
+	cepService.On("GetLocationByCEP", mock.Anything, "50030230").Return(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"}, nil)
+	weatherService := new(MockWeatherService)
+	weatherService.On("GetConditionsByCity", mock.Anything, "Recife", "PE").Return(&models.WeatherConditions{TempC: 28}, nil)
+	weatherService.On("GetFreshConditionsByCity", mock.Anything, "Recife", "PE", time.Minute).Return(&models.WeatherConditions{TempC: 28}, nil).Once()
+
+	// Sem estatísticas, a atualização usa as cidades do pré-aquecimento
+	cfg := DefaultConfig()
+	cfg.CEPFile = writeCEPFile(t, "50030230\n")
+	cfg.Rate = 0
+	cfg.RefreshInterval = time.Minute
+	warmer := NewWarmer(cepService, weatherService, nil, cfg)
+
+	_, err := warmer.Run(context.Background())
+	require.NoError(t, err)
+	result := warmer.Refresh(context.Background())
+
+	assert.Equal(t, 1, result.Cities)
+	weatherService.AssertExpectations(t)
+}
+
+func TestWarmer_SaveStats(t *testing.T) {
+	stats := NewStats()
+	stats.Record(&models.LocationInfo{City: "Recife", State: "PE", CEP: "50030-230"})
+
+	// Sem WARMUP_STATS_FILE não há o que gravar
+	assert.NoError(t, NewWarmer(new(MockCEPService), new(MockWeatherService), stats, DefaultConfig()).SaveStats())
+
+	cfg := DefaultConfig()
+	cfg.StatsFile = filepath.Join(t.TempDir(), "stats.json")
+	require.NoError(t, NewWarmer(new(MockCEPService), new(MockWeatherService), stats, cfg).SaveStats())
+
+	loaded, err := LoadStats(cfg.StatsFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"50030230"}, loaded.TopCEPs(10))
+}
+
+func TestConfigFrom(t *testing.T) {
+	env := map[string]string{
+		"WARMUP_FILE":             "/data/ceps.txt",
+		"WARMUP_TOP":              "10",
+		"WARMUP_CONCURRENCY":      "8",
+		"WARMUP_RATE":             "2.5",
+		"WARMUP_TIMEOUT":          "1m",
+		"WARMUP_REFRESH_INTERVAL": "0",
+	}
+
+	cfg, err := ConfigFrom(func(key string) string { return env[key] })
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, Config{
+		CEPFile:         "/data/ceps.txt",
+		Top:             10,
+		Concurrency:     8,
+		Rate:            2.5,
+		Timeout:         time.Minute,
+		RefreshInterval: 0,
+		RefreshTop:      20,
+	}, cfg)
+
+	for name, value := range map[string]string{
+		"WARMUP_TOP":              "-1",
+		"WARMUP_CONCURRENCY":      "0",
+		"WARMUP_RATE":             "fast",
+		"WARMUP_REFRESH_INTERVAL": "soon",
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := maps.Clone(env)
+			invalid[name] = value
+			_, err := ConfigFrom(func(key string) string { return invalid[key] })
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfig_Disabled(t *testing.T) {
+	assert.False(t, DefaultConfig().Enabled())
+}